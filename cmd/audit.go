@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/feature"
+)
+
+var auditFormats = []string{"table", "json", "ndjson", "csv"}
+
+func newAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect and verify the audit log",
+		Long: `Inspect and verify .virtualboard/audit.jsonl, the append-only log of feature
+and lock changes. Each entry stores the SHA-256 hash of the entry before it, so
+edited, reordered or deleted entries break the chain. Entries can also be signed
+(see audit.signing in the configuration) so that a rewritten log is detected.`,
+	}
+	cmd.AddCommand(newAuditVerifyCommand())
+	cmd.AddCommand(newAuditLogCommand())
+	return cmd
+}
+
+func newAuditVerifyCommand() *cobra.Command {
+	var requireSigned, showEntries bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the audit log's hash chain and signatures",
+		Long: `Recompute the hash of every audit entry and check that each entry links to
+the one before it. Reports malformed lines, edited entries, reordered or deleted
+entries, and places where a new chain was started, for example after a corrupt
+line. Exits with the validation code when the chain is broken.
+
+Signed entries are checked against the signers' Ed25519 public keys in
+.virtualboard/audit-keys/<signer>.pub (or the directory pinned in
+audit.trusted_keys), or the configured HMAC secret, and attributed to the signer. Signatures that do not verify are problems; with
+--require-signed (or audit.require_signed), so are unsigned entries and
+signers without a trusted key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			keys, err := opts.AuditKeyring()
+			if err != nil {
+				return WrapCLIError(ExitCodeValidation, err)
+			}
+			if !cmd.Flags().Changed("require-signed") {
+				requireSigned = opts.Settings.Audit.RequireSigned
+			}
+			result, err := audit.VerifyWith(feature.NewManager(opts).AuditPath(), audit.VerifyOptions{
+				Keys:          keys,
+				RequireSigned: requireSigned,
+			})
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+
+			if opts.JSONOutput {
+				if result.OK() {
+					return respond(cmd, opts, true, fmt.Sprintf("%d audit entries verified", result.Entries), result)
+				}
+				// The report still goes to stdout; the exit code lets CI fail on it.
+				if err := respond(cmd, opts, false, fmt.Sprintf("audit log has %d problem(s)", len(result.Problems)), result); err != nil {
+					return err
+				}
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("audit log is broken at line %d", result.Problems[0].Line))
+			}
+			out := cmd.OutOrStdout()
+			if showEntries {
+				for _, a := range result.Attributions {
+					fmt.Fprintf(out, "line %d: %s\n", a.Line, describeAttribution(a))
+				}
+			}
+			if !result.OK() {
+				fmt.Fprintf(out, "Audit log has %d problem(s) in %d entries:\n", len(result.Problems), result.Entries)
+				for _, problem := range result.Problems {
+					fmt.Fprintf(out, "  - %s\n", problem)
+				}
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("audit log is broken at line %d", result.Problems[0].Line))
+			}
+			message := fmt.Sprintf("Audit log intact: %d entries verified", result.Entries)
+			if result.Signed > 0 {
+				message += fmt.Sprintf(", %d signed (%s)", result.Signed, signerCounts(result.Attributions))
+			}
+			return respond(cmd, opts, true, message, nil)
+		},
+	}
+
+	cmd.Flags().BoolVar(&requireSigned, "require-signed", false, "Report entries without a verified signature (default: audit.require_signed)")
+	cmd.Flags().BoolVar(&showEntries, "entries", false, "List every entry's actor and signer")
+	return cmd
+}
+
+// describeAttribution renders an entry's actor and signature status, e.g.
+// "alice, signed by alice (ed25519, verified)".
+func describeAttribution(a audit.Attribution) string {
+	if a.Status == audit.SignatureUnsigned {
+		return a.Actor + ", unsigned"
+	}
+	return fmt.Sprintf("%s, signed by %s (%s, %s)", a.Actor, a.Signer, a.Method, a.Status)
+}
+
+// signerCounts summarizes the verified signatures per signer, e.g. "alice: 3, bob: 1".
+func signerCounts(attributions []audit.Attribution) string {
+	counts := map[string]int{}
+	var signers []string
+	for _, a := range attributions {
+		if a.Status != audit.SignatureVerified {
+			continue
+		}
+		if counts[a.Signer] == 0 {
+			signers = append(signers, a.Signer)
+		}
+		counts[a.Signer]++
+	}
+	sort.Strings(signers)
+	parts := make([]string, len(signers))
+	for i, signer := range signers {
+		parts[i] = fmt.Sprintf("%s: %d", signer, counts[signer])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func newAuditLogCommand() *cobra.Command {
+	var filter audit.Filter
+	var since, until string
+	var limit int
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "List audit entries with optional filtering",
+		Long: `List audit entries, oldest first.
+
+Filters accept comma-separated values or can be repeated; values within a filter
+are OR'ed and different filters are AND'ed together. --since and --until take a
+YYYY-MM-DD date or an RFC 3339 timestamp and are inclusive.
+
+Examples:
+  vb audit log --feature FTR-0001
+  vb audit log --feature FTR-0001 --field priority
+  vb audit log --actor alice --action move,delete --since 2026-01-01
+  vb audit log --until 2026-03-31 --format csv > audit.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if !containsString(auditFormats, format) {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("unknown format %s (allowed: %s)", format, strings.Join(auditFormats, ", ")))
+			}
+			if limit < 0 {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("--limit must not be negative"))
+			}
+			if since != "" {
+				if filter.Since, err = audit.ParseTime(since, false); err != nil {
+					return WrapCLIError(ExitCodeValidation, fmt.Errorf("--since: %w", err))
+				}
+			}
+			if until != "" {
+				if filter.Until, err = audit.ParseTime(until, true); err != nil {
+					return WrapCLIError(ExitCodeValidation, fmt.Errorf("--until: %w", err))
+				}
+			}
+
+			entries, err := readAuditEntries(cmd, feature.NewManager(opts).AuditPath())
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			entries = filter.Apply(entries)
+			if limit > 0 && len(entries) > limit {
+				entries = entries[len(entries)-limit:]
+			}
+
+			if opts.JSONOutput {
+				return respond(cmd, opts, true, fmt.Sprintf("%d audit entries", len(entries)), map[string]interface{}{
+					"total":   len(entries),
+					"entries": entries,
+				})
+			}
+			return writeAuditEntries(cmd.OutOrStdout(), format, entries)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&filter.FeatureIDs, "feature", nil, "Filter by feature ID")
+	cmd.Flags().StringSliceVar(&filter.Actors, "actor", nil, "Filter by actor")
+	cmd.Flags().StringSliceVar(&filter.Actions, "action", nil, "Filter by action (e.g. create, update, move, delete, lock)")
+	cmd.Flags().StringSliceVar(&filter.Fields, "field", nil, "Only entries that changed this frontmatter field")
+	cmd.Flags().StringVar(&since, "since", "", "Only entries at or after this date or timestamp")
+	cmd.Flags().StringVar(&until, "until", "", "Only entries at or before this date or timestamp")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Show only the newest N matching entries (0 for all)")
+	cmd.Flags().StringVar(&format, "format", "table", fmt.Sprintf("Output format: %s", strings.Join(auditFormats, ", ")))
+	return cmd
+}
+
+// auditDetails describes an entry by its change set, falling back to the
+// free-form details of entries that have none.
+func auditDetails(e audit.Entry) string {
+	if !e.Changes.Empty() {
+		return e.Changes.String()
+	}
+	return e.Details
+}
+
+func writeAuditEntries(w io.Writer, format string, entries []audit.Entry) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"timestamp", "action", "actor", "feature_id", "details", "changes", "prev_hash", "entry_hash"}); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			changes := ""
+			if e.Changes != nil {
+				data, err := json.Marshal(e.Changes)
+				if err != nil {
+					return err
+				}
+				changes = string(data)
+			}
+			if err := cw.Write([]string{e.Timestamp, e.Action, e.Actor, e.FeatureID, e.Details, changes, e.PrevHash, e.EntryHash}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		if len(entries) == 0 {
+			fmt.Fprintln(w, "No audit entries found")
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIMESTAMP\tACTION\tACTOR\tFEATURE\tDETAILS")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Timestamp, e.Action, e.Actor, e.FeatureID, auditDetails(e))
+		}
+		return tw.Flush()
+	}
+}
+
+// readAuditEntries reads the audit log, warning on stderr about malformed lines it
+// skipped; vb audit verify reports them in detail.
+func readAuditEntries(cmd *cobra.Command, path string) ([]audit.Entry, error) {
+	entries, skipped, err := audit.ReadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		lines := make([]string, len(skipped))
+		for i, no := range skipped {
+			lines[i] = strconv.Itoa(no)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %d malformed audit line(s): %s (run vb audit verify for details)\n", len(skipped), strings.Join(lines, ", "))
+	}
+	return entries, nil
+}
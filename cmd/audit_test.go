@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestAuditVerifyCommand(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	feat, err := mgr.CreateFeature("Audited", nil)
+	if err != nil {
+		t.Fatalf("create feature failed: %v", err)
+	}
+	// vb move logs through both the lock and the feature manager.
+	moveCmd := newMoveCommand()
+	moveCmd.SetOut(buf)
+	moveCmd.SetArgs([]string{feat.FrontMatter.ID, "in-progress"})
+	if err := moveCmd.Execute(); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+
+	out, err := runCommand(t, newAuditCommand(), "verify")
+	if err != nil || !strings.HasPrefix(out, "Audit log intact: ") {
+		t.Fatalf("expected an intact chain: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(mgr.AuditPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	tampered := strings.Join(append([]string{lines[1], lines[0]}, lines[2:]...), "")
+	if err := os.WriteFile(mgr.AuditPath(), []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCommand(t, newAuditCommand(), "verify")
+	if ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "broken at line 1") ||
+		!strings.Contains(out, "  - line 1: follows line 2 but is the first entry\n") {
+		t.Fatalf("expected a broken chain: %v\n%s", err, out)
+	}
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newAuditCommand(), "verify")
+	if ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected a JSON report and the validation exit code, got %v", err)
+	}
+	var payload struct {
+		Success bool               `json:"success"`
+		Data    audit.Verification `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if payload.Success || payload.Data.Problems[0].Kind != audit.ProblemReordered {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	if err := os.Mkdir(mgr.AuditPath()+".d", 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(mgr.AuditPath()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(mgr.AuditPath()+".d", mgr.AuditPath()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, newAuditCommand(), "verify"); ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected a filesystem error, got %v", err)
+	}
+}
+
+func TestAuditVerifySignatures(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	t.Setenv(config.AuditKeyEnv, base64.StdEncoding.EncodeToString(key.Seed()))
+	opts.Settings.Audit.Signing = config.SigningSettings{Method: config.SigningEd25519, Signer: "alice"}
+	fix.WriteFile(t, "audit-keys/alice.pub", []byte(base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))))
+
+	mgr := feature.NewManager(opts)
+	if _, err := mgr.CreateFeature("Signed", nil); err != nil {
+		t.Fatalf("create feature failed: %v", err)
+	}
+	// Creating a feature also logs its lock and unlock.
+	out, err := runCommand(t, newAuditCommand(), "verify", "--entries")
+	if err != nil || !strings.Contains(out, "line 1: ") || !strings.Contains(out, ", signed by alice (ed25519, verified)\n") ||
+		!strings.Contains(out, "Audit log intact: 3 entries verified, 3 signed (alice: 3)") {
+		t.Fatalf("expected a verified signature: %v\n%s", err, out)
+	}
+
+	opts.Settings.Audit.Signing = config.SigningSettings{}
+	if _, err := feature.NewManager(opts).CreateFeature("Unsigned", nil); err != nil {
+		t.Fatalf("create feature failed: %v", err)
+	}
+	if out, err := runCommand(t, newAuditCommand(), "verify"); err != nil {
+		t.Fatalf("unsigned entries should pass unless required: %v\n%s", err, out)
+	}
+	opts.Settings.Audit.RequireSigned = true
+	out, err = runCommand(t, newAuditCommand(), "verify")
+	if ExitCode(err) != ExitCodeValidation || !strings.Contains(out, "  - line 4: entry is not signed\n") {
+		t.Fatalf("expected the unsigned entry to be reported: %v\n%s", err, out)
+	}
+	if out, err := runCommand(t, newAuditCommand(), "verify", "--require-signed=false"); err != nil {
+		t.Fatalf("the flag should override the setting: %v\n%s", err, out)
+	}
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newAuditCommand(), "verify")
+	if ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected a JSON report and the validation exit code, got %v", err)
+	}
+	var payload struct {
+		Data audit.Verification `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if payload.Data.Signed != 3 || len(payload.Data.Attributions) != 6 || payload.Data.Attributions[3].Status != audit.SignatureUnsigned {
+		t.Fatalf("unexpected payload %+v", payload.Data)
+	}
+	if out, err := runCommand(t, newAuditCommand(), "verify", "--require-signed=false"); err != nil || !strings.Contains(out, `"success": true`) {
+		t.Fatalf("expected a passing JSON report: %v\n%s", err, out)
+	}
+
+	fix.WriteFile(t, "audit-keys/bob.pub", []byte("garbage"))
+	if _, err := runCommand(t, newAuditCommand(), "verify"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected an invalid key error, got %v", err)
+	}
+}
+
+func TestAuditLogCommand(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	lines := []string{
+		`{"timestamp":"2026-01-01T09:00:00Z","action":"create","actor":"alice","feature_id":"FTR-0001","details":"title=One","prev_hash":"","entry_hash":"a"}`,
+		`{"timestamp":"2026-01-02T09:00:00Z","action":"move","actor":"bob","feature_id":"FTR-0001","details":"status=done, \"quoted\"","prev_hash":"a","entry_hash":"b"}`,
+		`{"timestamp":"2026-01-03T09:00:00Z","action":"create","actor":"alice","feature_id":"FTR-0002","prev_hash":"b","entry_hash":"c"}`,
+		`{"timestamp":"2026-01-04T09:00:00Z","action":"update","actor":"bob","feature_id":"FTR-0002","details":"priority: low -> high","changes":{"fields":[{"field":"priority","old":"low","new":"high"}],"sections":[{"section":"Goal","change":"edited"}]},"prev_hash":"c","entry_hash":"d"}`,
+	}
+	fix.WriteFile(t, "audit.jsonl", []byte(strings.Join(lines, "\n")+"\n"))
+
+	out, err := runCommand(t, newAuditCommand(), "log", "--actor", "alice")
+	if err != nil || !strings.HasPrefix(out, "TIMESTAMP") || strings.Count(out, "\n") != 3 || strings.Contains(out, "bob") {
+		t.Fatalf("unexpected table: %v\n%s", err, out)
+	}
+
+	out, err = runCommand(t, newAuditCommand(), "log", "--field", "priority")
+	if err != nil || strings.Count(out, "\n") != 2 || !strings.Contains(out, "priority: low -> high; sections edited: Goal\n") {
+		t.Fatalf("expected the priority change: %v\n%s", err, out)
+	}
+	out, err = runCommand(t, newAuditCommand(), "log", "--field", "priority", "--format", "csv")
+	if err != nil || !strings.Contains(out, `,"{""fields"":[{""field"":""priority"",""old"":""low"",""new"":""high""}],`) {
+		t.Fatalf("expected the change set in CSV: %v\n%s", err, out)
+	}
+
+	out, err = runCommand(t, newAuditCommand(), "log", "--feature", "ftr-0001", "--since", "2026-01-02", "--format", "csv")
+	if err != nil || out != "timestamp,action,actor,feature_id,details,changes,prev_hash,entry_hash\n"+
+		`2026-01-02T09:00:00Z,move,bob,FTR-0001,"status=done, ""quoted""",,a,b`+"\n" {
+		t.Fatalf("unexpected csv: %v\n%s", err, out)
+	}
+
+	out, err = runCommand(t, newAuditCommand(), "log", "--format", "ndjson", "--limit", "2", "--until", "2026-01-03T08:00:00Z")
+	if err != nil || strings.Count(out, "\n") != 2 || !strings.Contains(out, `"entry_hash":"a"`) {
+		t.Fatalf("unexpected ndjson: %v\n%s", err, out)
+	}
+
+	out, err = runCommand(t, newAuditCommand(), "log", "--format", "json", "--action", "delete")
+	if err != nil || strings.TrimSpace(out) != "[]" {
+		t.Fatalf("expected an empty JSON array: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newAuditCommand(), "log", "--action", "delete"); err != nil || out != "No audit entries found\n" {
+		t.Fatalf("expected no entries: %v\n%s", err, out)
+	}
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newAuditCommand(), "log", "--limit", "1")
+	if err != nil {
+		t.Fatalf("json log failed: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Total   int           `json:"total"`
+			Entries []audit.Entry `json:"entries"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if payload.Data.Total != 1 || payload.Data.Entries[0].FeatureID != "FTR-0002" || payload.Data.Entries[0].Changes.Fields[0].Old != "low" {
+		t.Fatalf("expected the newest entry, got %+v", payload.Data)
+	}
+
+	for _, args := range [][]string{
+		{"log", "--format", "xml"},
+		{"log", "--limit", "-1"},
+		{"log", "--since", "yesterday"},
+		{"log", "--until", "01/02/2026"},
+	} {
+		if _, err := runCommand(t, newAuditCommand(), args...); ExitCode(err) != ExitCodeValidation {
+			t.Fatalf("%v: expected a validation error, got %v", args, err)
+		}
+	}
+
+	fix.WriteFile(t, "audit.jsonl", []byte("{broken\n"))
+	if out, err := runCommand(t, newAuditCommand(), "log"); err != nil || !strings.HasPrefix(out, "Skipped 1 malformed audit line(s): 1 (run vb audit verify for details)\n") || !strings.Contains(out, `"total": 0`) {
+		t.Fatalf("expected the malformed line to be skipped: %v\n%s", err, out)
+	}
+	if err := os.Remove(fix.Path("audit.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(fix.Path("audit.jsonl"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, newAuditCommand(), "log"); ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected a filesystem error, got %v", err)
+	}
+}
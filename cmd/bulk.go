@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/patch"
+)
+
+// selector picks the features a mutating command applies to when no single ID is
+// given: an explicit --ids list, IDs read from --stdin, or a --where query.
+type selector struct {
+	ids   []string
+	stdin bool
+	where []string
+}
+
+func (s *selector) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.ids, "ids", nil, "Apply to these feature IDs (comma-separated or repeated)")
+	cmd.Flags().BoolVar(&s.stdin, "stdin", false, "Read feature IDs from stdin (first word of each line)")
+	cmd.Flags().StringArrayVar(&s.where, "where", nil, "Apply to features matching a query expression (repeatable, AND'ed)")
+}
+
+func (s *selector) active() bool {
+	return len(s.ids) > 0 || s.stdin || len(s.where) > 0
+}
+
+// resolve returns the selected IDs without duplicates. Explicit IDs keep their order;
+// when combined with --where, the filter narrows them down.
+func (s *selector) resolve(cmd *cobra.Command, opts *config.Options, mgr *feature.Manager) ([]string, error) {
+	ids := append([]string{}, s.ids...)
+	if s.stdin {
+		scanner := bufio.NewScanner(cmd.InOrStdin())
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			ids = append(ids, strings.Fields(line)[0])
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, WrapCLIError(ExitCodeFilesystem, fmt.Errorf("failed to read IDs from stdin: %w", err))
+		}
+	}
+	explicit := len(s.ids) > 0 || s.stdin
+
+	if len(s.where) > 0 {
+		q, err := compileQuery(mgr, s.where)
+		if err != nil {
+			return nil, err
+		}
+		all, err := mgr.List()
+		if err != nil {
+			return nil, WrapCLIError(ExitCodeFilesystem, err)
+		}
+		matched := map[string]bool{}
+		matchedIDs := []string{}
+		for _, feat := range applyQuery(opts, mgr, q, all) {
+			matched[strings.ToUpper(feat.FrontMatter.ID)] = true
+			matchedIDs = append(matchedIDs, feat.FrontMatter.ID)
+		}
+		if !explicit {
+			ids = matchedIDs
+		} else {
+			narrowed := []string{}
+			for _, id := range ids {
+				if matched[strings.ToUpper(strings.TrimSpace(id))] {
+					narrowed = append(narrowed, id)
+				}
+			}
+			ids = narrowed
+		}
+	}
+
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		key := strings.ToUpper(id)
+		if id == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// bulkEntry is the serialised form of a BulkResult.
+type bulkEntry struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// reportBulk prints per-feature results and a summary, followed in dry-run mode by
+// the planned patches, returning an error carrying the exit code of the first
+// failure when any feature failed.
+func reportBulk(cmd *cobra.Command, opts *config.Options, verb string, results []feature.BulkResult, patches []patch.Patch) error {
+	entries := make([]bulkEntry, 0, len(results))
+	failed := 0
+	var firstErr error
+	for _, result := range results {
+		entry := bulkEntry{ID: result.ID, Success: result.Success, Message: result.Message}
+		if result.Path != "" {
+			if rel, err := filepath.Rel(opts.RootDir, result.Path); err == nil {
+				entry.Path = filepath.ToSlash(rel)
+			} else {
+				entry.Path = result.Path
+			}
+		}
+		if !result.Success {
+			failed++
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	summary := fmt.Sprintf("%s %d of %d feature(s)", verb, len(results)-failed, len(results))
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+
+	if opts.JSONOutput {
+		data := map[string]interface{}{
+			"results": entries,
+			"summary": map[string]int{
+				"total":     len(results),
+				"succeeded": len(results) - failed,
+				"failed":    failed,
+			},
+		}
+		addPatches(opts, data, patches)
+		if err := respond(cmd, opts, failed == 0, summary, data); err != nil {
+			return err
+		}
+	} else {
+		out := cmd.OutOrStdout()
+		for _, entry := range entries {
+			mark := "ok"
+			if !entry.Success {
+				mark = "FAILED"
+			}
+			fmt.Fprintf(out, "%-6s %s: %s\n", mark, entry.ID, entry.Message)
+		}
+		fmt.Fprintln(out, summary)
+		printPatches(cmd, opts, patches)
+	}
+
+	if failed > 0 {
+		return WrapCLIError(featureExitCode(firstErr), fmt.Errorf("%d of %d feature(s) failed", failed, len(results)))
+	}
+	return nil
+}
+
+// featureExitCode maps feature manager errors to exit codes.
+func featureExitCode(err error) int {
+	var cliErr *CLIError
+	switch {
+	case errors.As(err, &cliErr):
+		return cliErr.Code
+	case errors.Is(err, feature.ErrNotFound):
+		return ExitCodeNotFound
+	case errors.Is(err, feature.ErrInvalidTransition):
+		return ExitCodeInvalidTransition
+	case errors.Is(err, feature.ErrDependencyBlocked):
+		return ExitCodeDependency
+	default:
+		return ExitCodeFilesystem
+	}
+}
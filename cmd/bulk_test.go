@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestMoveCommandBulkWhere(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, true, false, false)
+	mgr := feature.NewManager(opts)
+
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "review", "One")
+	buildFeatureFile(t, fix, mgr, "FTR-0002", "review", "Two")
+	buildFeatureFile(t, fix, mgr, "FTR-0003", "backlog", "Three")
+
+	cmd := newMoveCommand()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--where", "status=review", "done", "carol"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("bulk move failed: %v", err)
+	}
+
+	var payload struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Results []bulkEntry    `json:"results"`
+			Summary map[string]int `json:"summary"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	if !payload.Success || payload.Data.Summary["succeeded"] != 2 || len(payload.Data.Results) != 2 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if payload.Data.Results[0].ID != "FTR-0001" || !strings.HasPrefix(payload.Data.Results[0].Path, "features/done/") {
+		t.Fatalf("unexpected result: %+v", payload.Data.Results[0])
+	}
+	for _, id := range []string{"FTR-0001", "FTR-0002"} {
+		feat, err := mgr.LoadByID(id)
+		if err != nil || feat.FrontMatter.Status != "done" || feat.FrontMatter.Owner != "carol" {
+			t.Fatalf("expected %s to be done and owned by carol: %v %+v", id, err, feat)
+		}
+	}
+	if feat, _ := mgr.LoadByID("FTR-0003"); feat.FrontMatter.Status != "backlog" {
+		t.Fatalf("unselected feature should not move")
+	}
+}
+
+func TestMoveCommandBulkReportsFailures(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "One")
+	buildFeatureFile(t, fix, mgr, "FTR-0002", "done", "Two")
+
+	cmd := newMoveCommand()
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader("FTR-0001\tbacklog\towner\tOne\n\n# comment\nFTR-0002\nFTR-0001\n"))
+	cmd.SetArgs([]string{"--stdin", "in-progress"})
+	err := cmd.Execute()
+	if err == nil || ExitCode(err) != ExitCodeInvalidTransition {
+		t.Fatalf("expected invalid transition exit code, got %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "ok     FTR-0001: Moved FTR-0001 to in-progress") || !strings.Contains(out, "FAILED FTR-0002:") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if !strings.Contains(out, "Moved 1 of 2 feature(s), 1 failed") {
+		t.Fatalf("expected summary in output:\n%s", out)
+	}
+	if feat, _ := mgr.LoadByID("FTR-0001"); feat.FrontMatter.Status != "in-progress" {
+		t.Fatalf("valid feature should still move")
+	}
+}
+
+func TestMoveCommandBulkArgs(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	_, buf := setupOptions(t, fix, false, false, false)
+
+	for _, args := range [][]string{{"--ids", "FTR-0001"}, {"--ids", "FTR-0001", "done", "x", "y"}} {
+		cmd := newMoveCommand()
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("expected argument error for %v", args)
+		}
+	}
+
+	cmd := newMoveCommand()
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--where", "colour=red", "done"})
+	if err := cmd.Execute(); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error for unknown filter, got %v", err)
+	}
+}
+
+func TestUpdateCommandBulkLabels(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	for i, id := range []string{"FTR-0001", "FTR-0002", "FTR-0003"} {
+		feat := buildFeatureFile(t, fix, mgr, id, "backlog", fmt.Sprintf("Feature %d", i))
+		feat.FrontMatter.Labels = []string{"old"}
+		if i < 2 {
+			feat.FrontMatter.Epic = "checkout"
+		}
+		if err := mgr.Save(feat); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+	}
+
+	cmd := newUpdateCommand()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--where", "epic=checkout", "--add-label", "q3", "--remove-label", "OLD"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("bulk update failed: %v\n%s", err, buf.String())
+	}
+	for _, id := range []string{"FTR-0001", "FTR-0002"} {
+		feat, _ := mgr.LoadByID(id)
+		if strings.Join(feat.FrontMatter.Labels, ",") != "q3" {
+			t.Fatalf("unexpected labels for %s: %v", id, feat.FrontMatter.Labels)
+		}
+	}
+	if feat, _ := mgr.LoadByID("FTR-0003"); strings.Join(feat.FrontMatter.Labels, ",") != "old" {
+		t.Fatalf("unselected feature should not change: %v", feat.FrontMatter.Labels)
+	}
+
+	buf.Reset()
+	cmd = newUpdateCommand()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--ids", "FTR-0001,FTR-0009", "--field", "bogus=1"})
+	err := cmd.Execute()
+	if err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "FAILED FTR-0009:") {
+		t.Fatalf("expected missing feature to be reported:\n%s", buf.String())
+	}
+
+	cmd = newUpdateCommand()
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--ids", "FTR-0001", "FTR-0002", "--add-label", "x"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected error when combining an id with a selector")
+	}
+}
+
+func TestDeleteCommandBulk(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "One")
+	buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", "Two")
+	buildFeatureFile(t, fix, mgr, "FTR-0003", "review", "Three")
+
+	cmd := newDeleteCommand()
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader("no\n"))
+	cmd.SetArgs([]string{"--where", "status=backlog"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cancelled delete failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Delete 2 feature(s): FTR-0001, FTR-0002?") || !strings.Contains(buf.String(), "Deletion cancelled") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	cmd = newDeleteCommand()
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader("FTR-0001\n"))
+	cmd.SetArgs([]string{"--stdin"})
+	if err := cmd.Execute(); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected --stdin without --force to be rejected, got %v", err)
+	}
+
+	cmd = newDeleteCommand()
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SetArgs([]string{"--where", "status=backlog"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected confirmation read error")
+	}
+
+	buf.Reset()
+	cmd = newDeleteCommand()
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader("yes\n"))
+	cmd.SetArgs([]string{"--ids", "FTR-0001,FTR-0002,FTR-0003", "--where", "status=backlog"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("bulk delete failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Deleted 2 of 2 feature(s)") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	for id, gone := range map[string]bool{"FTR-0001": true, "FTR-0002": true, "FTR-0003": false} {
+		_, err := mgr.LoadByID(id)
+		if gone != errors.Is(err, feature.ErrNotFound) {
+			t.Fatalf("unexpected state for %s: %v", id, err)
+		}
+	}
+}
+
+func TestUpdateCommandBulkDryRun(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, true)
+	mgr := feature.NewManager(opts)
+
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "One")
+	buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", "Two")
+
+	cmd := newUpdateCommand()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--ids", "FTR-0001,FTR-0002", "--field", "priority=high"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("dry-run bulk update failed: %v", err)
+	}
+	out := buf.String()
+	summary := strings.Index(out, "Updated 2 of 2 feature(s)")
+	diff := strings.Index(out, "diff --git a/.virtualboard/features/backlog/FTR-0001-one.md")
+	if summary < 0 || diff < summary || strings.Count(out, "+priority: high\n") != 2 {
+		t.Fatalf("expected the summary followed by both diffs:\n%s", out)
+	}
+	if feat, _ := mgr.LoadByID("FTR-0001"); feat.FrontMatter.Priority == "high" {
+		t.Fatalf("dry-run should not change files")
+	}
+
+	opts.JSONOutput = true
+	buf.Reset()
+	cmd = newUpdateCommand()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--ids", "FTR-0001", "--field", "priority=high"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("dry-run bulk update failed: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Patches []patch.Patch `json:"patches"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	if len(payload.Data.Patches) != 1 || payload.Data.Patches[0].Op != patch.Modify {
+		t.Fatalf("expected one modify patch, got %+v", payload.Data.Patches)
+	}
+}
+
+func TestFeatureExitCode(t *testing.T) {
+	cases := map[error]int{
+		WrapCLIError(ExitCodeValidation, errors.New("x")): ExitCodeValidation,
+		feature.ErrNotFound:          ExitCodeNotFound,
+		feature.ErrInvalidTransition: ExitCodeInvalidTransition,
+		feature.ErrDependencyBlocked: ExitCodeDependency,
+		errors.New("disk"):           ExitCodeFilesystem,
+	}
+	for err, want := range cases {
+		if got := featureExitCode(err); got != want {
+			t.Fatalf("%v: expected %d, got %d", err, want, got)
+		}
+	}
+}
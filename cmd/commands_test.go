@@ -2,15 +2,19 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+
 	"github.com/virtualboard/vb-cli/internal/config"
 	"github.com/virtualboard/vb-cli/internal/feature"
 	"github.com/virtualboard/vb-cli/internal/indexer"
+	"github.com/virtualboard/vb-cli/internal/patch"
 	"github.com/virtualboard/vb-cli/internal/testutil"
 	"github.com/virtualboard/vb-cli/internal/util"
 	"github.com/virtualboard/vb-cli/internal/validator"
@@ -25,6 +29,20 @@ func setupOptions(t *testing.T, fix *testutil.Fixture, jsonOut, verbose, dry boo
 	return opts, &buf
 }
 
+// runCommand executes cmd with args and returns everything it wrote to stdout and
+// stderr. Usage and error printing are silenced so output holds only the command's own.
+func runCommand(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
 func TestNewAndUpdateCommands(t *testing.T) {
 	fix := testutil.NewFixture(t)
 	opts, buf := setupOptions(t, fix, false, false, false)
@@ -140,6 +158,87 @@ func TestDeleteAndTemplateCommands(t *testing.T) {
 	}
 }
 
+func TestDryRunCommandsShowPatches(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	feat, err := mgr.CreateFeature("Dry Feature", nil)
+	if err != nil {
+		t.Fatalf("create feature failed: %v", err)
+	}
+	path := feat.Path
+
+	opts.DryRun = true
+	moveCmd := newMoveCommand()
+	moveCmd.SetOut(buf)
+	moveCmd.SetErr(buf)
+	moveCmd.SetArgs([]string{feat.FrontMatter.ID, "in-progress"})
+	if err := moveCmd.Execute(); err != nil {
+		t.Fatalf("dry-run move failed: %v", err)
+	}
+	for _, want := range []string{
+		"rename from .virtualboard/features/backlog/FTR-0001-dry-feature.md\n",
+		"rename to .virtualboard/features/in-progress/FTR-0001-dry-feature.md\n",
+		"-status: backlog\n+status: in-progress\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected %q in:\n%s", want, buf.String())
+		}
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("dry-run move should keep the file: %v", err)
+	}
+
+	buf.Reset()
+	newCmd := newNewCommand()
+	newCmd.SetOut(buf)
+	newCmd.SetErr(buf)
+	newCmd.SetArgs([]string{"Second Feature"})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("dry-run new failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "new file mode 100644\n--- /dev/null\n+++ b/.virtualboard/features/backlog/FTR-0002-second-feature.md\n") {
+		t.Fatalf("expected a new file diff:\n%s", buf.String())
+	}
+
+	opts.JSONOutput = true
+	buf.Reset()
+	deleteCmd := newDeleteCommand()
+	deleteCmd.SetOut(buf)
+	deleteCmd.SetErr(buf)
+	deleteCmd.SetArgs([]string{feat.FrontMatter.ID, "--force"})
+	if err := deleteCmd.Execute(); err != nil {
+		t.Fatalf("dry-run delete failed: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Patches []patch.Patch `json:"patches"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	if len(payload.Data.Patches) != 1 || payload.Data.Patches[0].Op != patch.Delete ||
+		payload.Data.Patches[0].Path != ".virtualboard/features/backlog/FTR-0001-dry-feature.md" {
+		t.Fatalf("expected a delete patch, got %+v", payload.Data.Patches)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("dry-run delete should keep the file: %v", err)
+	}
+
+	opts.DryRun = false
+	buf.Reset()
+	deleteCmd = newDeleteCommand()
+	deleteCmd.SetOut(buf)
+	deleteCmd.SetArgs([]string{feat.FrontMatter.ID, "--force"})
+	if err := deleteCmd.Execute(); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "patches") {
+		t.Fatalf("patches should only be reported in dry-run: %s", buf.String())
+	}
+}
+
 func TestValidateCommand(t *testing.T) {
 	fix := testutil.NewFixture(t)
 	opts, buf := setupOptions(t, fix, false, false, false)
@@ -149,7 +248,7 @@ func TestValidateCommand(t *testing.T) {
 	if err != nil {
 		t.Fatalf("validator init failed: %v", err)
 	}
-	statusDir := filepath.Join(mgr.FeaturesDir(), filepath.Base(feature.DirectoryForStatus("backlog")))
+	statusDir := filepath.Join(mgr.FeaturesDir(), filepath.Base(mgr.Workflow().DirectoryForStatus("backlog")))
 	if exp, act := statusDir, filepath.Dir(feat.Path); exp != act {
 		t.Fatalf("expected dir %s, got %s", exp, act)
 	}
@@ -324,6 +423,38 @@ func TestRegisterCommandsIdempotent(t *testing.T) {
 	}
 }
 
+func TestRootCommandWithInvalidConfig(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "workflow.yaml", []byte("statuses: []\n"))
+	config.SetCurrent(nil)
+	t.Cleanup(func() {
+		config.SetCurrent(nil)
+		flagRoot = ""
+	})
+
+	root := RootCommand()
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"version"}, true},
+		{[]string{"where"}, true},
+		{[]string{"list"}, false},
+	} {
+		config.SetCurrent(nil)
+		out, err := runCommand(t, root, append(tc.args, "--root", fix.Root)...)
+		if (err == nil) != tc.ok {
+			t.Fatalf("vb %v: unexpected result %v\n%s", tc.args, err, out)
+		}
+		if !tc.ok && !strings.Contains(err.Error(), "workflow") {
+			t.Fatalf("vb %v: expected workflow error, got %v", tc.args, err)
+		}
+		if tc.args[0] == "where" && !strings.Contains(out, "Error:") {
+			t.Fatalf("where should report the config error:\n%s", out)
+		}
+	}
+}
+
 func TestVersionCommand(t *testing.T) {
 	fix := testutil.NewFixture(t)
 	_, buf := setupOptions(t, fix, false, false, false)
@@ -801,7 +932,7 @@ Schema details.`
 
 func buildFeatureFile(t *testing.T, fix *testutil.Fixture, mgr *feature.Manager, id, status, title string) *feature.Feature {
 	t.Helper()
-	statusDir := filepath.Join(mgr.FeaturesDir(), filepath.Base(feature.DirectoryForStatus(status)))
+	statusDir := filepath.Join(mgr.FeaturesDir(), filepath.Base(mgr.Workflow().DirectoryForStatus(status)))
 	feat := &feature.Feature{
 		Path: filepath.Join(statusDir, fmt.Sprintf("%s-%s.md", id, util.Slugify(title))),
 		FrontMatter: feature.FrontMatter{
@@ -828,3 +959,169 @@ func buildFeatureFile(t *testing.T, fix *testutil.Fixture, mgr *feature.Manager,
 	fix.WriteFile(t, rel, data)
 	return feat
 }
+
+// seedFeature describes a feature written by seedFeatures. Empty fields keep the
+// defaults of buildFeatureFile; an empty title becomes "Feature <ID>".
+type seedFeature struct {
+	ID, Status, Title string
+	Owner, Epic       string
+	Updated, Body     string
+	Labels, Deps      []string
+}
+
+// seedFeatures writes the features into the fixture's workspace.
+func seedFeatures(t *testing.T, fix *testutil.Fixture, mgr *feature.Manager, feats ...seedFeature) {
+	t.Helper()
+	for _, s := range feats {
+		title := s.Title
+		if title == "" {
+			title = "Feature " + s.ID
+		}
+		feat := buildFeatureFile(t, fix, mgr, s.ID, s.Status, title)
+		if s.Owner != "" {
+			feat.FrontMatter.Owner = s.Owner
+		}
+		if s.Updated != "" {
+			feat.FrontMatter.Updated = s.Updated
+		}
+		if s.Body != "" {
+			feat.Body = s.Body
+		}
+		feat.FrontMatter.Epic = s.Epic
+		feat.FrontMatter.Labels = s.Labels
+		feat.FrontMatter.Dependencies = s.Deps
+		if err := mgr.Save(feat); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+	}
+}
+
+func TestCommandsUseConfigSettings(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "config.yaml", []byte("owner: alice\nlock:\n  ttl: 90\nindex:\n  format: json\n  output: features/INDEX.json\n"))
+	// The template source is user-only, so it comes from the environment.
+	t.Setenv("VB_TEMPLATE_SOURCE", "https://example.com/custom.zip")
+	opts, buf := setupOptions(t, fix, false, false, false)
+
+	newCmd := newNewCommand()
+	newCmd.SetOut(buf)
+	newCmd.SetArgs([]string{"Configured Owner"})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+	feat, err := feature.NewManager(opts).LoadByID("FTR-0001")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if feat.FrontMatter.Owner != "alice" {
+		t.Fatalf("expected configured owner, got %s", feat.FrontMatter.Owner)
+	}
+
+	lockCmd := newLockCommand()
+	lockCmd.SetOut(buf)
+	lockCmd.SetArgs([]string{"FTR-0001", "--owner", "tester"})
+	if err := lockCmd.Execute(); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "ttl 90m") {
+		t.Fatalf("expected configured ttl, got %s", buf.String())
+	}
+
+	indexCmd := newIndexCommand()
+	indexCmd.SetOut(buf)
+	indexCmd.SetArgs([]string{})
+	if err := indexCmd.Execute(); err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	if _, err := os.Stat(fix.Path("features", "INDEX.json")); err != nil {
+		t.Fatalf("expected configured index output: %v", err)
+	}
+
+	if templateSource() != "https://example.com/custom.zip" {
+		t.Fatalf("expected configured template source, got %s", templateSource())
+	}
+	opts.Settings.Template.Source = ""
+	if templateSource() != templateZipURL {
+		t.Fatalf("expected default template source, got %s", templateSource())
+	}
+}
+
+func TestApplyFlagOverrides(t *testing.T) {
+	opts := &config.Options{JSONOutput: true}
+	cmd := newListCommand()
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "")
+	applyFlagOverrides(cmd, opts)
+	if !opts.JSONOutput {
+		t.Fatalf("unset flag should keep configured value")
+	}
+	if err := cmd.Flags().Set("json", "false"); err != nil {
+		t.Fatalf("set flag failed: %v", err)
+	}
+	applyFlagOverrides(cmd, opts)
+	if opts.JSONOutput {
+		t.Fatalf("explicit --json=false should override configuration")
+	}
+}
+
+func TestUpdateAndIndexCustomFields(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "config.yaml", []byte("fields:\n  estimate: int\n  customers: string[]\n"))
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Custom Fields")
+
+	updateCmd := newUpdateCommand()
+	updateCmd.SetOut(buf)
+	updateCmd.SetArgs([]string{"FTR-0001", "--field", "estimate=5", "--field", "customers=acme,globex"})
+	if err := updateCmd.Execute(); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	updateCmd = newUpdateCommand()
+	updateCmd.SetOut(buf)
+	updateCmd.SetArgs([]string{"FTR-0001", "--field", "estimate=lots"})
+	if err := updateCmd.Execute(); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error for mistyped field, got %v", err)
+	}
+
+	indexCmd := newIndexCommand()
+	indexCmd.SetOut(buf)
+	indexCmd.SetArgs([]string{"--column", "estimate,customers"})
+	if err := indexCmd.Execute(); err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	index, err := os.ReadFile(fix.Path("features", "INDEX.md"))
+	if err != nil {
+		t.Fatalf("read index failed: %v", err)
+	}
+	if !strings.Contains(string(index), "| estimate | customers |") || !strings.Contains(string(index), "| 5 | acme, globex |") {
+		t.Fatalf("expected custom columns in index:\n%s", index)
+	}
+}
+
+func TestIndexCommandQuery(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Backlog Item")
+	buildFeatureFile(t, fix, mgr, "FTR-0002", "review", "Review Item")
+
+	indexCmd := newIndexCommand()
+	indexCmd.SetOut(buf)
+	indexCmd.SetErr(buf)
+	indexCmd.SetArgs([]string{"--format", "json", "--output", "-", "--query", "status:review"})
+	if err := indexCmd.Execute(); err != nil {
+		t.Fatalf("index with query failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "FTR-0002") || strings.Contains(buf.String(), "FTR-0001") {
+		t.Fatalf("expected only the review feature:\n%s", buf.String())
+	}
+
+	indexCmd = newIndexCommand()
+	indexCmd.SetOut(buf)
+	indexCmd.SetErr(buf)
+	indexCmd.SetArgs([]string{"--query", "status:"})
+	if err := indexCmd.Execute(); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected query validation error, got %v", err)
+	}
+}
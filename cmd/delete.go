@@ -9,22 +9,42 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/virtualboard/vb-cli/internal/config"
 	"github.com/virtualboard/vb-cli/internal/feature"
 )
 
 func newDeleteCommand() *cobra.Command {
 	var force bool
+	var sel selector
 
 	cmd := &cobra.Command{
 		Use:   "delete <id>",
 		Short: "Delete a feature spec",
-		Args:  cobra.ExactArgs(1),
+		Long: `Delete a feature spec.
+
+Deleting a feature that others depend on prints a warning naming them; see
+vb impact for the full downstream picture.
+
+With --ids, --stdin or --where, the id argument is omitted and every selected
+feature is deleted together. --stdin requires --force because stdin carries the IDs.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if sel.active() {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts, err := options()
 			if err != nil {
 				return err
 			}
+			if sel.active() {
+				return deleteSelected(cmd, opts, &sel, force)
+			}
 			id := args[0]
+			mgr := feature.NewManager(opts)
+			dependents := dependentsOf(opts, mgr, []string{id})
+			warnDependents(cmd, dependents)
 
 			if !force {
 				prompt := fmt.Sprintf("Delete feature %s? Type 'yes' to confirm: ", id)
@@ -42,7 +62,6 @@ func newDeleteCommand() *cobra.Command {
 				}
 			}
 
-			mgr := feature.NewManager(opts)
 			path, err := mgr.DeleteFeature(id)
 			if err != nil {
 				if errors.Is(err, feature.ErrNotFound) {
@@ -57,7 +76,10 @@ func newDeleteCommand() *cobra.Command {
 				"id":   id,
 				"path": rel,
 			}
-			if err := respond(cmd, opts, true, message, data); err != nil {
+			if ids := dependents[feature.Key(id)]; len(ids) > 0 {
+				data["dependents"] = ids
+			}
+			if err := respondWithPatches(cmd, opts, true, message, data, mgr.Patches()); err != nil {
 				return err
 			}
 			return nil
@@ -65,5 +87,31 @@ func newDeleteCommand() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&force, "force", false, "Delete without confirmation")
+	sel.register(cmd)
 	return cmd
 }
+
+func deleteSelected(cmd *cobra.Command, opts *config.Options, sel *selector, force bool) error {
+	if sel.stdin && !force {
+		return WrapCLIError(ExitCodeValidation, fmt.Errorf("--stdin requires --force because stdin cannot also answer the confirmation"))
+	}
+	mgr := feature.NewManager(opts)
+	ids, err := sel.resolve(cmd, opts, mgr)
+	if err != nil {
+		return err
+	}
+	warnDependents(cmd, dependentsOf(opts, mgr, ids))
+	if !force && len(ids) > 0 {
+		prompt := fmt.Sprintf("Delete %d feature(s): %s? Type 'yes' to confirm: ", len(ids), strings.Join(ids, ", "))
+		fmt.Fprint(cmd.OutOrStdout(), prompt)
+		input, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil {
+			return WrapCLIError(ExitCodeFilesystem, fmt.Errorf("confirmation failed: %w", err))
+		}
+		if strings.TrimSpace(input) != "yes" {
+			return respond(cmd, opts, false, "Deletion cancelled", nil)
+		}
+	}
+	results, _ := mgr.DeleteFeatures(ids)
+	return reportBulk(cmd, opts, "Deleted", results, mgr.Patches())
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/epic"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+// epicSummary is the serialised form of an epic with its rollup.
+type epicSummary struct {
+	epic.FrontMatter
+	Path     string        `json:"path,omitempty"`
+	Defined  bool          `json:"defined"`
+	Progress epic.Progress `json:"progress"`
+}
+
+func newEpicCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "epic",
+		Short: "Manage epics grouping related features",
+		Long: `Manage epics: larger outcomes that group related features.
+
+Epics are defined in .virtualboard/epics/<id>.md and referenced from a feature's
+epic frontmatter field. Once the epics directory exists, vb validate reports
+features that reference an undefined epic.`,
+	}
+	cmd.AddCommand(newEpicNewCommand())
+	cmd.AddCommand(newEpicListCommand())
+	cmd.AddCommand(newEpicShowCommand())
+	return cmd
+}
+
+func newEpicNewCommand() *cobra.Command {
+	var id string
+	var owner string
+	var targetDate string
+
+	cmd := &cobra.Command{
+		Use:   "new <title>",
+		Short: "Define a new epic",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			mgr := epic.NewManager(opts)
+			e, err := mgr.Create(args[0], id, owner, targetDate)
+			if err != nil {
+				var invalid *epic.InvalidFileError
+				if errors.As(err, &invalid) {
+					return WrapCLIError(ExitCodeFilesystem, err)
+				}
+				return WrapCLIError(ExitCodeValidation, err)
+			}
+
+			rel, _ := filepath.Rel(opts.RootDir, e.Path)
+			message := fmt.Sprintf("Created epic %s at %s", e.FrontMatter.ID, filepath.ToSlash(rel))
+			return respondWithPatches(cmd, opts, true, message, map[string]interface{}{
+				"id":    e.FrontMatter.ID,
+				"path":  filepath.ToSlash(rel),
+				"title": e.FrontMatter.Title,
+			}, mgr.Patches())
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "Epic ID (default: slugified title)")
+	cmd.Flags().StringVar(&owner, "owner", "", "Epic owner (default: owner from settings)")
+	cmd.Flags().StringVar(&targetDate, "target-date", "", "Target completion date (YYYY-MM-DD)")
+	return cmd
+}
+
+func newEpicListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List epics with progress rolled up from their features",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			epics, err := epic.NewManager(opts).List()
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			features, err := feature.NewManager(opts).List()
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			rollup := epic.Rollup(features, opts.Workflow())
+
+			defined := make([]epicSummary, 0, len(epics))
+			for _, e := range epics {
+				defined = append(defined, newEpicSummary(opts.RootDir, e, rollup))
+				delete(rollup, epic.Key(e.FrontMatter.ID))
+			}
+			undefined := make([]epicSummary, 0, len(rollup))
+			for key, progress := range rollup {
+				undefined = append(undefined, epicSummary{FrontMatter: epic.FrontMatter{ID: key}, Progress: *progress})
+			}
+			sort.Slice(undefined, func(i, j int) bool { return undefined[i].ID < undefined[j].ID })
+
+			message := fmt.Sprintf("%d epic(s)", len(defined))
+			if opts.JSONOutput {
+				return respond(cmd, opts, true, message, map[string]interface{}{
+					"epics":     defined,
+					"undefined": undefined,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			if len(defined) == 0 {
+				fmt.Fprintln(out, "No epics defined")
+			} else {
+				tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "ID\tSTATUS\tPROGRESS\tOWNER\tTARGET\tTITLE")
+				for _, s := range defined {
+					fmt.Fprintf(tw, "%s\t%s\t%d/%d (%d%%)\t%s\t%s\t%s\n", s.ID, s.Status, s.Progress.Done, s.Progress.Total, s.Progress.Percent, fallbackText(s.Owner, "unassigned"), fallbackText(s.TargetDate, "-"), s.Title)
+				}
+				if err := tw.Flush(); err != nil {
+					return err
+				}
+			}
+			if len(undefined) > 0 {
+				refs := make([]string, 0, len(undefined))
+				for _, s := range undefined {
+					refs = append(refs, fmt.Sprintf("%s (%d feature(s))", s.ID, s.Progress.Total))
+				}
+				fmt.Fprintf(out, "Referenced but not defined: %s\n", strings.Join(refs, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func newEpicShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show an epic with the progress and status of its features",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			e, err := epic.NewManager(opts).LoadByID(args[0])
+			if err != nil {
+				if errors.Is(err, epic.ErrNotFound) {
+					return WrapCLIError(ExitCodeNotFound, err)
+				}
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			all, err := feature.NewManager(opts).List()
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			children := []*feature.Feature{}
+			related := []relatedFeature{}
+			for _, feat := range all {
+				if epic.Key(feat.FrontMatter.Epic) == epic.Key(e.FrontMatter.ID) {
+					children = append(children, feat)
+					related = append(related, newRelatedFeature(feat))
+				}
+			}
+			summary := newEpicSummary(opts.RootDir, e, epic.Rollup(children, opts.Workflow()))
+
+			if opts.JSONOutput {
+				return respond(cmd, opts, true, "", map[string]interface{}{
+					"epic":     summary,
+					"body":     e.Body,
+					"features": related,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			printEpic(out, summary)
+			if body := strings.TrimSpace(e.Body); body != "" {
+				fmt.Fprintf(out, "\n%s\n", body)
+			}
+			fmt.Fprintf(out, "\nProgress: %s\n", formatEpicProgress(summary.Progress, opts.Workflow()))
+			printRelated(out, "Features", related)
+			return nil
+		},
+	}
+}
+
+func newEpicSummary(root string, e *epic.Epic, rollup map[string]*epic.Progress) epicSummary {
+	rel, _ := filepath.Rel(root, e.Path)
+	summary := epicSummary{
+		FrontMatter: e.FrontMatter,
+		Path:        filepath.ToSlash(rel),
+		Defined:     true,
+		Progress:    epic.Progress{ByStatus: map[string]int{}},
+	}
+	if p, ok := rollup[epic.Key(e.FrontMatter.ID)]; ok {
+		summary.Progress = *p
+	}
+	return summary
+}
+
+func printEpic(out io.Writer, s epicSummary) {
+	fmt.Fprintf(out, "%s: %s\n", s.ID, s.Title)
+	rows := [][2]string{
+		{"Status", s.Status},
+		{"Owner", s.Owner},
+		{"Target date", s.TargetDate},
+		{"Created", s.Created},
+		{"Updated", s.Updated},
+		{"Path", s.Path},
+	}
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		fmt.Fprintf(out, "  %-12s %s\n", row[0]+":", row[1])
+	}
+}
+
+// formatEpicProgress renders a rollup such as "1/3 done (33%): 1 backlog, 1 in-progress, 1 done",
+// listing statuses in workflow order.
+func formatEpicProgress(p epic.Progress, wf *workflow.Workflow) string {
+	text := fmt.Sprintf("%d/%d done (%d%%)", p.Done, p.Total, p.Percent)
+	if p.Total == 0 {
+		return text
+	}
+	order := wf.Names()
+	known := map[string]bool{}
+	for _, name := range order {
+		known[name] = true
+	}
+	var extra []string
+	for status := range p.ByStatus {
+		if !known[status] {
+			extra = append(extra, status)
+		}
+	}
+	sort.Strings(extra)
+
+	parts := []string{}
+	for _, status := range append(order, extra...) {
+		if count := p.ByStatus[status]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, status))
+		}
+	}
+	return text + ": " + strings.Join(parts, ", ")
+}
+
+// fallbackText returns def when value is blank.
+func fallbackText(value, def string) string {
+	if strings.TrimSpace(value) == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/epic"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+// epicFeatures belong to the checkout epic, in either case, to another epic or
+// to none.
+var epicFeatures = []seedFeature{
+	{ID: "FTR-0001", Status: "done", Epic: "checkout"},
+	{ID: "FTR-0002", Status: "in-progress", Epic: "Checkout"},
+	{ID: "FTR-0003", Status: "backlog", Epic: "search"},
+	{ID: "FTR-0004", Status: "backlog"},
+}
+
+// newCheckoutEpic creates the checkout epic the epicFeatures refer to.
+var newCheckoutEpic = []string{"new", "Checkout revamp", "--id", "checkout", "--owner", "alice", "--target-date", "2026-12-01"}
+
+func TestEpicCommands(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	if _, err := runCommand(t, newEpicCommand(), newCheckoutEpic...); err != nil {
+		t.Fatalf("epic new failed: %v", err)
+	}
+	seedFeatures(t, fix, feature.NewManager(opts), epicFeatures...)
+
+	out, err := runCommand(t, newEpicCommand(), "list")
+	if err != nil {
+		t.Fatalf("epic list failed: %v", err)
+	}
+	for _, want := range []string{
+		"ID        STATUS   PROGRESS   OWNER  TARGET      TITLE\n",
+		"checkout  planned  1/2 (50%)  alice  2026-12-01  Checkout revamp\n",
+		"Referenced but not defined: search (1 feature(s))\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out, err = runCommand(t, newEpicCommand(), "show", "CHECKOUT")
+	if err != nil {
+		t.Fatalf("epic show failed: %v", err)
+	}
+	for _, want := range []string{
+		"checkout: Checkout revamp\n  Status:      planned\n  Owner:       alice\n  Target date: 2026-12-01\n",
+		"  Path:        epics/checkout.md\n",
+		"## Goal",
+		"Progress: 1/2 done (50%): 1 in-progress, 1 done\n",
+		"Features:\n  - FTR-0001 Feature FTR-0001 [done]\n  - FTR-0002 Feature FTR-0002 [in-progress]\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestEpicCommandsJSON(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	if _, err := runCommand(t, newEpicCommand(), newCheckoutEpic...); err != nil {
+		t.Fatalf("epic new failed: %v", err)
+	}
+	seedFeatures(t, fix, feature.NewManager(opts), epicFeatures...)
+	opts.JSONOutput = true
+
+	out, err := runCommand(t, newEpicCommand(), "list")
+	if err != nil {
+		t.Fatalf("epic list failed: %v", err)
+	}
+	var list struct {
+		Data struct {
+			Epics     []epicSummary `json:"epics"`
+			Undefined []epicSummary `json:"undefined"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if len(list.Data.Epics) != 1 || !list.Data.Epics[0].Defined || list.Data.Epics[0].Progress.ByStatus["in-progress"] != 1 ||
+		len(list.Data.Undefined) != 1 || list.Data.Undefined[0].ID != "search" || list.Data.Undefined[0].Defined {
+		t.Fatalf("unexpected list payload %+v", list.Data)
+	}
+
+	out, err = runCommand(t, newEpicCommand(), "show", "checkout")
+	if err != nil {
+		t.Fatalf("epic show failed: %v", err)
+	}
+	var show struct {
+		Data struct {
+			Epic     epicSummary      `json:"epic"`
+			Features []relatedFeature `json:"features"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &show); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if show.Data.Epic.ID != "checkout" || show.Data.Epic.Progress.Total != 2 || len(show.Data.Features) != 2 {
+		t.Fatalf("unexpected show payload %+v", show.Data)
+	}
+
+	out, err = runCommand(t, newEpicCommand(), "new", "Search")
+	if err != nil || !strings.Contains(out, `"path": "epics/search.md"`) {
+		t.Fatalf("unexpected epic new output: %v\n%s", err, out)
+	}
+}
+
+func TestEpicCommandErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+
+	out, err := runCommand(t, newEpicCommand(), "list")
+	if err != nil || !strings.Contains(out, "No epics defined") {
+		t.Fatalf("expected empty list: %v\n%s", err, out)
+	}
+	if _, err := runCommand(t, newEpicCommand(), "show", "missing"); ExitCode(err) != ExitCodeNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := runCommand(t, newEpicCommand(), "new", "Checkout"); err != nil {
+		t.Fatalf("epic new failed: %v", err)
+	}
+	if _, err := runCommand(t, newEpicCommand(), "new", "Checkout"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error for a duplicate, got %v", err)
+	}
+	if _, err := runCommand(t, newEpicCommand(), "new", "Later", "--target-date", "soon"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error for a bad date, got %v", err)
+	}
+
+	opts.DryRun = true
+	out, err = runCommand(t, newEpicCommand(), "new", "Dry")
+	if err != nil {
+		t.Fatalf("dry-run epic new failed: %v", err)
+	}
+	if !strings.Contains(out, "--- /dev/null\n+++ b/.virtualboard/epics/dry.md\n") {
+		t.Fatalf("expected a new file diff:\n%s", out)
+	}
+	if _, statErr := os.Stat(fix.Path("epics", "dry.md")); !os.IsNotExist(statErr) {
+		t.Fatalf("expected no file in dry-run, got %v", statErr)
+	}
+	opts.DryRun = false
+
+	fix.WriteFile(t, "epics/broken.md", []byte("broken"))
+	for _, args := range [][]string{{"list"}, {"show", "checkout"}, {"new", "Other"}} {
+		if _, err := runCommand(t, newEpicCommand(), args...); ExitCode(err) != ExitCodeFilesystem {
+			t.Fatalf("%v: expected filesystem error, got %v", args, err)
+		}
+	}
+	if err := os.Remove(fix.Path("epics", "broken.md")); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	fix.WriteFile(t, "features/backlog/FTR-0009-broken.md", []byte("broken"))
+	for _, args := range [][]string{{"list"}, {"show", "checkout"}} {
+		if _, err := runCommand(t, newEpicCommand(), args...); ExitCode(err) != ExitCodeFilesystem {
+			t.Fatalf("%v: expected filesystem error, got %v", args, err)
+		}
+	}
+}
+
+func TestIndexCommandGroupByEpic(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, buf := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	plain := buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Plain")
+	plain.FrontMatter.Epic = "misc"
+	if err := mgr.Save(plain); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	runIndex := func(args ...string) (string, error) {
+		buf.Reset()
+		indexCmd := newIndexCommand()
+		indexCmd.SetOut(buf)
+		indexCmd.SetErr(buf)
+		indexCmd.SetArgs(append([]string{"--output", "-"}, args...))
+		err := indexCmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := runIndex()
+	if err != nil || strings.Contains(out, "## Epic:") {
+		t.Fatalf("expected a flat index without epics: %v\n%s", err, out)
+	}
+	if out, err = runIndex("--group-by", "EPIC"); err != nil || !strings.Contains(out, "## Epic: misc (undefined) — 0/1 done (0%)") {
+		t.Fatalf("expected forced grouping: %v\n%s", err, out)
+	}
+
+	if _, err := runCommand(t, newEpicCommand(), newCheckoutEpic...); err != nil {
+		t.Fatalf("epic new failed: %v", err)
+	}
+	seedFeatures(t, fix, mgr, epicFeatures...)
+	out, err = runIndex()
+	if err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	for _, want := range []string{
+		"## Epic: Checkout revamp (checkout) — 1/2 done (50%)",
+		"## Epic: search (undefined) — 0/1 done (0%)",
+		"## No epic",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+	if out, err = runIndex("--group-by", "none"); err != nil || strings.Contains(out, "## Epic:") {
+		t.Fatalf("expected grouping to be disabled: %v\n%s", err, out)
+	}
+	if _, err = runIndex("--group-by", "owner"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	fix.WriteFile(t, "epics/broken.md", []byte("broken"))
+	if _, err = runIndex(); ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error, got %v", err)
+	}
+}
+
+func TestFormatEpicProgress(t *testing.T) {
+	p := epic.Progress{Total: 3, Done: 1, Percent: 33, ByStatus: map[string]int{"done": 1, "backlog": 1, "legacy": 1}}
+	if got := formatEpicProgress(p, workflow.Default()); got != "1/3 done (33%): 1 backlog, 1 done, 1 legacy" {
+		t.Fatalf("unexpected progress %q", got)
+	}
+	if got := formatEpicProgress(epic.Progress{}, workflow.Default()); got != "0/0 done (0%)" {
+		t.Fatalf("unexpected empty progress %q", got)
+	}
+	if fallbackText(" ", "-") != "-" || fallbackText("x", "-") != "x" {
+		t.Fatalf("unexpected fallback")
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/graph"
+	"github.com/virtualboard/vb-cli/internal/query"
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// graphNode is the serialised form of a feature in graph output.
+type graphNode struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title,omitempty"`
+	Status       string   `json:"status"`
+	Owner        string   `json:"owner,omitempty"`
+	Epic         string   `json:"epic,omitempty"`
+	Kind         string   `json:"kind"`
+	Dependencies []string `json:"dependencies"`
+	Dependents   []string `json:"dependents"`
+}
+
+// graphEdge points from a dependency to the feature that needs it.
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// graphReport is the adjacency JSON form of the graph with its analysis.
+type graphReport struct {
+	Nodes        []graphNode `json:"nodes"`
+	Edges        []graphEdge `json:"edges"`
+	Order        []string    `json:"order"`
+	Ready        []string    `json:"ready"`
+	Target       string      `json:"target,omitempty"`
+	CriticalPath []string    `json:"critical_path"`
+	// CriticalPathError explains an empty untargeted critical path, e.g. a cycle.
+	CriticalPathError string     `json:"critical_path_error,omitempty"`
+	Cycles            [][]string `json:"cycles"`
+}
+
+var graphFormats = []string{"summary", "dot", "mermaid", "json"}
+
+func newGraphCommand() *cobra.Command {
+	var statuses, epics []string
+	var queries []string
+	var target string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the feature dependency graph and plan around it",
+		Long: `Export the feature dependency graph as Graphviz DOT, Mermaid or adjacency JSON,
+or print a planning summary.
+
+Edges point from a dependency to the feature that needs it. The summary lists a
+topological order, the backlog items that are ready now because every dependency
+is done, and the critical path: the longest chain of unfinished work ending at
+--target, or anywhere when no target is given. Filters narrow the features shown;
+dependencies outside the filter are drawn as dashed nodes.
+
+Examples:
+  vb graph
+  vb graph --format dot --epic checkout | dot -Tsvg > checkout.svg
+  vb graph --format mermaid --status backlog,in-progress
+  vb graph --target FTR-0042 --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if !containsString(graphFormats, format) {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("unknown format %s (allowed: %s)", format, strings.Join(graphFormats, ", ")))
+			}
+
+			mgr := feature.NewManager(opts)
+			q, err := compileQuery(mgr, queries)
+			if err != nil {
+				return err
+			}
+			all, err := mgr.List()
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			keep := map[string]bool{}
+			for _, feat := range applyQuery(opts, mgr, query.And(q, query.Any("status", statuses), query.Any("epic", epics)), all) {
+				keep[feat.FrontMatter.ID] = true
+			}
+			g := graph.New(all, opts.Workflow()).Filter(func(feat *feature.Feature) bool {
+				return keep[feat.FrontMatter.ID]
+			})
+
+			report := graphReport{Ready: g.Ready(), Cycles: g.Cycles()}
+			report.Order, _ = g.TopoOrder()
+			if target != "" {
+				feat, err := mgr.LoadByID(target)
+				if err != nil {
+					if errors.Is(err, feature.ErrNotFound) {
+						return WrapCLIError(ExitCodeNotFound, err)
+					}
+					return WrapCLIError(ExitCodeFilesystem, err)
+				}
+				report.Target = feat.FrontMatter.ID
+			}
+			if report.CriticalPath, err = g.CriticalPath(report.Target); err != nil {
+				if target != "" {
+					return WrapCLIError(ExitCodeDependency, err)
+				}
+				report.CriticalPathError = err.Error()
+			}
+			for _, id := range g.IDs() {
+				feat, _ := g.Feature(id)
+				report.Nodes = append(report.Nodes, graphNode{
+					ID:           id,
+					Title:        feat.FrontMatter.Title,
+					Status:       feat.FrontMatter.Status,
+					Owner:        feat.FrontMatter.Owner,
+					Epic:         feat.FrontMatter.Epic,
+					Kind:         g.Kind(id),
+					Dependencies: g.Dependencies(id),
+					Dependents:   g.Dependents(id),
+				})
+			}
+			for _, e := range g.Edges() {
+				report.Edges = append(report.Edges, graphEdge{From: e.From, To: e.To})
+			}
+			if report.Nodes == nil {
+				report.Nodes = []graphNode{}
+			}
+			if report.Edges == nil {
+				report.Edges = []graphEdge{}
+			}
+			if report.CriticalPath == nil {
+				report.CriticalPath = []string{}
+			}
+
+			if opts.JSONOutput {
+				message := fmt.Sprintf("%d feature(s), %d dependency edge(s)", len(report.Nodes), len(report.Edges))
+				return respond(cmd, opts, true, message, report)
+			}
+			out := cmd.OutOrStdout()
+			switch format {
+			case "dot":
+				return g.WriteDOT(out, report.CriticalPath)
+			case "mermaid":
+				return g.WriteMermaid(out, report.CriticalPath)
+			case "json":
+				return util.PrintJSON(out, report)
+			default:
+				writeGraphSummary(out, g, report)
+				return nil
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&statuses, "status", nil, "Only include features with these statuses")
+	cmd.Flags().StringSliceVar(&epics, "epic", nil, "Only include features in these epics")
+	cmd.Flags().StringArrayVar(&queries, "query", nil, "Only include features matching a query expression (repeatable, AND'ed)")
+	cmd.Flags().StringVar(&target, "target", "", "Feature to compute the critical path to (default: longest chain anywhere)")
+	cmd.Flags().StringVar(&format, "format", "summary", fmt.Sprintf("Output format: %s", strings.Join(graphFormats, ", ")))
+	return cmd
+}
+
+func writeGraphSummary(w io.Writer, g *graph.Graph, report graphReport) {
+	describe := func(id string) string {
+		feat, ok := g.Feature(id)
+		if !ok {
+			return fmt.Sprintf("%s [missing]", id)
+		}
+		return fmt.Sprintf("%s %s [%s]", id, feat.FrontMatter.Title, feat.FrontMatter.Status)
+	}
+
+	if len(report.Nodes) == 0 {
+		fmt.Fprintln(w, "No features found")
+		return
+	}
+	fmt.Fprintf(w, "Features: %d, dependency edges: %d\n", len(report.Nodes), len(report.Edges))
+
+	fmt.Fprintln(w, "\nOrder (dependencies first):")
+	for i, id := range report.Order {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, describe(id))
+	}
+
+	fmt.Fprintln(w, "\nReady now:")
+	if len(report.Ready) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for _, id := range report.Ready {
+		fmt.Fprintf(w, "  %s\n", describe(id))
+	}
+
+	if report.Target != "" {
+		fmt.Fprintf(w, "\nCritical path to %s (%d remaining):\n", report.Target, len(report.CriticalPath))
+	} else {
+		fmt.Fprintf(w, "\nLongest remaining chain (%d):\n", len(report.CriticalPath))
+	}
+	switch {
+	case report.CriticalPathError != "":
+		fmt.Fprintf(w, "  (unavailable: %s)\n", report.CriticalPathError)
+	case len(report.CriticalPath) == 0:
+		fmt.Fprintln(w, "  (nothing left to do)")
+	}
+	for i, id := range report.CriticalPath {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, describe(id))
+	}
+
+	if len(report.Cycles) > 0 {
+		fmt.Fprintln(w, "\nCycles (left out of the order):")
+		for _, cycle := range report.Cycles {
+			fmt.Fprintf(w, "  %s\n", strings.Join(cycle, " -> "))
+		}
+	}
+}
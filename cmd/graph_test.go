@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+// graphFeatures is a checkout chain FTR-0001 <- FTR-0002 <- FTR-0003 with
+// FTR-0004 in another epic depending on its end.
+var graphFeatures = []seedFeature{
+	{ID: "FTR-0001", Status: "done", Epic: "checkout"},
+	{ID: "FTR-0002", Status: "backlog", Epic: "checkout", Deps: []string{"FTR-0001"}},
+	{ID: "FTR-0003", Status: "backlog", Epic: "checkout", Deps: []string{"FTR-0002"}},
+	{ID: "FTR-0004", Status: "backlog", Epic: "search", Deps: []string{"FTR-0003"}},
+}
+
+func TestGraphCommandSummary(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), graphFeatures...)
+
+	out, err := runCommand(t, newGraphCommand(), "--target", "ftr-0003")
+	if err != nil {
+		t.Fatalf("graph failed: %v", err)
+	}
+	for _, want := range []string{
+		"Features: 4, dependency edges: 3",
+		"  1. FTR-0001 Feature FTR-0001 [done]\n  2. FTR-0002",
+		"Ready now:\n  FTR-0002 Feature FTR-0002 [backlog]\n",
+		"Critical path to FTR-0003 (2 remaining):\n  1. FTR-0002 Feature FTR-0002 [backlog]\n  2. FTR-0003",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out, err = runCommand(t, newGraphCommand(), "--status", "review")
+	if err != nil || !strings.Contains(out, "No features found") {
+		t.Fatalf("expected empty summary: %v\n%s", err, out)
+	}
+}
+
+func TestGraphCommandFormats(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), graphFeatures...)
+
+	out, err := runCommand(t, newGraphCommand(), "--format", "dot", "--epic", "search")
+	if err != nil || !strings.Contains(out, `"FTR-0003" -> "FTR-0004" [color="#d9534f", penwidth=2];`) || !strings.Contains(out, `style="rounded,dashed"`) {
+		t.Fatalf("unexpected dot output: %v\n%s", err, out)
+	}
+
+	out, err = runCommand(t, newGraphCommand(), "--format", "MERMAID", "--query", "status:backlog")
+	if err != nil || !strings.Contains(out, "FTR_0002 --> FTR_0003") || !strings.Contains(out, "class FTR_0001 external") {
+		t.Fatalf("unexpected mermaid output: %v\n%s", err, out)
+	}
+
+	out, err = runCommand(t, newGraphCommand(), "--format", "json")
+	if err != nil {
+		t.Fatalf("json failed: %v", err)
+	}
+	var report graphReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if len(report.Nodes) != 4 || len(report.Edges) != 3 || !reflect.DeepEqual(report.CriticalPath, []string{"FTR-0002", "FTR-0003", "FTR-0004"}) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Nodes[1].Kind != "ready" || !reflect.DeepEqual(report.Nodes[1].Dependents, []string{"FTR-0003"}) {
+		t.Fatalf("unexpected node %+v", report.Nodes[1])
+	}
+}
+
+func TestGraphCommandJSONEnvelopeAndCycles(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	mgr := feature.NewManager(opts)
+	a := buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "A")
+	a.FrontMatter.Dependencies = []string{"FTR-0002"}
+	b := buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", "B")
+	b.FrontMatter.Dependencies = []string{"FTR-0001"}
+	for _, f := range []*feature.Feature{a, b} {
+		if err := mgr.Save(f); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+	}
+
+	out, err := runCommand(t, newGraphCommand(), "--format", "dot")
+	if err != nil {
+		t.Fatalf("graph failed: %v", err)
+	}
+	var payload struct {
+		Success bool        `json:"success"`
+		Data    graphReport `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("expected JSON envelope regardless of format: %v\n%s", err, out)
+	}
+	if len(payload.Data.Cycles) != 1 || len(payload.Data.Order) != 0 || !strings.Contains(payload.Data.CriticalPathError, "circular dependency") {
+		t.Fatalf("unexpected payload %+v", payload.Data)
+	}
+
+	if _, err := runCommand(t, newGraphCommand(), "--target", "FTR-0001"); err == nil || ExitCode(err) != ExitCodeDependency {
+		t.Fatalf("expected dependency exit code for a cyclic target, got %v", err)
+	}
+
+	opts.JSONOutput = false
+	out, err = runCommand(t, newGraphCommand())
+	if err != nil || !strings.Contains(out, "(unavailable: circular dependency detected") || !strings.Contains(out, "Cycles (left out of the order):\n  FTR-0001 -> FTR-0002 -> FTR-0001") {
+		t.Fatalf("unexpected cyclic summary: %v\n%s", err, out)
+	}
+}
+
+func TestGraphCommandErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	setupOptions(t, fix, false, false, false)
+
+	if _, err := runCommand(t, newGraphCommand(), "--format", "png"); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := runCommand(t, newGraphCommand(), "--query", "bogus:1"); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected query error, got %v", err)
+	}
+	if _, err := runCommand(t, newGraphCommand(), "--target", "FTR-0404"); err == nil || ExitCode(err) != ExitCodeNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	fix.WriteFile(t, "features/backlog/FTR-0009-broken.md", []byte("broken"))
+	if _, err := runCommand(t, newGraphCommand()); err == nil || ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error, got %v", err)
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/lock"
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/query"
 	"github.com/virtualboard/vb-cli/internal/util"
 )
 
@@ -23,3 +27,57 @@ func respond(cmd *cobra.Command, opts *config.Options, success bool, message str
 	}
 	return nil
 }
+
+// respondWithPatches responds like respond and, in dry-run mode, adds the file
+// changes the command would have made: as "patches" in JSON, or as a unified diff
+// after the message.
+func respondWithPatches(cmd *cobra.Command, opts *config.Options, success bool, message string, data map[string]interface{}, patches []patch.Patch) error {
+	addPatches(opts, data, patches)
+	if err := respond(cmd, opts, success, message, data); err != nil {
+		return err
+	}
+	printPatches(cmd, opts, patches)
+	return nil
+}
+
+// addPatches adds the planned patches to a JSON payload in dry-run mode.
+func addPatches(opts *config.Options, data map[string]interface{}, patches []patch.Patch) {
+	if opts.DryRun {
+		if patches == nil {
+			patches = []patch.Patch{}
+		}
+		data["patches"] = patches
+	}
+}
+
+// printPatches prints the planned patches as a unified diff in dry-run text mode.
+func printPatches(cmd *cobra.Command, opts *config.Options, patches []patch.Patch) {
+	if opts.DryRun && !opts.JSONOutput {
+		fmt.Fprint(cmd.OutOrStdout(), patch.Join(patches))
+	}
+}
+
+// compileQuery parses query expressions against the workspace's custom fields.
+// It returns nil when no expression was given.
+func compileQuery(mgr *feature.Manager, exprs []string) (*query.Query, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	custom, err := mgr.CustomFields()
+	if err != nil {
+		return nil, WrapCLIError(ExitCodeSchema, err)
+	}
+	q, err := query.ParseAll(exprs, custom)
+	if err != nil {
+		return nil, WrapCLIError(ExitCodeValidation, err)
+	}
+	return q, nil
+}
+
+// applyQuery filters features with q, deriving lock and dependency facts from the workspace.
+func applyQuery(opts *config.Options, mgr *feature.Manager, q *query.Query, features []*feature.Feature) []*feature.Feature {
+	if q == nil {
+		return features
+	}
+	return q.Apply(features, query.NewEnv(features, mgr.Workflow(), lock.NewManager(opts)))
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/graph"
+)
+
+// impactEntry is the serialised form of a downstream feature.
+type impactEntry struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Owner  string `json:"owner"`
+	Depth  int    `json:"depth"`
+	Via    string `json:"via"`
+	AtRisk bool   `json:"at_risk"`
+}
+
+func newImpactCommand() *cobra.Command {
+	var depth int
+
+	cmd := &cobra.Command{
+		Use:   "impact <id>",
+		Short: "List every feature that depends on a feature, directly or transitively",
+		Long: `List every feature downstream of a feature by walking its reverse dependencies.
+
+Each feature is shown at its shortest depth (1 for direct dependents) together with
+the dependency it is reached through. Direct dependents in a status that requires
+finished dependencies, such as in-progress, are flagged: they would fail validation
+if the feature left done or were deleted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			if depth < 0 {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("--depth must not be negative"))
+			}
+
+			mgr := feature.NewManager(opts)
+			feat, err := mgr.LoadByID(args[0])
+			if err != nil {
+				if errors.Is(err, feature.ErrNotFound) {
+					return WrapCLIError(ExitCodeNotFound, err)
+				}
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			id := feat.FrontMatter.ID
+			all, err := mgr.List()
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			g := graph.New(all, opts.Workflow())
+
+			entries := []impactEntry{}
+			atRisk := []string{}
+			for _, impact := range g.Downstream(id, depth) {
+				dependent, _ := g.Feature(impact.ID)
+				entry := impactEntry{
+					ID:     impact.ID,
+					Title:  dependent.FrontMatter.Title,
+					Status: dependent.FrontMatter.Status,
+					Owner:  dependent.FrontMatter.Owner,
+					Depth:  impact.Depth,
+					Via:    impact.Via,
+					AtRisk: impact.Depth == 1 && g.RequiresDone(impact.ID),
+				}
+				if entry.Owner == "" {
+					entry.Owner = "unassigned"
+				}
+				if entry.AtRisk {
+					atRisk = append(atRisk, entry.ID)
+				}
+				entries = append(entries, entry)
+			}
+
+			message := fmt.Sprintf("%d feature(s) depend on %s", len(entries), id)
+			if opts.JSONOutput {
+				return respond(cmd, opts, true, message, map[string]interface{}{
+					"id":         id,
+					"status":     feat.FrontMatter.Status,
+					"dependents": entries,
+					"at_risk":    atRisk,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s %s [%s]\n", id, feat.FrontMatter.Title, feat.FrontMatter.Status)
+			if len(entries) == 0 {
+				fmt.Fprintf(out, "No features depend on %s\n", id)
+				return nil
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "DEPTH\tID\tSTATUS\tOWNER\tVIA\tTITLE")
+			for _, e := range entries {
+				mark := ""
+				if e.AtRisk {
+					mark = " (!)"
+				}
+				fmt.Fprintf(tw, "%d\t%s%s\t%s\t%s\t%s\t%s\n", e.Depth, e.ID, mark, e.Status, e.Owner, e.Via, e.Title)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, message)
+			if len(atRisk) > 0 {
+				fmt.Fprintf(out, "(!) %s would fail validation if %s left %s or were deleted\n", strings.Join(atRisk, ", "), id, opts.Workflow().Done)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", 0, "Maximum depth to walk (0 for unlimited)")
+	return cmd
+}
+
+// dependentsOf returns, keyed by feature.Key, the direct dependents of each
+// feature in ids that are not being removed with it. It is best effort: when the
+// workspace cannot be listed it reports nothing rather than blocking the caller.
+func dependentsOf(opts *config.Options, mgr *feature.Manager, ids []string) map[string][]string {
+	all, err := mgr.List()
+	if err != nil {
+		return nil
+	}
+	g := graph.New(all, opts.Workflow())
+	removed := map[string]bool{}
+	for _, id := range ids {
+		removed[feature.Key(id)] = true
+	}
+	found := map[string][]string{}
+	for _, id := range g.IDs() {
+		if !removed[feature.Key(id)] {
+			continue
+		}
+		for _, dependent := range g.Dependents(id) {
+			if !removed[feature.Key(dependent)] {
+				found[feature.Key(id)] = append(found[feature.Key(id)], dependent)
+			}
+		}
+	}
+	return found
+}
+
+// warnDependents prints one warning per feature that others still depend on.
+func warnDependents(cmd *cobra.Command, dependents map[string][]string) {
+	ids := make([]string, 0, len(dependents))
+	for id := range dependents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s depend(s) on %s and will reference a missing dependency\n", strings.Join(dependents[id], ", "), id)
+	}
+}
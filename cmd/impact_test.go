@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+// impactFeatures depend on FTR-0001, directly or through FTR-0002.
+var impactFeatures = []seedFeature{
+	{ID: "FTR-0001", Status: "done", Title: "Base"},
+	{ID: "FTR-0002", Status: "in-progress", Owner: "alice", Deps: []string{"FTR-0001"}},
+	{ID: "FTR-0003", Status: "backlog", Owner: "unassigned", Deps: []string{"FTR-0001"}},
+	{ID: "FTR-0004", Status: "backlog", Owner: "bob", Deps: []string{"FTR-0002"}},
+}
+
+func TestImpactCommandText(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), impactFeatures...)
+
+	out, err := runCommand(t, newImpactCommand(), "ftr-0001")
+	if err != nil {
+		t.Fatalf("impact failed: %v", err)
+	}
+	for _, want := range []string{
+		"FTR-0001 Base [done]",
+		"DEPTH  ID",
+		"1      FTR-0002 (!)  in-progress  alice       FTR-0001  Feature FTR-0002",
+		"1      FTR-0003      backlog      unassigned  FTR-0001",
+		"2      FTR-0004      backlog      bob         FTR-0002",
+		"3 feature(s) depend on FTR-0001",
+		"(!) FTR-0002 would fail validation if FTR-0001 left done or were deleted",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out, err = runCommand(t, newImpactCommand(), "FTR-0004")
+	if err != nil || !strings.Contains(out, "No features depend on FTR-0004") {
+		t.Fatalf("unexpected leaf output: %v\n%s", err, out)
+	}
+}
+
+func TestImpactCommandJSON(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), impactFeatures...)
+
+	out, err := runCommand(t, newImpactCommand(), "FTR-0001", "--depth", "1")
+	if err != nil {
+		t.Fatalf("impact failed: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Dependents []impactEntry `json:"dependents"`
+			AtRisk     []string      `json:"at_risk"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if len(payload.Data.Dependents) != 2 || !payload.Data.Dependents[0].AtRisk || strings.Join(payload.Data.AtRisk, ",") != "FTR-0002" {
+		t.Fatalf("unexpected payload %+v", payload.Data)
+	}
+}
+
+func TestImpactCommandErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	if _, err := runCommand(t, newImpactCommand(), "FTR-0001", "--depth", "-1"); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := runCommand(t, newImpactCommand(), "FTR-0404"); err == nil || ExitCode(err) != ExitCodeNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "done", "Base")
+	fix.WriteFile(t, "features/backlog/FTR-0009-broken.md", []byte("broken"))
+	if _, err := runCommand(t, newImpactCommand(), "FTR-0001"); err == nil || ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error, got %v", err)
+	}
+	if got := dependentsOf(opts, mgr, []string{"FTR-0001"}); got != nil {
+		t.Fatalf("expected no warnings when the workspace cannot be listed, got %v", got)
+	}
+}
+
+func TestDeleteWarnsAboutDependents(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), impactFeatures...)
+
+	var out, errOut bytes.Buffer
+	cmd := newDeleteCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetIn(strings.NewReader("no\n"))
+	cmd.SetArgs([]string{"ftr-0001"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "Warning: FTR-0002, FTR-0003 depend(s) on FTR-0001") {
+		t.Fatalf("expected dependents warning, got %q", errOut.String())
+	}
+
+	opts.JSONOutput, opts.DryRun = true, true
+	out.Reset()
+	cmd = newDeleteCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"ftr-0001", "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("dry-run delete failed: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Dependents []string `json:"dependents"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil || strings.Join(payload.Data.Dependents, ",") != "FTR-0002,FTR-0003" {
+		t.Fatalf("expected both dependents in JSON: %v %s", err, out.String())
+	}
+	opts.JSONOutput, opts.DryRun = false, false
+
+	errOut.Reset()
+	cmd = newDeleteCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--ids", "FTR-0002,FTR-0004", "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("bulk delete failed: %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("expected no warning when dependents are deleted too, got %q", errOut.String())
+	}
+
+	opts.JSONOutput = true
+	out.Reset()
+	cmd = newDeleteCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"FTR-0001", "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	payload.Data.Dependents = nil
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil || strings.Join(payload.Data.Dependents, ",") != "FTR-0003" {
+		t.Fatalf("expected dependents in JSON: %v %s", err, out.String())
+	}
+}
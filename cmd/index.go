@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/virtualboard/vb-cli/internal/epic"
 	"github.com/virtualboard/vb-cli/internal/feature"
 	"github.com/virtualboard/vb-cli/internal/indexer"
 	"github.com/virtualboard/vb-cli/internal/util"
@@ -16,6 +17,9 @@ import (
 func newIndexCommand() *cobra.Command {
 	var format string
 	var output string
+	var columns []string
+	var queries []string
+	var groupBy string
 	var verbosity int
 	var quiet bool
 
@@ -27,6 +31,15 @@ func newIndexCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if !cmd.Flags().Changed("format") {
+				format = opts.Settings.Index.Format
+			}
+			if !cmd.Flags().Changed("output") {
+				output = opts.Settings.Index.Output
+			}
+			if !cmd.Flags().Changed("column") {
+				columns = opts.Settings.Index.Columns
+			}
 			format = strings.ToLower(format)
 			if format == "" {
 				format = "md"
@@ -38,7 +51,32 @@ func newIndexCommand() *cobra.Command {
 			}
 
 			mgr := feature.NewManager(opts)
+			q, err := compileQuery(mgr, queries)
+			if err != nil {
+				return err
+			}
 			gen := indexer.NewGenerator(mgr)
+			gen.Columns = columns
+			epics := epic.NewManager(opts)
+			switch strings.ToLower(groupBy) {
+			case "":
+				gen.GroupByEpic = epics.Defined()
+			case "epic":
+				gen.GroupByEpic = true
+			case "none":
+			default:
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("unknown --group-by %s (expected epic or none)", groupBy))
+			}
+			if gen.GroupByEpic {
+				if gen.Epics, err = epics.List(); err != nil {
+					return WrapCLIError(ExitCodeFilesystem, err)
+				}
+			}
+			if q != nil {
+				gen.Select = func(features []*feature.Feature) []*feature.Feature {
+					return applyQuery(opts, mgr, q, features)
+				}
+			}
 			data, err := gen.Build()
 			if err != nil {
 				return WrapCLIError(ExitCodeFilesystem, err)
@@ -153,6 +191,9 @@ func newIndexCommand() *cobra.Command {
 
 	cmd.Flags().StringVar(&format, "format", "md", "Index format: md, json, html")
 	cmd.Flags().StringVar(&output, "output", "", "Output destination (default: features/INDEX.md for md format)")
+	cmd.Flags().StringSliceVar(&columns, "column", nil, "Custom frontmatter field to add as an index column (default from index.columns in config)")
+	cmd.Flags().StringArrayVar(&queries, "query", nil, "Only index features matching a query expression (repeatable, AND'ed)")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group features: epic or none (default: epic when epics are defined)")
 	cmd.Flags().CountVarP(&verbosity, "verbose", "v", "Increase verbosity level (-v, -vv)")
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only output if there are changes")
 	return cmd
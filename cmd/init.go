@@ -27,7 +27,7 @@ type fetchTemplateToDirFunc func() (string, error)
 type fetchTemplateVersionFunc func() (string, error)
 
 var fetchTemplate fetchTemplateFunc = func(workdir, dest string) error {
-	resp, err := http.Get(templateZipURL)
+	resp, err := http.Get(templateSource())
 	if err != nil {
 		return fmt.Errorf("failed to download template archive: %w", err)
 	}
@@ -161,6 +161,14 @@ var fetchTemplateVersionVar fetchTemplateVersionFunc = func() (string, error) {
 	return strings.TrimSpace(string(versionBytes)), nil
 }
 
+// templateSource returns the configured template archive URL, falling back to the official template.
+func templateSource() string {
+	if opts, err := options(); err == nil && opts.Settings.Template.Source != "" {
+		return opts.Settings.Template.Source
+	}
+	return templateZipURL
+}
+
 func saveTemplateVersion(targetPath, version string) error {
 	versionPath := filepath.Join(targetPath, templateVersionFile)
 	return util.WriteFileAtomic(versionPath, []byte(version+"\n"), 0o600)
@@ -179,6 +187,8 @@ func readTemplateVersion(targetPath string) (string, error) {
 }
 
 func handleUpdate(cmd *cobra.Command, opts *config.Options, targetPath string, fileFilter []string, autoYes bool) error {
+	util.SetEditor(opts.Settings.Editor)
+
 	// Check if workspace exists
 	exists, err := pathExists(targetPath)
 	if err != nil {
@@ -695,17 +705,14 @@ func newInitCommand() *cobra.Command {
 By default, creates a new .virtualboard/ directory with the latest template.
 Use --update to update an existing workspace to the latest template version.
 Use --files to update only specific files when using --update.`,
+		Annotations: map[string]string{annotationAllowInvalidConfig: "true"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts, err := options()
 			if err != nil {
 				return err
 			}
 
-			projectRoot := opts.RootDir
-			if filepath.Base(projectRoot) == initDirName {
-				projectRoot = filepath.Dir(projectRoot)
-			}
-
+			projectRoot := initRoot(opts)
 			targetPath := filepath.Join(projectRoot, initDirName)
 
 			// Handle --update flag
@@ -753,7 +760,7 @@ Use --files to update only specific files when using --update.`,
 			msg := fmt.Sprintf("VirtualBoard project initialised in %s. Review the files under %s.", initDirName, initDirName)
 			return respond(cmd, opts, true, msg, map[string]interface{}{
 				"path":    initDirName,
-				"source":  templateZipURL,
+				"source":  templateSource(),
 				"version": version,
 			})
 		},
@@ -767,6 +774,20 @@ Use --files to update only specific files when using --update.`,
 	return cmd
 }
 
+// initRoot returns the directory vb init works in. It is the directory discovery
+// started from rather than the discovered workspace, so running init in a
+// subdirectory never reports on, or with --force removes, a parent project's workspace.
+func initRoot(opts *config.Options) string {
+	root := opts.StartDir
+	if root == "" {
+		root = opts.RootDir
+	}
+	if filepath.Base(root) == initDirName {
+		root = filepath.Dir(root)
+	}
+	return root
+}
+
 func pathExists(path string) (bool, error) {
 	_, err := os.Stat(path)
 	if err == nil {
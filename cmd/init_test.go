@@ -136,6 +136,47 @@ func TestInitCommandForceRecreates(t *testing.T) {
 	}
 }
 
+func TestInitCommandInNestedDirectory(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mockTemplateVersion(t)
+	parent := filepath.Join(fix.Root, initDirName)
+	nested := filepath.Join(fix.Root, "services", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	opts := config.New()
+	if err := opts.Init(nested, false, false, false, ""); err != nil {
+		t.Fatalf("failed to init options: %v", err)
+	}
+	if opts.RootDir != parent {
+		t.Fatalf("expected discovery to find the parent workspace, got %s", opts.RootDir)
+	}
+	config.SetCurrent(opts)
+	t.Cleanup(func() { config.SetCurrent(nil) })
+
+	original := fetchTemplate
+	fetchTemplate = func(workdir, dest string) error {
+		if workdir != nested {
+			t.Fatalf("unexpected workdir: %s", workdir)
+		}
+		return os.MkdirAll(filepath.Join(workdir, dest), 0o755)
+	}
+	t.Cleanup(func() { fetchTemplate = original })
+
+	for _, args := range [][]string{nil, {"--force"}} {
+		if out, err := runCommand(t, newInitCommand(), args...); err != nil {
+			t.Fatalf("init %v failed: %v\n%s", args, err, out)
+		}
+		if _, err := os.Stat(filepath.Join(nested, initDirName)); err != nil {
+			t.Fatalf("expected nested workspace: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(parent, "features")); err != nil {
+			t.Fatalf("parent workspace must be left alone after init %v: %v", args, err)
+		}
+	}
+}
+
 // Helper function to mock template version fetching in tests
 func mockTemplateVersion(t *testing.T) {
 	t.Helper()
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/query"
+)
+
+// listEntry is the serialised form of a feature in list output.
+type listEntry struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`
+	Owner        string   `json:"owner"`
+	Priority     string   `json:"priority"`
+	Complexity   string   `json:"complexity"`
+	Epic         string   `json:"epic,omitempty"`
+	Labels       []string `json:"labels"`
+	Dependencies []string `json:"dependencies"`
+	Created      string   `json:"created"`
+	Updated      string   `json:"updated"`
+	Path         string   `json:"path"`
+}
+
+var listFormats = []string{"table", "plain", "json", "csv", "ndjson"}
+
+// listFilters holds the list filter flags. Each flag is shorthand for a query
+// term, so they match exactly like --query does.
+type listFilters struct {
+	Statuses      []string
+	Owners        []string
+	Labels        []string
+	Priorities    []string
+	Complexities  []string
+	Epics         []string
+	UpdatedAfter  string
+	UpdatedBefore string
+}
+
+// query translates the flags into a query, or nil when no flag is set.
+func (f listFilters) query() (*query.Query, error) {
+	bounds := []struct{ name, value string }{
+		{"updated-after", f.UpdatedAfter},
+		{"updated-before", f.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		if bound.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", bound.value); err != nil {
+			return nil, fmt.Errorf("%s must be YYYY-MM-DD, got %q", bound.name, bound.value)
+		}
+	}
+	return query.And(
+		query.Any("status", f.Statuses),
+		query.Any("owner", f.Owners),
+		query.Any("label", f.Labels),
+		query.Any("priority", f.Priorities),
+		query.Any("complexity", f.Complexities),
+		query.Any("epic", f.Epics),
+		query.Compare("updated", ">=", f.UpdatedAfter),
+		query.Compare("updated", "<=", f.UpdatedBefore),
+	), nil
+}
+
+func newListCommand() *cobra.Command {
+	var filter listFilters
+	var queries []string
+	var sortKeys []string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List features with optional filtering and sorting",
+		Long: `List features without regenerating the index.
+
+Filters accept comma-separated values or can be repeated; values within a filter
+are OR'ed and different filters are AND'ed together. --query accepts the full
+query language and is AND'ed with the other filters.
+
+Examples:
+  vb list --status in-progress,review
+  vb list --owner alice --label backend --sort -updated
+  vb list --updated-after 2026-01-01 --format csv
+  vb list --query 'status in (review,done) and label:backend and not owner:unassigned'
+  vb list --query 'is:ready and empty:"Acceptance Criteria"'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if !containsString(listFormats, format) {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("unknown format %s (allowed: %s)", format, strings.Join(listFormats, ", ")))
+			}
+			flagQuery, err := filter.query()
+			if err != nil {
+				return WrapCLIError(ExitCodeValidation, err)
+			}
+
+			mgr := feature.NewManager(opts)
+			q, err := compileQuery(mgr, queries)
+			if err != nil {
+				return err
+			}
+			all, err := mgr.List()
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			selected := applyQuery(opts, mgr, query.And(q, flagQuery), all)
+			if err := feature.SortFeatures(selected, sortKeys); err != nil {
+				return WrapCLIError(ExitCodeValidation, err)
+			}
+
+			entries := make([]listEntry, 0, len(selected))
+			for _, feat := range selected {
+				entries = append(entries, newListEntry(opts.RootDir, feat))
+			}
+
+			if opts.JSONOutput {
+				message := fmt.Sprintf("%d feature(s)", len(entries))
+				return respond(cmd, opts, true, message, map[string]interface{}{
+					"total":    len(entries),
+					"features": entries,
+				})
+			}
+			return writeListEntries(cmd.OutOrStdout(), format, entries)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&filter.Statuses, "status", nil, "Filter by status")
+	cmd.Flags().StringSliceVar(&filter.Owners, "owner", nil, "Filter by owner")
+	cmd.Flags().StringSliceVar(&filter.Labels, "label", nil, "Filter by label (matches any)")
+	cmd.Flags().StringSliceVar(&filter.Priorities, "priority", nil, "Filter by priority")
+	cmd.Flags().StringSliceVar(&filter.Complexities, "complexity", nil, "Filter by complexity")
+	cmd.Flags().StringSliceVar(&filter.Epics, "epic", nil, "Filter by epic")
+	cmd.Flags().StringVar(&filter.UpdatedAfter, "updated-after", "", "Only features updated on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&filter.UpdatedBefore, "updated-before", "", "Only features updated on or before this date (YYYY-MM-DD)")
+	cmd.Flags().StringArrayVar(&queries, "query", nil, "Filter with a query expression (repeatable, AND'ed)")
+	cmd.Flags().StringSliceVar(&sortKeys, "sort", []string{"id"}, fmt.Sprintf("Sort columns, prefix with - for descending (%s)", strings.Join(feature.SortKeys, ", ")))
+	cmd.Flags().StringVar(&format, "format", "table", fmt.Sprintf("Output format: %s", strings.Join(listFormats, ", ")))
+	return cmd
+}
+
+func newListEntry(root string, feat *feature.Feature) listEntry {
+	rel, err := filepath.Rel(root, feat.Path)
+	if err != nil {
+		rel = feat.Path
+	}
+	owner := feat.FrontMatter.Owner
+	if strings.TrimSpace(owner) == "" {
+		owner = "unassigned"
+	}
+	labels := feat.FrontMatter.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	deps := feat.FrontMatter.Dependencies
+	if deps == nil {
+		deps = []string{}
+	}
+	return listEntry{
+		ID:           feat.FrontMatter.ID,
+		Title:        feat.FrontMatter.Title,
+		Status:       feat.FrontMatter.Status,
+		Owner:        owner,
+		Priority:     feat.FrontMatter.Priority,
+		Complexity:   feat.FrontMatter.Complexity,
+		Epic:         feat.FrontMatter.Epic,
+		Labels:       labels,
+		Dependencies: deps,
+		Created:      feat.FrontMatter.Created,
+		Updated:      feat.FrontMatter.Updated,
+		Path:         filepath.ToSlash(rel),
+	}
+}
+
+func writeListEntries(w io.Writer, format string, entries []listEntry) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "title", "status", "owner", "priority", "complexity", "epic", "labels", "dependencies", "created", "updated", "path"}); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			record := []string{e.ID, e.Title, e.Status, e.Owner, e.Priority, e.Complexity, e.Epic,
+				strings.Join(e.Labels, ";"), strings.Join(e.Dependencies, ";"), e.Created, e.Updated, e.Path}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case "plain":
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.ID, e.Status, e.Owner, e.Title)
+		}
+		return nil
+	default:
+		if len(entries) == 0 {
+			fmt.Fprintln(w, "No features found")
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSTATUS\tOWNER\tP\tC\tUPDATED\tTITLE\tLABELS")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.ID, e.Status, e.Owner, e.Priority, e.Complexity, e.Updated, e.Title, strings.Join(e.Labels, ", "))
+		}
+		return tw.Flush()
+	}
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
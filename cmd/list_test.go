@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+var listFeatures = []seedFeature{
+	{ID: "FTR-0001", Status: "backlog", Title: "First Feature"},
+	{ID: "FTR-0002", Status: "review", Title: "Second Feature", Owner: "alice", Labels: []string{"backend"}, Updated: "2026-02-01"},
+}
+
+func TestListCommandFormats(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), listFeatures...)
+
+	out, err := runCommand(t, newListCommand())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !strings.Contains(out, "ID") || !strings.Contains(out, "FTR-0001") || !strings.Contains(out, "FTR-0002") {
+		t.Fatalf("unexpected table output: %s", out)
+	}
+
+	out, err = runCommand(t, newListCommand(), "--status", "review", "--format", "plain")
+	if err != nil {
+		t.Fatalf("list plain failed: %v", err)
+	}
+	if strings.TrimSpace(out) != "FTR-0002\treview\talice\tSecond Feature" {
+		t.Fatalf("unexpected plain output: %q", out)
+	}
+
+	out, err = runCommand(t, newListCommand(), "--format", "json", "--sort", "-id")
+	if err != nil {
+		t.Fatalf("list json failed: %v", err)
+	}
+	var entries []listEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "FTR-0002" {
+		t.Fatalf("unexpected json entries: %+v", entries)
+	}
+
+	out, err = runCommand(t, newListCommand(), "--format", "ndjson", "--label", "backend")
+	if err != nil {
+		t.Fatalf("list ndjson failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], "\"FTR-0002\"") {
+		t.Fatalf("unexpected ndjson output: %s", out)
+	}
+
+	out, err = runCommand(t, newListCommand(), "--format", "csv", "--updated-after", "2026-01-01")
+	if err != nil {
+		t.Fatalf("list csv failed: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(records) != 2 || records[1][0] != "FTR-0002" || records[1][7] != "backend" {
+		t.Fatalf("unexpected csv records: %v", records)
+	}
+
+	out, err = runCommand(t, newListCommand(), "--owner", "nobody")
+	if err != nil {
+		t.Fatalf("list empty failed: %v", err)
+	}
+	if !strings.Contains(out, "No features found") {
+		t.Fatalf("expected empty message, got %s", out)
+	}
+}
+
+func TestListCommandJSONEnvelope(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), listFeatures...)
+
+	out, err := runCommand(t, newListCommand(), "--owner", "unassigned,owner")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var payload struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Total    int         `json:"total"`
+			Features []listEntry `json:"features"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if !payload.Success || payload.Data.Total != 1 || payload.Data.Features[0].ID != "FTR-0001" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestListCommandErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	setupOptions(t, fix, false, false, false)
+
+	for _, args := range [][]string{
+		{"--format", "xml"},
+		{"--updated-before", "yesterday"},
+		{"--sort", "nope"},
+	} {
+		_, err := runCommand(t, newListCommand(), args...)
+		if err == nil || ExitCode(err) != ExitCodeValidation {
+			t.Fatalf("expected validation error for %v, got %v", args, err)
+		}
+	}
+
+	fix.WriteFile(t, "features/backlog/broken.md", []byte("no frontmatter"))
+	if _, err := runCommand(t, newListCommand()); ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error, got %v", err)
+	}
+}
+
+func TestListCommandQuery(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), listFeatures...)
+
+	out, err := runCommand(t, newListCommand(), "--query", "status in (backlog,review) and label:backend", "--format", "plain")
+	if err != nil {
+		t.Fatalf("list query failed: %v", err)
+	}
+	if strings.TrimSpace(out) != "FTR-0002\treview\talice\tSecond Feature" {
+		t.Fatalf("unexpected query output: %q", out)
+	}
+
+	out, err = runCommand(t, newListCommand(), "--query", "not is:locked", "--query", "owner:owner", "--format", "plain")
+	if err != nil || !strings.HasPrefix(out, "FTR-0001\t") || strings.Contains(out, "FTR-0002") {
+		t.Fatalf("unexpected combined query output: %v %q", err, out)
+	}
+
+	_, err = runCommand(t, newListCommand(), "--query", "status in (review")
+	if err == nil || ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "column 18") {
+		t.Fatalf("expected positioned validation error, got %v", err)
+	}
+
+	fix.WriteFile(t, "config.yaml", []byte("fields:\n  status: string\n"))
+	setupOptions(t, fix, false, false, false)
+	_, err = runCommand(t, newListCommand(), "--query", "status:review")
+	if err == nil || ExitCode(err) != ExitCodeSchema {
+		t.Fatalf("expected schema error for invalid custom fields, got %v", err)
+	}
+}
+
+func TestListFlagsMatchQuery(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	seedFeatures(t, fix, feature.NewManager(opts), append(listFeatures,
+		seedFeature{ID: "FTR-0003", Status: "review", Title: "Third Feature", Owner: "Alicia", Epic: "payments", Labels: []string{"Backend-API"}, Updated: "2026-03-15"},
+	)...)
+
+	cases := []struct {
+		flags []string
+		query string
+	}{
+		{[]string{"--status", "Review"}, "status:review"},
+		{[]string{"--owner", "ali*", "--label", "backend*"}, "owner:ali* and label:backend*"},
+		{[]string{"--owner", "unassigned,alicia"}, "owner in (unassigned,alicia)"},
+		{[]string{"--epic", "PAYMENTS"}, "epic:payments"},
+		{[]string{"--updated-after", "2026-02-01", "--updated-before", "2026-03-01"}, "updated >= 2026-02-01 and updated <= 2026-03-01"},
+	}
+	for _, tc := range cases {
+		byFlags, err := runCommand(t, newListCommand(), append(tc.flags, "--format", "plain")...)
+		if err != nil {
+			t.Fatalf("%v: list failed: %v", tc.flags, err)
+		}
+		byQuery, err := runCommand(t, newListCommand(), "--query", tc.query, "--format", "plain")
+		if err != nil {
+			t.Fatalf("%q: list failed: %v", tc.query, err)
+		}
+		if byFlags != byQuery || strings.TrimSpace(byFlags) == "" {
+			t.Fatalf("%v listed %q, --query %q listed %q", tc.flags, byFlags, tc.query, byQuery)
+		}
+	}
+}
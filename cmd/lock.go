@@ -70,6 +70,9 @@ func newLockCommand() *cobra.Command {
 				return respond(cmd, opts, true, message, data)
 			}
 
+			if !cmd.Flags().Changed("ttl") {
+				ttl = opts.Settings.Lock.TTL
+			}
 			if ttl <= 0 {
 				return WrapCLIError(ExitCodeValidation, fmt.Errorf("ttl must be positive"))
 			}
@@ -88,7 +91,7 @@ func newLockCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&ttl, "ttl", 30, "Lock TTL in minutes")
+	cmd.Flags().IntVar(&ttl, "ttl", 30, "Lock TTL in minutes (default from lock.ttl in config)")
 	cmd.Flags().StringVar(&owner, "owner", "", "Owner acquiring the lock")
 	cmd.Flags().BoolVar(&release, "release", false, "Release the lock")
 	cmd.Flags().BoolVar(&status, "status", false, "Show lock status")
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -12,10 +11,30 @@ import (
 
 func newMoveCommand() *cobra.Command {
 	var ownerFlag string
+	var sel selector
 	cmd := &cobra.Command{
 		Use:   "move <id> <status> [owner]",
 		Short: "Move a feature to a new status and optionally assign an owner",
+		Long: `Move a feature to a new status and optionally assign an owner.
+
+With --ids, --stdin or --where, the id argument is omitted and every selected
+feature is moved. Features that cannot move are reported and left untouched; the
+others are written together.
+
+Examples:
+  vb move FTR-0001 in-progress alice
+  vb move --where status=review --where owner=alice done
+  vb list --status blocked --format plain | vb move --stdin in-progress`,
 		Args: func(cmd *cobra.Command, args []string) error {
+			if sel.active() {
+				if len(args) < 1 {
+					return fmt.Errorf("requires status")
+				}
+				if len(args) > 2 {
+					return fmt.Errorf("too many arguments")
+				}
+				return nil
+			}
 			if len(args) < 2 {
 				return fmt.Errorf("requires feature id and status")
 			}
@@ -29,6 +48,20 @@ func newMoveCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			mgr := feature.NewManager(opts)
+			if sel.active() {
+				owner := ownerFlag
+				if owner == "" && len(args) == 2 {
+					owner = args[1]
+				}
+				ids, err := sel.resolve(cmd, opts, mgr)
+				if err != nil {
+					return err
+				}
+				results, _ := mgr.MoveFeatures(ids, args[0], owner)
+				return reportBulk(cmd, opts, "Moved", results, mgr.Patches())
+			}
+
 			id := args[0]
 			status := args[1]
 			owner := ownerFlag
@@ -36,19 +69,9 @@ func newMoveCommand() *cobra.Command {
 				owner = args[2]
 			}
 
-			mgr := feature.NewManager(opts)
 			feat, summary, err := mgr.MoveFeature(id, status, owner)
 			if err != nil {
-				switch {
-				case errors.Is(err, feature.ErrNotFound):
-					return WrapCLIError(ExitCodeNotFound, err)
-				case errors.Is(err, feature.ErrInvalidTransition):
-					return WrapCLIError(ExitCodeInvalidTransition, err)
-				case errors.Is(err, feature.ErrDependencyBlocked):
-					return WrapCLIError(ExitCodeDependency, err)
-				default:
-					return WrapCLIError(ExitCodeFilesystem, err)
-				}
+				return WrapCLIError(featureExitCode(err), err)
 			}
 
 			rel, _ := filepath.Rel(opts.RootDir, feat.Path)
@@ -59,7 +82,7 @@ func newMoveCommand() *cobra.Command {
 				"path":    rel,
 				"summary": summary,
 			}
-			if err := respond(cmd, opts, true, summary, data); err != nil {
+			if err := respondWithPatches(cmd, opts, true, summary, data, mgr.Patches()); err != nil {
 				return err
 			}
 			return nil
@@ -67,5 +90,6 @@ func newMoveCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&ownerFlag, "owner", "", "Set the owner while moving")
+	sel.register(cmd)
 	return cmd
 }
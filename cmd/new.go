@@ -39,7 +39,7 @@ func newNewCommand() *cobra.Command {
 				"title":  feat.FrontMatter.Title,
 				"labels": feat.FrontMatter.Labels,
 			}
-			if err := respond(cmd, opts, true, message, data); err != nil {
+			if err := respondWithPatches(cmd, opts, true, message, data, manager.Patches()); err != nil {
 				return err
 			}
 			return nil
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/feature"
+)
+
+func newUndoCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo",
+		Short: "Revert your most recent feature operation",
+		Long: `Revert the newest vb new, update, move or delete you ($USER) made that has not
+been undone yet. Running it again steps further back. Bulk operations are undone
+one feature at a time.
+
+The feature is restored from the snapshot kept in .virtualboard/.trash/ when the
+operation ran, or removed if the operation created it. The undo is recorded in
+the audit log as a revert; vb revert <hash> of that entry redoes the operation.
+If the feature changed since, by someone else or by hand, use vb revert --force.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			mgr := feature.NewManager(opts)
+			result, err := mgr.Undo()
+			if err != nil {
+				return revertError(err)
+			}
+			return reportRevert(cmd, opts, mgr, result)
+		},
+	}
+}
+
+func newRevertCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "revert <audit-entry-hash>",
+		Short: "Revert the feature operation recorded by an audit entry",
+		Long: `Revert the vb new, update, move, delete or revert recorded by the audit entry
+whose entry_hash starts with the given prefix (at least 4 characters; see
+vb audit log --format json). Created features are removed; updated, moved and
+deleted features are restored exactly as they were from .virtualboard/.trash/.
+Reverting a revert redoes the original operation.
+
+The feature must not have changed since the entry, through vb or by hand, apart
+from changes that were themselves reverted; --force restores it anyway and
+discards those changes.
+Operations recorded before snapshots were kept cannot be reverted.
+
+Examples:
+  vb audit log --feature FTR-0001 --format json
+  vb revert 3f9a1c2b
+  vb revert 3f9a1c2b --force --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			mgr := feature.NewManager(opts)
+			result, err := mgr.Revert(args[0], force)
+			if err != nil {
+				return revertError(err)
+			}
+			return reportRevert(cmd, opts, mgr, result)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Revert even if the feature changed since")
+	return cmd
+}
+
+func revertError(err error) error {
+	switch {
+	case errors.Is(err, feature.ErrNotRevertible), errors.Is(err, feature.ErrRevertConflict):
+		return WrapCLIError(ExitCodeValidation, err)
+	default:
+		return WrapCLIError(ExitCodeFilesystem, err)
+	}
+}
+
+func reportRevert(cmd *cobra.Command, opts *config.Options, mgr *feature.Manager, result *feature.RevertResult) error {
+	reverted := result.Reverted
+	short := reverted.EntryHash
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	message := fmt.Sprintf("Reverted %s of %s (%s): removed the feature", reverted.Action, result.ID, short)
+	data := map[string]interface{}{
+		"id":       result.ID,
+		"reverted": reverted.EntryHash,
+		"action":   reverted.Action,
+		"removed":  result.Removed,
+	}
+	if !result.Removed {
+		rel, _ := filepath.Rel(opts.RootDir, result.Path)
+		message = fmt.Sprintf("Reverted %s of %s (%s): restored %s", reverted.Action, result.ID, short, rel)
+		data["path"] = rel
+	}
+	return respondWithPatches(cmd, opts, true, message, data, mgr.Patches())
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestUndoAndRevertCommands(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	feat, err := mgr.CreateFeature("Regret", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := runCommand(t, newDeleteCommand(), feat.FrontMatter.ID, "--force"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	out, err := runCommand(t, newUndoCommand())
+	if err != nil || !strings.HasPrefix(out, "Reverted delete of FTR-0001 (") || !strings.HasSuffix(out, "): restored features/backlog/FTR-0001-regret.md\n") {
+		t.Fatalf("unexpected undo output: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(feat.Path); err != nil {
+		t.Fatalf("expected the feature back: %v", err)
+	}
+
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	undo := audit.Filter{Actions: []string{"revert"}}.Apply(entries)[0]
+
+	opts.DryRun = true
+	out, err = runCommand(t, newRevertCommand(), undo.EntryHash[:8])
+	if err != nil || !strings.Contains(out, "): removed the feature\n") || !strings.Contains(out, "deleted file mode") {
+		t.Fatalf("unexpected dry-run revert output: %v\n%s", err, out)
+	}
+	opts.DryRun = false
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newRevertCommand(), undo.EntryHash[:8])
+	if err != nil {
+		t.Fatalf("redo failed: %v", err)
+	}
+	var payload struct {
+		Success bool                   `json:"success"`
+		Data    map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if !payload.Success || payload.Data["action"] != "revert" || payload.Data["removed"] != true || payload.Data["reverted"] != undo.EntryHash {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if _, err := os.Stat(feat.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected the delete to be redone, got %v", err)
+	}
+
+	if _, err := runCommand(t, newRevertCommand(), "ab"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if _, err := runCommand(t, newRevertCommand()); err == nil {
+		t.Fatal("expected an argument error")
+	}
+
+	fix.WriteFile(t, "audit.jsonl", []byte("{broken\n"))
+	if _, err := runCommand(t, newUndoCommand()); err == nil || !strings.Contains(err.Error(), "no operation") {
+		t.Fatalf("expected the malformed line to be skipped, got %v", err)
+	}
+	if err := os.Remove(fix.Path("audit.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(fix.Path("audit.jsonl"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, newUndoCommand()); ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected a filesystem error, got %v", err)
+	}
+}
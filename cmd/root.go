@@ -21,9 +21,11 @@ var (
 				return nil
 			}
 			opts := config.New()
+			opts.AllowInvalidConfig = cmd.Annotations[annotationAllowInvalidConfig] == "true"
 			if err := opts.Init(flagRoot, flagJSON, flagVerbose, flagDryRun, flagLogFile); err != nil {
 				return err
 			}
+			applyFlagOverrides(cmd, opts)
 			cmd.SetContext(opts.WithContext(cmd.Context()))
 			return nil
 		},
@@ -36,6 +38,17 @@ var (
 	flagLogFile string
 )
 
+// annotationAllowInvalidConfig marks commands that must run even when config.yaml or
+// workflow.yaml is invalid, because they are how the user inspects or repairs it.
+const annotationAllowInvalidConfig = "vb.allow-invalid-config"
+
+// applyFlagOverrides re-applies explicitly set flags over config files and VB_* variables.
+func applyFlagOverrides(cmd *cobra.Command, opts *config.Options) {
+	if flag := cmd.Flags().Lookup("json"); flag != nil && flag.Changed {
+		opts.JSONOutput = flagJSON
+	}
+}
+
 // Execute runs the root command.
 func Execute() error {
 	registerCommands()
@@ -69,15 +82,25 @@ func registerCommands() {
 	rootCmd.PersistentFlags().StringVar(&flagLogFile, "log-file", "", "File to write verbose logs")
 
 	rootCmd.AddCommand(newNewCommand())
+	rootCmd.AddCommand(newListCommand())
+	rootCmd.AddCommand(newShowCommand())
+	rootCmd.AddCommand(newSearchCommand())
 	rootCmd.AddCommand(newMoveCommand())
 	rootCmd.AddCommand(newUpdateCommand())
 	rootCmd.AddCommand(newDeleteCommand())
+	rootCmd.AddCommand(newUndoCommand())
+	rootCmd.AddCommand(newRevertCommand())
 	rootCmd.AddCommand(newIndexCommand())
+	rootCmd.AddCommand(newGraphCommand())
+	rootCmd.AddCommand(newImpactCommand())
+	rootCmd.AddCommand(newEpicCommand())
 	rootCmd.AddCommand(newValidateCommand())
 	rootCmd.AddCommand(newTemplateCommand())
 	rootCmd.AddCommand(newLockCommand())
+	rootCmd.AddCommand(newAuditCommand())
 	rootCmd.AddCommand(newInitCommand())
 	rootCmd.AddCommand(newInstallCommand())
+	rootCmd.AddCommand(newWhereCommand())
 	rootCmd.AddCommand(newVersionCommand())
 	rootCmd.AddCommand(newUpgradeCommand())
 }
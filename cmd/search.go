@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/search"
+)
+
+// searchEntry is the serialised form of a search result.
+type searchEntry struct {
+	Kind    string  `json:"kind"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Status  string  `json:"status,omitempty"`
+	Path    string  `json:"path"`
+	Score   float64 `json:"score"`
+	Section string  `json:"section"`
+	Snippet string  `json:"snippet"`
+}
+
+var searchTypes = []string{search.KindFeature, search.KindSpec}
+
+func newSearchCommand() *cobra.Command {
+	var kind string
+	var limit int
+	var rebuild bool
+
+	cmd := &cobra.Command{
+		Use:   "search <terms...>",
+		Short: "Full-text search across features and system specs",
+		Long: `Search feature titles, frontmatter and sections, and system spec bodies.
+
+Results must contain every term and are ranked by relevance, with title matches
+weighing most. Words are matched on their stem, so "limit" also finds "limiting";
+a trailing * matches a prefix and "quoted phrases" must appear as written.
+
+The index is cached in .virtualboard/.cache and only files changed since the last
+search are read again.
+
+Examples:
+  vb search rate limiting
+  vb search '"rate limiting"' --type feature
+  vb search auth* --limit 5`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			kind = strings.ToLower(strings.TrimSpace(kind))
+			if kind != "" && !containsString(searchTypes, kind) {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("unknown type %s (allowed: %s)", kind, strings.Join(searchTypes, ", ")))
+			}
+			if limit < 0 {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("--limit must not be negative"))
+			}
+			terms := strings.Join(args, " ")
+
+			cachePath := search.CachePath(opts.RootDir)
+			ix := search.NewIndex()
+			if !rebuild {
+				if ix, err = search.Load(cachePath); err != nil {
+					return WrapCLIError(ExitCodeFilesystem, err)
+				}
+			}
+			stats, err := search.Update(ix, opts.RootDir)
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			if stats.Changed() || rebuild {
+				if opts.DryRun {
+					opts.Logger().WithField("component", "search").WithField("path", cachePath).Info("Skipping search index write in dry-run mode")
+				} else if err := ix.Save(cachePath); err != nil {
+					return WrapCLIError(ExitCodeFilesystem, err)
+				}
+			}
+
+			results := ix.Search(terms, search.Options{Kind: kind, Limit: limit})
+			entries := make([]searchEntry, 0, len(results))
+			for _, r := range results {
+				entries = append(entries, searchEntry{
+					Kind:    r.Doc.Kind,
+					ID:      r.Doc.ID,
+					Title:   r.Doc.Title,
+					Status:  r.Doc.Status,
+					Path:    r.Doc.Path,
+					Score:   r.Score,
+					Section: r.Section,
+					Snippet: r.Snippet,
+				})
+			}
+
+			message := fmt.Sprintf("%d result(s) for %q", len(entries), terms)
+			if opts.JSONOutput {
+				return respond(cmd, opts, true, message, map[string]interface{}{
+					"query":   terms,
+					"results": entries,
+					"index":   stats,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			for _, e := range entries {
+				status := ""
+				if e.Status != "" {
+					status = fmt.Sprintf(" [%s]", e.Status)
+				}
+				fmt.Fprintf(out, "%s  %s%s  (%s, score %.2f)\n", e.ID, e.Title, status, e.Kind, e.Score)
+				fmt.Fprintf(out, "  %s § %s\n", e.Path, e.Section)
+				if e.Snippet != "" {
+					fmt.Fprintf(out, "  %s\n", e.Snippet)
+				}
+				fmt.Fprintln(out)
+			}
+			if len(entries) == 0 {
+				message = fmt.Sprintf("No results for %q", terms)
+			}
+			fmt.Fprintln(out, message)
+			if len(stats.Skipped) > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %d unparsable file(s): %s (run vb validate for details)\n", len(stats.Skipped), strings.Join(stats.Skipped, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kind, "type", "", "Only return results of this type: feature, spec")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of results (0 for all)")
+	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Discard the cached index and rebuild it from scratch")
+	return cmd
+}
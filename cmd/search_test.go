@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/search"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+var searchFeatures = []seedFeature{
+	{ID: "FTR-0001", Status: "in-progress", Title: "Public API throttling", Body: "## Summary\nIntroduce rate limiting per API key.\n"},
+	{ID: "FTR-0002", Status: "backlog", Title: "Unrelated"},
+}
+
+// gatewaySpec mentions rate limiting, like FTR-0001.
+const gatewaySpec = "---\nspec_type: architecture\ntitle: Gateway\nstatus: approved\nlast_updated: 2026-01-01\napplicability: []\n---\n\n## Limits\nThe gateway enforces rate limiting.\n"
+
+func TestSearchCommandText(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	seedFeatures(t, fix, mgr, searchFeatures...)
+	fix.WriteFile(t, "specs/gateway.md", []byte(gatewaySpec))
+	fix.WriteFile(t, "specs/broken.md", []byte("no frontmatter"))
+
+	out, err := runCommand(t, newSearchCommand(), "rate", "limiting")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	for _, want := range []string{
+		"FTR-0001  Public API throttling [in-progress]  (feature, score",
+		"§ Summary",
+		"Introduce rate limiting per API key.",
+		"gateway.md  Gateway [approved]  (spec, score",
+		"2 result(s) for \"rate limiting\"",
+		"Skipped 1 unparsable file(s): specs/broken.md",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(search.CachePath(opts.RootDir)); err != nil {
+		t.Fatalf("expected index to be cached: %v", err)
+	}
+
+	out, err = runCommand(t, newSearchCommand(), "nothing-matches-this")
+	if err != nil || !strings.Contains(out, "No results for \"nothing-matches-this\"") {
+		t.Fatalf("unexpected empty search output: %v\n%s", err, out)
+	}
+}
+
+func TestSearchCommandJSON(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	mgr := feature.NewManager(opts)
+	seedFeatures(t, fix, mgr, searchFeatures...)
+	fix.WriteFile(t, "specs/gateway.md", []byte(gatewaySpec))
+
+	out, err := runCommand(t, newSearchCommand(), "rate", "--type", "spec", "--rebuild")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var payload struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Results []searchEntry `json:"results"`
+			Index   search.Stats  `json:"index"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if !payload.Success || len(payload.Data.Results) != 1 || payload.Data.Results[0].Path != "specs/gateway.md" || payload.Data.Results[0].Section != "Limits" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if payload.Data.Index.Documents != 3 || payload.Data.Index.Indexed != 3 {
+		t.Fatalf("expected a full rebuild, got %+v", payload.Data.Index)
+	}
+}
+
+func TestSearchCommandDryRunSkipsCache(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, true)
+	mgr := feature.NewManager(opts)
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Rate limits")
+
+	out, err := runCommand(t, newSearchCommand(), "rate")
+	if err != nil || !strings.Contains(out, "1 result(s)") {
+		t.Fatalf("dry-run search failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(search.CachePath(opts.RootDir)); !os.IsNotExist(err) {
+		t.Fatalf("expected no cache in dry-run mode: %v", err)
+	}
+}
+
+func TestSearchCommandErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+
+	if _, err := runCommand(t, newSearchCommand(), "x", "--type", "epic"); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error for unknown type, got %v", err)
+	}
+	if _, err := runCommand(t, newSearchCommand(), "x", "--limit", "-1"); err == nil || ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error for negative limit, got %v", err)
+	}
+	if _, err := runCommand(t, newSearchCommand()); err == nil {
+		t.Fatalf("expected error without terms")
+	}
+
+	cache := search.CachePath(opts.RootDir)
+	if err := os.MkdirAll(cache, 0o750); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if _, err := runCommand(t, newSearchCommand(), "x"); err == nil || ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error for unreadable cache, got %v", err)
+	}
+	if _, err := runCommand(t, newSearchCommand(), "x", "--rebuild"); err == nil || ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error for unwritable cache, got %v", err)
+	}
+}
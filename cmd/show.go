@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/lock"
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// relatedFeature summarises a dependency or dependent of the shown feature.
+type relatedFeature struct {
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"`
+	Owner  string `json:"owner,omitempty"`
+}
+
+// showSection is a single H2 section of the feature body.
+type showSection struct {
+	Name      string             `json:"name"`
+	Content   string             `json:"content"`
+	Checklist *feature.Checklist `json:"checklist,omitempty"`
+}
+
+func newShowCommand() *cobra.Command {
+	var auditLimit int
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a feature with its dependencies, lock and recent audit history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+			if auditLimit < 0 {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("--audit must not be negative"))
+			}
+
+			mgr := feature.NewManager(opts)
+			feat, err := mgr.LoadByID(args[0])
+			if err != nil {
+				if errors.Is(err, feature.ErrNotFound) {
+					return WrapCLIError(ExitCodeNotFound, err)
+				}
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			id := feat.FrontMatter.ID
+
+			// Unrelated files that fail to parse only hide their own features.
+			all, err := mgr.List()
+			var invalid *feature.InvalidFileError
+			if errors.As(err, &invalid) {
+				warnInvalidFiles(cmd, opts.RootDir, invalid)
+			} else if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+			byID := make(map[string]*feature.Feature, len(all))
+			for _, f := range all {
+				byID[feature.Key(f.FrontMatter.ID)] = f
+			}
+
+			dependencies := []relatedFeature{}
+			for _, dep := range feat.FrontMatter.Dependencies {
+				dep = strings.TrimSpace(dep)
+				if dep == "" {
+					continue
+				}
+				if depFeat, ok := byID[feature.Key(dep)]; ok {
+					dependencies = append(dependencies, newRelatedFeature(depFeat))
+				} else {
+					dependencies = append(dependencies, relatedFeature{ID: dep, Status: "missing"})
+				}
+			}
+
+			dependents := []relatedFeature{}
+			for _, other := range all {
+				for _, dep := range other.FrontMatter.Dependencies {
+					if feature.Key(dep) == feature.Key(id) {
+						dependents = append(dependents, newRelatedFeature(other))
+						break
+					}
+				}
+			}
+
+			checklists := map[string]feature.Checklist{}
+			for _, c := range feat.Checklists() {
+				checklists[c.Section] = c
+			}
+			order, contents := feature.ExtractSections(feat.Body)
+			sections := make([]showSection, 0, len(order))
+			for _, name := range order {
+				section := showSection{Name: name, Content: contents[name]}
+				if c, ok := checklists[name]; ok {
+					section.Checklist = &c
+				}
+				sections = append(sections, section)
+			}
+			checklist := feat.ChecklistTotal()
+
+			lockInfo, err := lock.NewManager(opts).Load(id)
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+
+			history, err := recentAuditEntries(cmd, mgr.AuditPath(), id, auditLimit)
+			if err != nil {
+				return WrapCLIError(ExitCodeFilesystem, err)
+			}
+
+			rel, _ := filepath.Rel(opts.RootDir, feat.Path)
+
+			if opts.JSONOutput {
+				data := map[string]interface{}{
+					"id":           id,
+					"path":         filepath.ToSlash(rel),
+					"frontmatter":  feat.FrontMatter,
+					"sections":     sections,
+					"checklist":    checklist,
+					"dependencies": dependencies,
+					"dependents":   dependents,
+					"audit":        history,
+				}
+				if lockInfo != nil {
+					data["lock"] = lockPayload(lockInfo)
+				} else {
+					data["lock"] = nil
+				}
+				return respond(cmd, opts, true, "", data)
+			}
+
+			out := cmd.OutOrStdout()
+			printFrontMatter(out, feat.FrontMatter, filepath.ToSlash(rel))
+			if checklist.Total > 0 {
+				fmt.Fprintf(out, "  %-11s %s\n", "Checklist:", checklist)
+			}
+			for _, section := range sections {
+				if section.Checklist != nil {
+					fmt.Fprintf(out, "\n## %s [%s]\n", section.Name, section.Checklist)
+				} else {
+					fmt.Fprintf(out, "\n## %s\n", section.Name)
+				}
+				if section.Content != "" {
+					fmt.Fprintln(out, section.Content)
+				}
+			}
+			printRelated(out, "Dependencies", dependencies)
+			printRelated(out, "Dependents", dependents)
+
+			fmt.Fprintln(out)
+			if lockInfo == nil {
+				fmt.Fprintln(out, "Lock: none")
+			} else {
+				state := "active"
+				if lockInfo.Expired() {
+					state = "expired"
+				}
+				fmt.Fprintf(out, "Lock: %s (owner %s, expires %s)\n", state, lockInfo.Owner, lockInfo.ExpiresAt().Format(time.RFC3339))
+			}
+
+			if auditLimit > 0 {
+				fmt.Fprintln(out, "\nRecent activity:")
+				if len(history) == 0 {
+					fmt.Fprintln(out, "  (none)")
+				}
+				for _, entry := range history {
+					line := fmt.Sprintf("  %s %s by %s", entry.Timestamp, entry.Action, entry.Actor)
+					if details := auditDetails(entry); details != "" {
+						line += ": " + details
+					}
+					fmt.Fprintln(out, line)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&auditLimit, "audit", 5, "Number of recent audit entries to show (0 to hide)")
+	return cmd
+}
+
+// warnInvalidFiles tells the user which feature files were skipped because they
+// failed to parse.
+func warnInvalidFiles(cmd *cobra.Command, root string, invalid *feature.InvalidFileError) {
+	paths := make([]string, len(invalid.Files))
+	for i, f := range invalid.Files {
+		paths[i] = util.RelativePath(root, f.Path)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %d unparsable file(s): %s (run vb validate for details)\n", len(paths), strings.Join(paths, ", "))
+}
+
+func newRelatedFeature(feat *feature.Feature) relatedFeature {
+	return relatedFeature{
+		ID:     feat.FrontMatter.ID,
+		Title:  feat.FrontMatter.Title,
+		Status: feat.FrontMatter.Status,
+		Owner:  feat.FrontMatter.Owner,
+	}
+}
+
+// recentAuditEntries returns up to limit of the newest audit entries for the feature, newest last.
+func recentAuditEntries(cmd *cobra.Command, path, id string, limit int) ([]audit.Entry, error) {
+	matched := []audit.Entry{}
+	if limit == 0 {
+		return matched, nil
+	}
+	entries, err := readAuditEntries(cmd, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.FeatureID, id) {
+			matched = append(matched, entry)
+		}
+	}
+	if len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched, nil
+}
+
+func printFrontMatter(out io.Writer, fm feature.FrontMatter, path string) {
+	fmt.Fprintf(out, "%s: %s\n", fm.ID, fm.Title)
+	rows := [][2]string{
+		{"Status", fm.Status},
+		{"Owner", fm.Owner},
+		{"Priority", fm.Priority},
+		{"Complexity", fm.Complexity},
+		{"Epic", fm.Epic},
+		{"Labels", strings.Join(fm.Labels, ", ")},
+		{"Created", fm.Created},
+		{"Updated", fm.Updated},
+		{"Risk notes", fm.RiskNotes},
+		{"Path", path},
+	}
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		fmt.Fprintf(out, "  %-11s %s\n", row[0]+":", row[1])
+	}
+}
+
+func printRelated(out io.Writer, heading string, related []relatedFeature) {
+	fmt.Fprintf(out, "\n%s:\n", heading)
+	if len(related) == 0 {
+		fmt.Fprintln(out, "  (none)")
+		return
+	}
+	for _, r := range related {
+		if r.Title == "" {
+			fmt.Fprintf(out, "  - %s [%s]\n", r.ID, r.Status)
+			continue
+		}
+		fmt.Fprintf(out, "  - %s %s [%s]\n", r.ID, r.Title, r.Status)
+	}
+}
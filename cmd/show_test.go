@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/lock"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestShowCommandText(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	created, err := mgr.CreateFeature("Shown Feature", []string{"ux"})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, _, err := mgr.MoveFeature(created.FrontMatter.ID, "in-progress", "alice"); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	dep := buildFeatureFile(t, fix, mgr, "FTR-0010", "done", "Base Work")
+	created, _ = mgr.LoadByID(created.FrontMatter.ID)
+	created.FrontMatter.Dependencies = []string{strings.ToLower(dep.FrontMatter.ID), "FTR-0404"}
+	created.Body += "\n## Acceptance Criteria\n- [x] One\n- [ ] Two\n- [ ] Three\n"
+	if err := mgr.Save(created); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	downstream := buildFeatureFile(t, fix, mgr, "FTR-0020", "backlog", "Follow Up")
+	downstream.FrontMatter.Dependencies = []string{created.FrontMatter.ID}
+	if err := mgr.Save(downstream); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if _, err := lock.NewManager(opts).Acquire(created.FrontMatter.ID, "bob", 30, false); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+
+	out, err := runCommand(t, newShowCommand(), strings.ToLower(created.FrontMatter.ID))
+	if err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	for _, want := range []string{
+		"FTR-0001: Shown Feature",
+		"Owner:      alice",
+		"## Summary",
+		"  Checklist:  1/3 (33%)\n",
+		"## Acceptance Criteria [1/3 (33%)]\n- [x] One",
+		"FTR-0010 Base Work [done]",
+		"FTR-0404 [missing]",
+		"FTR-0020 Follow Up [backlog]",
+		"Lock: active (owner bob",
+		"create by",
+		"move by",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out, err = runCommand(t, newShowCommand(), "FTR-0010", "--audit", "0")
+	if err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	if !strings.Contains(out, "Lock: none") || strings.Contains(out, "Recent activity") || !strings.Contains(out, "Dependencies:\n  (none)") || strings.Contains(out, "Checklist:") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	out, err = runCommand(t, newShowCommand(), "FTR-0020", "--audit", "1")
+	if err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	if !strings.Contains(out, "Recent activity:\n  (none)") {
+		t.Fatalf("expected empty activity:\n%s", out)
+	}
+}
+
+func TestShowCommandJSON(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	mgr := feature.NewManager(opts)
+	created, err := mgr.CreateFeature("Json Feature", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	created.Body += "\n## Acceptance Criteria\n- [x] One\n- [ ] Two\n"
+	if err := mgr.Save(created); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	for _, status := range []string{"in-progress", "review", "done"} {
+		if _, _, err := mgr.MoveFeature(created.FrontMatter.ID, status, ""); err != nil {
+			t.Fatalf("move failed: %v", err)
+		}
+	}
+
+	out, err := runCommand(t, newShowCommand(), created.FrontMatter.ID, "--audit", "2")
+	if err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			ID       string `json:"id"`
+			Sections []struct {
+				Name      string             `json:"name"`
+				Checklist *feature.Checklist `json:"checklist"`
+			} `json:"sections"`
+			Checklist feature.Checklist `json:"checklist"`
+			Audit     []struct {
+				Details string `json:"details"`
+			} `json:"audit"`
+			Lock interface{} `json:"lock"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if payload.Data.ID != created.FrontMatter.ID || len(payload.Data.Sections) != 3 || payload.Data.Lock != nil {
+		t.Fatalf("unexpected payload: %+v", payload.Data)
+	}
+	if payload.Data.Sections[0].Checklist != nil || payload.Data.Sections[2].Checklist == nil || payload.Data.Sections[2].Checklist.Percent != 50 || payload.Data.Checklist.Total != 2 {
+		t.Fatalf("unexpected checklist payload: %+v", payload.Data)
+	}
+	if len(payload.Data.Audit) != 2 || payload.Data.Audit[1].Details != "status=done" {
+		t.Fatalf("expected last two audit entries, got %+v", payload.Data.Audit)
+	}
+
+	if _, err := lock.NewManager(opts).Acquire(created.FrontMatter.ID, "bob", 30, false); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	out, err = runCommand(t, newShowCommand(), created.FrontMatter.ID)
+	if err != nil || !strings.Contains(out, "\"owner\": \"bob\"") {
+		t.Fatalf("expected lock payload, got %v\n%s", err, out)
+	}
+}
+
+func TestShowCommandErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	if _, err := runCommand(t, newShowCommand(), "FTR-9999"); ExitCode(err) != ExitCodeNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := runCommand(t, newShowCommand(), "FTR-0001", "--audit", "-1"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Good")
+	fix.WriteFile(t, "audit.jsonl", []byte("{not json\n"))
+	if out, err := runCommand(t, newShowCommand(), "FTR-0001"); err != nil || !strings.Contains(out, "Skipped 1 malformed audit line(s): 1") {
+		t.Fatalf("expected a warning for the corrupt audit line, got %v\n%s", err, out)
+	}
+
+	fix.WriteFile(t, "locks/FTR-0001.lock", []byte("garbage"))
+	if _, err := runCommand(t, newShowCommand(), "FTR-0001"); ExitCode(err) != ExitCodeFilesystem {
+		t.Fatalf("expected filesystem error for corrupt lock, got %v", err)
+	}
+
+	if err := os.Remove(fix.Path("locks", "FTR-0001.lock")); err != nil {
+		t.Fatal(err)
+	}
+	fix.WriteFile(t, "features/backlog/broken.md", []byte("no frontmatter"))
+	if out, err := runCommand(t, newShowCommand(), "FTR-0001"); err != nil || !strings.Contains(out, "Skipped 1 unparsable file(s): features/backlog/broken.md") || !strings.Contains(out, "FTR-0001: Good") {
+		t.Fatalf("an unrelated invalid file should only be skipped, got %v\n%s", err, out)
+	}
+}
@@ -54,7 +54,7 @@ func newTemplateApplyCommand() *cobra.Command {
 			data := map[string]interface{}{
 				"id": id,
 			}
-			return respond(cmd, opts, true, message, data)
+			return respondWithPatches(cmd, opts, true, message, data, mgr.Patches())
 		},
 	}
 }
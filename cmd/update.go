@@ -13,22 +13,73 @@ import (
 func newUpdateCommand() *cobra.Command {
 	var fieldPairs []string
 	var sectionPairs []string
+	var addLabels []string
+	var removeLabels []string
+	var sel selector
 
 	cmd := &cobra.Command{
 		Use:   "update <id> [--field key=value ...] [--body-section section=content ...]",
 		Short: "Update frontmatter fields or body sections of a feature",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Update frontmatter fields or body sections of a feature.
+
+With --ids, --stdin or --where, the id argument is omitted and the same updates
+are applied to every selected feature and written together.
+
+Examples:
+  vb update FTR-0001 --field priority=high
+  vb update --where epic=checkout --add-label q3`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if sel.active() {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts, err := options()
 			if err != nil {
 				return err
 			}
-			id := args[0]
-			if len(fieldPairs) == 0 && len(sectionPairs) == 0 {
+			if len(fieldPairs) == 0 && len(sectionPairs) == 0 && len(addLabels) == 0 && len(removeLabels) == 0 {
 				return fmt.Errorf("no updates provided")
 			}
 
+			apply := func(feat *feature.Feature) error {
+				for _, pair := range fieldPairs {
+					key, value, err := splitPair(pair)
+					if err != nil {
+						return WrapCLIError(ExitCodeValidation, err)
+					}
+					if err := feat.SetField(key, value); err != nil {
+						return WrapCLIError(ExitCodeValidation, err)
+					}
+				}
+
+				for _, pair := range sectionPairs {
+					key, value, err := splitPair(pair)
+					if err != nil {
+						return WrapCLIError(ExitCodeValidation, err)
+					}
+					if err := feat.SetSection(key, value); err != nil {
+						return WrapCLIError(ExitCodeValidation, err)
+					}
+				}
+
+				feat.AddLabels(addLabels...)
+				feat.RemoveLabels(removeLabels...)
+				return nil
+			}
+
 			mgr := feature.NewManager(opts)
+			if sel.active() {
+				ids, err := sel.resolve(cmd, opts, mgr)
+				if err != nil {
+					return err
+				}
+				results, _ := mgr.UpdateFeatures(ids, apply)
+				return reportBulk(cmd, opts, "Updated", results, mgr.Patches())
+			}
+
+			id := args[0]
 			feat, err := mgr.LoadByID(id)
 			if err != nil {
 				if errors.Is(err, feature.ErrNotFound) {
@@ -37,24 +88,8 @@ func newUpdateCommand() *cobra.Command {
 				return WrapCLIError(ExitCodeFilesystem, err)
 			}
 
-			for _, pair := range fieldPairs {
-				key, value, err := splitPair(pair)
-				if err != nil {
-					return WrapCLIError(ExitCodeValidation, err)
-				}
-				if err := feat.SetField(key, value); err != nil {
-					return WrapCLIError(ExitCodeValidation, err)
-				}
-			}
-
-			for _, pair := range sectionPairs {
-				key, value, err := splitPair(pair)
-				if err != nil {
-					return WrapCLIError(ExitCodeValidation, err)
-				}
-				if err := feat.SetSection(key, value); err != nil {
-					return WrapCLIError(ExitCodeValidation, err)
-				}
+			if err := apply(feat); err != nil {
+				return err
 			}
 
 			if err := mgr.UpdateFeature(feat); err != nil {
@@ -67,7 +102,7 @@ func newUpdateCommand() *cobra.Command {
 				"fields":   fieldPairs,
 				"sections": sectionPairs,
 			}
-			if err := respond(cmd, opts, true, message, data); err != nil {
+			if err := respondWithPatches(cmd, opts, true, message, data, mgr.Patches()); err != nil {
 				return err
 			}
 			return nil
@@ -76,6 +111,9 @@ func newUpdateCommand() *cobra.Command {
 
 	cmd.Flags().StringArrayVar(&fieldPairs, "field", nil, "Frontmatter field to update (key=value)")
 	cmd.Flags().StringArrayVar(&sectionPairs, "body-section", nil, "Body section to update (name=content)")
+	cmd.Flags().StringSliceVar(&addLabels, "add-label", nil, "Add labels, keeping existing ones")
+	cmd.Flags().StringSliceVar(&removeLabels, "remove-label", nil, "Remove labels")
+	sel.register(cmd)
 	return cmd
 }
 
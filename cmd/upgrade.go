@@ -12,9 +12,10 @@ import (
 
 func newUpgradeCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "upgrade",
-		Short: "Upgrade vb to the latest version",
-		Long:  "Check for a newer version of vb on GitHub releases and upgrade the binary if available.",
+		Use:         "upgrade",
+		Short:       "Upgrade vb to the latest version",
+		Long:        "Check for a newer version of vb on GitHub releases and upgrade the binary if available.",
+		Annotations: map[string]string{annotationAllowInvalidConfig: "true"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts, err := options()
 			if err != nil {
@@ -3,21 +3,33 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/virtualboard/vb-cli/internal/config"
 	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/report"
+	"github.com/virtualboard/vb-cli/internal/rules"
 	"github.com/virtualboard/vb-cli/internal/spec"
 	tpl "github.com/virtualboard/vb-cli/internal/template"
+	"github.com/virtualboard/vb-cli/internal/util"
 	"github.com/virtualboard/vb-cli/internal/validator"
+	"github.com/virtualboard/vb-cli/internal/vcs"
 )
 
 func newValidateCommand() *cobra.Command {
 	var fix bool
 	var onlyFeatures bool
 	var onlySpecs bool
+	var only []string
+	var disabled []string
+	var format string
+	var changedSince string
 
 	cmd := &cobra.Command{
 		Use:   "validate [id|name|all]",
@@ -31,7 +43,30 @@ Examples:
   vb validate --only-features    # Validate only features
   vb validate --only-specs       # Validate only specs
   vb validate FEAT-001           # Validate specific feature
-  vb validate tech-stack.md      # Validate specific spec`,
+  vb validate tech-stack.md      # Validate specific spec
+  vb validate --rule filename    # Run only the filename rule
+  vb validate --disable-rule title-length
+  vb validate --format sarif > vb.sarif
+  vb validate --changed-since origin/main
+
+Every finding names the rule that reported it. Only error-severity findings fail
+validation; warnings and info are reported without changing the exit code.
+
+--format sarif|junit|github|checkstyle writes per-file, per-line diagnostics for
+CI code scanning, test reports and pull request annotations instead of text.
+
+--fix moves files into the directory for their status, syncs filenames with IDs
+and titles, normalizes IDs, dates, labels, dependencies and empty owners, and adds
+missing template sections. Each fix is listed; with --dry-run nothing is written
+and a diff previews the changes.
+
+--changed-since <ref> validates only the features and specs added, modified or
+renamed since the git ref, including uncommitted and untracked files. Changed
+features are validated with their direct dependencies and dependents, since
+dependency rules span files; the dependents of deleted features are validated
+too. This narrows what is checked and reported, not what is read: every feature
+file is still parsed to find dependents and duplicate IDs, so large workspaces
+do not validate much faster.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts, err := options()
@@ -43,6 +78,16 @@ Examples:
 			if onlyFeatures && onlySpecs {
 				return WrapCLIError(ExitCodeValidation, fmt.Errorf("--only-features and --only-specs are mutually exclusive"))
 			}
+			if err := checkRuleIDs(opts, only, disabled); err != nil {
+				return WrapCLIError(ExitCodeValidation, err)
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "text" && !report.Supported(format) {
+				return WrapCLIError(ExitCodeValidation, fmt.Errorf("unsupported format %q (allowed: text, %s)", format, strings.Join(report.Formats, ", ")))
+			}
+			projectRoot := filepath.Dir(opts.RootDir)
+			var docs []report.Document
+			var ruleset []rules.Rule
 
 			target := "all"
 			if len(args) == 1 {
@@ -58,6 +103,23 @@ Examples:
 			isFeatureID := isSpecificTarget && strings.HasPrefix(strings.ToUpper(target), "FTR-")
 			isSpecName := isSpecificTarget && (strings.HasSuffix(target, ".md") || (!isFeatureID && target != "all"))
 
+			var changed, deleted []string
+			if changedSince != "" {
+				if isSpecificTarget {
+					return WrapCLIError(ExitCodeValidation, fmt.Errorf("--changed-since cannot be combined with a target"))
+				}
+				changed, err = changedFiles(opts.RootDir, changedSince)
+				if err == nil {
+					deleted, err = deletedIDs(opts.RootDir, changedSince)
+				}
+				if err != nil {
+					if errors.Is(err, vcs.ErrNotRepository) || errors.Is(err, vcs.ErrUnknownRef) {
+						return WrapCLIError(ExitCodeValidation, err)
+					}
+					return WrapCLIError(ExitCodeFilesystem, err)
+				}
+			}
+
 			if isSpecificTarget {
 				if isFeatureID {
 					validateSpecs = false
@@ -69,6 +131,8 @@ Examples:
 			}
 
 			var featureSummary *validator.Summary
+			var fixReport *validator.FixReport
+			var patches []patch.Patch
 			var specSummary *spec.Summary
 			var totalErrors int
 
@@ -77,11 +141,22 @@ Examples:
 				mgr := feature.NewManager(opts)
 				v, err := validator.New(opts, mgr)
 				if err != nil {
-					return WrapCLIError(ExitCodeFilesystem, err)
+					return WrapCLIError(ExitCodeValidation, err)
 				}
+				v.Rules().Select(only, disabled)
+				ruleset = append(ruleset, v.Rules().Rules()...)
 
-				if fix {
-					ids := []string{}
+				var changedIDs []string
+				if changedSince != "" {
+					changedIDs, err = v.IDsAt(changed)
+					if err != nil {
+						return WrapCLIError(ExitCodeFilesystem, err)
+					}
+				}
+
+				// With --changed-since, only the changed features are fixed.
+				if fix && (changedSince == "" || len(changedIDs) > 0) {
+					ids := changedIDs
 					if isFeatureID {
 						ids = []string{target}
 					}
@@ -96,9 +171,15 @@ Examples:
 						}
 						return WrapCLIError(ExitCodeFilesystem, err)
 					}
-					if err := v.ApplyFixes(feats, processor.Apply); err != nil {
+					fixReport, err = v.ApplyFixes(feats, processor.Apply)
+					if err != nil {
 						return WrapCLIError(ExitCodeFilesystem, err)
 					}
+					patches = mgr.Patches()
+					if format == "text" && !opts.JSONOutput {
+						printFixes(cmd.OutOrStdout(), fixReport, opts.DryRun)
+						printPatches(cmd, opts, patches)
+					}
 				}
 
 				if isFeatureID {
@@ -109,23 +190,29 @@ Examples:
 						}
 						return WrapCLIError(ExitCodeFilesystem, err)
 					}
+					if format != "text" {
+						docs = append(docs, featureDocument(projectRoot, result))
+						return writeReport(cmd, format, docs, ruleset)
+					}
 
 					if opts.JSONOutput {
 						payload := map[string]interface{}{
 							"id":          target,
 							"errors":      result.Errors,
+							"findings":    result.Findings,
 							"status":      result.Feature.FrontMatter.Status,
 							"fix_applied": fix,
 						}
+						addFixReport(opts, payload, fixReport, patches)
 						success := len(result.Errors) == 0
 						return respond(cmd, opts, success, "validation complete", payload)
 					}
 
-					if len(result.Errors) > 0 {
+					if len(result.Findings) > 0 {
 						fmt.Fprintf(cmd.OutOrStdout(), "%s:\n", target)
-						for _, msg := range result.Errors {
-							fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", msg)
-						}
+						printFindings(cmd.OutOrStdout(), "  ", result.Findings)
+					}
+					if len(result.Errors) > 0 {
 						return WrapCLIError(ExitCodeValidation, fmt.Errorf("validation failed for %s", target))
 					}
 
@@ -134,14 +221,28 @@ Examples:
 						"id":          target,
 						"fix_applied": fix,
 					}
+					addFixReport(opts, data, fixReport, patches)
 					return respond(cmd, opts, true, message, data)
 				}
 
-				featureSummary, err = v.ValidateAll()
+				if changedSince != "" {
+					// Deleted features are gone, but their dependents must be rechecked.
+					featureSummary, err = v.ValidateNeighbourhood(append(append([]string{}, changedIDs...), deleted...))
+				} else {
+					featureSummary, err = v.ValidateAll()
+				}
 				if err != nil {
 					return WrapCLIError(ExitCodeFilesystem, err)
 				}
 				totalErrors += featureSummary.Invalid
+				ids := make([]string, 0, len(featureSummary.Results))
+				for id := range featureSummary.Results {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+				for _, id := range ids {
+					docs = append(docs, featureDocument(projectRoot, featureSummary.Results[id]))
+				}
 			}
 
 			// Validate specs
@@ -149,8 +250,10 @@ Examples:
 				specMgr := spec.NewManager(opts)
 				specValidator, err := spec.NewValidator(opts, specMgr)
 				if err != nil {
-					return WrapCLIError(ExitCodeFilesystem, err)
+					return WrapCLIError(ExitCodeValidation, err)
 				}
+				specValidator.Rules().Select(only, disabled)
+				ruleset = append(ruleset, specValidator.Rules().Rules()...)
 
 				if isSpecName {
 					result, err := specValidator.ValidateName(target)
@@ -160,22 +263,27 @@ Examples:
 						}
 						return WrapCLIError(ExitCodeFilesystem, err)
 					}
+					if format != "text" {
+						docs = append(docs, specDocument(projectRoot, result))
+						return writeReport(cmd, format, docs, ruleset)
+					}
 
 					if opts.JSONOutput {
 						payload := map[string]interface{}{
-							"name":   target,
-							"errors": result.Errors,
-							"status": result.Spec.FrontMatter.Status,
+							"name":     target,
+							"errors":   result.Errors,
+							"findings": result.Findings,
+							"status":   result.Spec.FrontMatter.Status,
 						}
 						success := len(result.Errors) == 0
 						return respond(cmd, opts, success, "validation complete", payload)
 					}
 
-					if len(result.Errors) > 0 {
+					if len(result.Findings) > 0 {
 						fmt.Fprintf(cmd.OutOrStdout(), "%s:\n", target)
-						for _, msg := range result.Errors {
-							fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", msg)
-						}
+						printFindings(cmd.OutOrStdout(), "  ", result.Findings)
+					}
+					if len(result.Errors) > 0 {
 						return WrapCLIError(ExitCodeValidation, fmt.Errorf("validation failed for %s", target))
 					}
 
@@ -186,26 +294,44 @@ Examples:
 					return respond(cmd, opts, true, message, data)
 				}
 
-				specSummary, err = specValidator.ValidateAll()
+				if changedSince != "" {
+					specSummary, err = specValidator.ValidatePaths(changed)
+				} else {
+					specSummary, err = specValidator.ValidateAll()
+				}
 				if err != nil {
 					return WrapCLIError(ExitCodeFilesystem, err)
 				}
 				totalErrors += specSummary.Invalid
+				names := make([]string, 0, len(specSummary.Results))
+				for name := range specSummary.Results {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					docs = append(docs, specDocument(projectRoot, specSummary.Results[name]))
+				}
+			}
+
+			if format != "text" {
+				return writeReport(cmd, format, docs, ruleset)
 			}
 
 			// Handle combined summary output
 			if opts.JSONOutput {
 				payload := buildCombinedPayload(featureSummary, specSummary, fix, target)
+				if changedSince != "" {
+					payload["changed_since"] = changedSince
+				}
+				addFixReport(opts, payload, fixReport, patches)
 				return respond(cmd, opts, totalErrors == 0, "validation complete", payload)
 			}
 
-			// Print failures
-			if featureSummary != nil && featureSummary.HasErrors() {
-				fmt.Fprintf(cmd.OutOrStdout(), "Feature validation failures:\n")
+			// Print findings; only errors fail validation
+			if featureSummary != nil {
 				printFeatureSummaryFailures(cmd, featureSummary)
 			}
-			if specSummary != nil && specSummary.HasErrors() {
-				fmt.Fprintf(cmd.OutOrStdout(), "Spec validation failures:\n")
+			if specSummary != nil {
 				printSpecSummaryFailures(cmd, specSummary)
 			}
 
@@ -226,18 +352,25 @@ Examples:
 			data := map[string]interface{}{
 				"fix_applied": fix,
 			}
+			if changedSince != "" {
+				message += " changed since " + changedSince
+				data["changed_since"] = changedSince
+			}
+			addFixReport(opts, data, fixReport, patches)
 			if featureSummary != nil {
 				data["features"] = map[string]interface{}{
-					"total":   featureSummary.Total,
-					"valid":   featureSummary.Valid,
-					"invalid": featureSummary.Invalid,
+					"total":    featureSummary.Total,
+					"valid":    featureSummary.Valid,
+					"invalid":  featureSummary.Invalid,
+					"warnings": featureSummary.Warnings,
 				}
 			}
 			if specSummary != nil {
 				data["specs"] = map[string]interface{}{
-					"total":   specSummary.Total,
-					"valid":   specSummary.Valid,
-					"invalid": specSummary.Invalid,
+					"total":    specSummary.Total,
+					"valid":    specSummary.Valid,
+					"invalid":  specSummary.Invalid,
+					"warnings": specSummary.Warnings,
 				}
 			}
 
@@ -245,63 +378,189 @@ Examples:
 		},
 	}
 
-	cmd.Flags().BoolVar(&fix, "fix", false, "Apply safe fixes before validating (features only)")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Apply safe fixes before validating (features only); with --dry-run, preview them as a diff")
 	cmd.Flags().BoolVar(&onlyFeatures, "only-features", false, "Validate only feature specs")
 	cmd.Flags().BoolVar(&onlySpecs, "only-specs", false, "Validate only system specs")
+	cmd.Flags().StringSliceVar(&only, "rule", nil, "Run only the given rule IDs (repeatable)")
+	cmd.Flags().StringSliceVar(&disabled, "disable-rule", nil, "Skip the given rule IDs (repeatable)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, sarif, junit, github or checkstyle")
+	cmd.Flags().StringVar(&changedSince, "changed-since", "", "Validate only files changed since the git ref, plus their dependency neighbourhood")
 	return cmd
 }
 
+// checkRuleIDs rejects rule IDs in flags or config that no validator defines.
+func checkRuleIDs(opts *config.Options, only, disabled []string) error {
+	ids := append(append([]string{}, only...), disabled...)
+	for id := range opts.Settings.Validation.Rules {
+		ids = append(ids, id)
+	}
+	unknown := rules.Unknown(ids, rules.NewSet(validator.DefaultRules()), rules.NewSet(spec.DefaultRules()))
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown rule(s): %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// changedFiles lists the workspace files changed since ref, rooted at rootDir so
+// that they match the paths the managers load.
+func changedFiles(rootDir, ref string) ([]string, error) {
+	paths, err := vcs.ChangedFiles(rootDir, ref)
+	if err != nil {
+		return nil, err
+	}
+	changed := []string{}
+	for _, path := range paths {
+		if path, ok := workspacePath(rootDir, path); ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed, nil
+}
+
+// deletedIDs returns the IDs of the workspace features deleted since ref, read
+// from the files as they were at ref.
+func deletedIDs(rootDir, ref string) ([]string, error) {
+	files, err := vcs.DeletedFiles(rootDir, ref)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, file := range files {
+		path, ok := workspacePath(rootDir, file.Path)
+		if !ok || filepath.Ext(path) != ".md" {
+			continue
+		}
+		feat, err := feature.Parse(path, file.Content)
+		if err != nil || feat.FrontMatter.ID == "" {
+			continue
+		}
+		ids = append(ids, feat.FrontMatter.ID)
+	}
+	return ids, nil
+}
+
+// workspacePath re-roots a path git reported at rootDir, reporting false when it
+// lies outside the workspace.
+func workspacePath(rootDir, path string) (string, bool) {
+	root := rootDir
+	if resolved, err := filepath.EvalSymlinks(rootDir); err == nil {
+		root = resolved
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(rootDir, rel), true
+}
+
+// writeReport renders documents in a CI format and fails when any has errors.
+func writeReport(cmd *cobra.Command, format string, docs []report.Document, ruleset []rules.Rule) error {
+	if err := report.Write(cmd.OutOrStdout(), format, docs, ruleset); err != nil {
+		return WrapCLIError(ExitCodeFilesystem, err)
+	}
+	totalErrors := 0
+	for _, doc := range docs {
+		totalErrors += doc.Errors()
+	}
+	if totalErrors > 0 {
+		return WrapCLIError(ExitCodeValidation, fmt.Errorf("validation failed with %d error(s)", totalErrors))
+	}
+	return nil
+}
+
+func featureDocument(projectRoot string, res validator.Result) report.Document {
+	return report.Document{
+		Kind:     "feature",
+		Name:     res.Feature.FrontMatter.ID,
+		Path:     util.RelativePath(projectRoot, res.Feature.Path),
+		Findings: res.Findings,
+	}
+}
+
+func specDocument(projectRoot string, res spec.Result) report.Document {
+	return report.Document{
+		Kind:     "spec",
+		Name:     filepath.Base(res.Spec.Path),
+		Path:     util.RelativePath(projectRoot, res.Spec.Path),
+		Findings: res.Findings,
+	}
+}
+
+// printFixes lists the fixes --fix made, or with dry-run would make.
+func printFixes(out io.Writer, report *validator.FixReport, dryRun bool) {
+	if len(report.Fixes) == 0 {
+		return
+	}
+	verb := "Applied"
+	if dryRun {
+		verb = "Would apply"
+	}
+	fmt.Fprintf(out, "%s %d fix(es):\n", verb, len(report.Fixes))
+	for _, f := range report.Fixes {
+		fmt.Fprintf(out, "  - %s\n", f)
+	}
+}
+
+// addFixReport adds the fixes, and in dry-run the patches previewing them, to a
+// JSON payload.
+func addFixReport(opts *config.Options, data map[string]interface{}, report *validator.FixReport, patches []patch.Patch) {
+	if report == nil {
+		return
+	}
+	data["fixes"] = report.Fixes
+	addPatches(opts, data, patches)
+}
+
+// printFindings lists findings as "- severity [rule] message".
+func printFindings(out io.Writer, indent string, findings []rules.Finding) {
+	for _, f := range findings {
+		fmt.Fprintf(out, "%s- %s %s\n", indent, f.Severity, f)
+	}
+}
+
 func printFeatureSummaryFailures(cmd *cobra.Command, summary *validator.Summary) {
 	ids := make([]string, 0)
 	for id, res := range summary.Results {
-		if len(res.Errors) > 0 {
+		if len(res.Findings) > 0 {
 			ids = append(ids, id)
 		}
 	}
+	if len(ids) == 0 {
+		return
+	}
 	sort.Strings(ids)
 	out := cmd.OutOrStdout()
+	if summary.HasErrors() {
+		fmt.Fprintf(out, "Feature validation failures:\n")
+	} else {
+		fmt.Fprintf(out, "Feature validation findings:\n")
+	}
 	for _, id := range ids {
 		fmt.Fprintf(out, "  %s:\n", id)
-		for _, msg := range summary.Results[id].Errors {
-			fmt.Fprintf(out, "    - %s\n", msg)
-		}
+		printFindings(out, "    ", summary.Results[id].Findings)
 	}
 }
 
 func printSpecSummaryFailures(cmd *cobra.Command, summary *spec.Summary) {
 	names := make([]string, 0)
 	for name, res := range summary.Results {
-		if len(res.Errors) > 0 {
+		if len(res.Findings) > 0 {
 			names = append(names, name)
 		}
 	}
+	if len(names) == 0 {
+		return
+	}
 	sort.Strings(names)
 	out := cmd.OutOrStdout()
+	if summary.HasErrors() {
+		fmt.Fprintf(out, "Spec validation failures:\n")
+	} else {
+		fmt.Fprintf(out, "Spec validation findings:\n")
+	}
 	for _, name := range names {
 		fmt.Fprintf(out, "  %s:\n", name)
-		for _, msg := range summary.Results[name].Errors {
-			fmt.Fprintf(out, "    - %s\n", msg)
-		}
-	}
-}
-
-func buildSummaryPayload(summary *validator.Summary, fix bool, target string) map[string]interface{} {
-	results := make(map[string]interface{})
-	for id, res := range summary.Results {
-		results[id] = map[string]interface{}{
-			"errors": res.Errors,
-		}
-		if res.Feature != nil {
-			results[id].(map[string]interface{})["status"] = res.Feature.FrontMatter.Status
-		}
-	}
-	return map[string]interface{}{
-		"target":      target,
-		"total":       summary.Total,
-		"invalid":     summary.Invalid,
-		"valid":       summary.Valid,
-		"results":     results,
-		"fix_applied": fix,
+		printFindings(out, "    ", summary.Results[name].Findings)
 	}
 }
 
@@ -315,17 +574,19 @@ func buildCombinedPayload(featureSummary *validator.Summary, specSummary *spec.S
 		featureResults := make(map[string]interface{})
 		for id, res := range featureSummary.Results {
 			featureResults[id] = map[string]interface{}{
-				"errors": res.Errors,
+				"errors":   res.Errors,
+				"findings": res.Findings,
 			}
 			if res.Feature != nil {
 				featureResults[id].(map[string]interface{})["status"] = res.Feature.FrontMatter.Status
 			}
 		}
 		payload["features"] = map[string]interface{}{
-			"total":   featureSummary.Total,
-			"valid":   featureSummary.Valid,
-			"invalid": featureSummary.Invalid,
-			"results": featureResults,
+			"total":    featureSummary.Total,
+			"valid":    featureSummary.Valid,
+			"invalid":  featureSummary.Invalid,
+			"warnings": featureSummary.Warnings,
+			"results":  featureResults,
 		}
 	}
 
@@ -333,17 +594,19 @@ func buildCombinedPayload(featureSummary *validator.Summary, specSummary *spec.S
 		specResults := make(map[string]interface{})
 		for name, res := range specSummary.Results {
 			specResults[name] = map[string]interface{}{
-				"errors": res.Errors,
+				"errors":   res.Errors,
+				"findings": res.Findings,
 			}
 			if res.Spec != nil {
 				specResults[name].(map[string]interface{})["status"] = res.Spec.FrontMatter.Status
 			}
 		}
 		payload["specs"] = map[string]interface{}{
-			"total":   specSummary.Total,
-			"valid":   specSummary.Valid,
-			"invalid": specSummary.Invalid,
-			"results": specResults,
+			"total":    specSummary.Total,
+			"valid":    specSummary.Valid,
+			"invalid":  specSummary.Invalid,
+			"warnings": specSummary.Warnings,
+			"results":  specResults,
 		}
 	}
 
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/rules"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+	"github.com/virtualboard/vb-cli/internal/validator"
+)
+
+func TestValidateCommandRules(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", strings.Repeat("Long title ", 8))
+
+	out, err := runCommand(t, newValidateCommand(), "--only-features")
+	if err != nil {
+		t.Fatalf("expected warnings not to fail validation: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Feature validation findings:\n  FTR-0001:\n    - warning [title-length] title is 88 characters long; keep it to 72\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "FTR-0001"); err != nil || !strings.Contains(out, "FTR-0001:\n  - warning [title-length]") {
+		t.Fatalf("expected single-feature warning: %v\n%s", err, out)
+	}
+
+	misnamed := buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", "Misnamed")
+	if err := os.Rename(misnamed.Path, filepath.Join(filepath.Dir(misnamed.Path), "FTR-0002-other.md")); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	out, err = runCommand(t, newValidateCommand(), "--only-features")
+	if ExitCode(err) != ExitCodeValidation || !strings.Contains(out, "Feature validation failures:") || !strings.Contains(out, "- error [filename] filename 'FTR-0002-other.md'") {
+		t.Fatalf("expected filename error: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "--only-features", "--disable-rule", "filename"); err != nil {
+		t.Fatalf("expected disabled rule to pass: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "--only-features", "--rule", "title-length"); err != nil || strings.Contains(out, "[filename]") {
+		t.Fatalf("expected only title-length to run: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "FTR-0002"); ExitCode(err) != ExitCodeValidation || !strings.Contains(out, "- error [filename]") {
+		t.Fatalf("expected single-feature error: %v\n%s", err, out)
+	}
+
+	if _, err = runCommand(t, newValidateCommand(), "--rule", "typo", "--disable-rule", "spec-type"); ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "unknown rule(s): typo") {
+		t.Fatalf("expected unknown rule error, got %v", err)
+	}
+	opts.Settings.Validation.Rules = rules.Overrides{"bogus": {Severity: rules.Off}}
+	if _, err = runCommand(t, newValidateCommand()); ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "unknown rule(s): bogus") {
+		t.Fatalf("expected unknown configured rule error, got %v", err)
+	}
+	opts.Settings.Validation.Rules = rules.Overrides{"title-length": {Options: map[string]interface{}{"min": 1}}}
+	if _, err = runCommand(t, newValidateCommand(), "--only-features"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected invalid option error, got %v", err)
+	}
+	opts.Settings.Validation.Rules = rules.Overrides{"spec-type": {Options: map[string]interface{}{"strict": true}}}
+	if _, err = runCommand(t, newValidateCommand(), "--only-specs"); ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected invalid option error for specs, got %v", err)
+	}
+	opts.Settings.Validation.Rules = rules.Overrides{"filename": {Severity: rules.Info}}
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newValidateCommand(), "--only-features")
+	if err != nil {
+		t.Fatalf("expected info findings not to fail: %v\n%s", err, out)
+	}
+	var payload struct {
+		Data struct {
+			Features struct {
+				Warnings int `json:"warnings"`
+				Results  map[string]struct {
+					Findings []rules.Finding `json:"findings"`
+				} `json:"results"`
+			} `json:"features"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	findings := payload.Data.Features.Results["FTR-0002"].Findings
+	if payload.Data.Features.Warnings != 1 || len(findings) != 1 || findings[0].Severity != rules.Info || findings[0].Rule != "filename" {
+		t.Fatalf("unexpected payload %+v", payload.Data.Features)
+	}
+}
+
+func TestValidateCommandSpecFindings(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	opts.Settings.Validation.Rules = rules.Overrides{"spec-applicability": {Severity: rules.Warning}}
+	spec := "---\nspec_type: tech-stack\ntitle: Technology Stack\nstatus: approved\nlast_updated: 2024-01-15\napplicability: []\n---\n\n<!-- vb-disable spec-schema -->\n"
+	fix.WriteFile(t, "specs/tech-stack.md", []byte(spec))
+
+	out, err := runCommand(t, newValidateCommand(), "--only-specs")
+	if err != nil || !strings.Contains(out, "Spec validation findings:\n  tech-stack.md:\n    - warning [spec-applicability] applicability must have at least one entry\n") {
+		t.Fatalf("expected spec warning: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "tech-stack.md"); err != nil || !strings.Contains(out, "tech-stack.md:\n  - warning [spec-applicability]") {
+		t.Fatalf("expected single-spec warning: %v\n%s", err, out)
+	}
+
+	opts.Settings.Validation.Rules = nil
+	if out, err = runCommand(t, newValidateCommand(), "--only-specs"); ExitCode(err) != ExitCodeValidation || !strings.Contains(out, "Spec validation failures:") {
+		t.Fatalf("expected spec failure: %v\n%s", err, out)
+	}
+}
+
+func TestValidateCommandFormats(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+	misnamed := buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Misnamed")
+	if err := os.Rename(misnamed.Path, filepath.Join(filepath.Dir(misnamed.Path), "FTR-0001-other.md")); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", strings.Repeat("Long title ", 8))
+	fix.WriteFile(t, "specs/tech-stack.md", []byte("---\nspec_type: unknown\ntitle: Technology Stack\nstatus: approved\nlast_updated: 2024-01-15\napplicability: [backend]\n---\n"))
+
+	out, err := runCommand(t, newValidateCommand(), "--format", "github")
+	if ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "validation failed with 3 error(s)") {
+		t.Fatalf("expected validation failure, got %v", err)
+	}
+	for _, want := range []string{
+		"::error file=.virtualboard/features/backlog/FTR-0001-other.md,title=vb validate [filename]::filename 'FTR-0001-other.md' should be 'FTR-0001-misnamed.md'\n",
+		"::warning file=.virtualboard/features/backlog/FTR-0002-long-title-long-title-long-title-long-title-long-title-long-title-long-title-long-title.md,line=3,title=vb validate [title-length]::",
+		"::error file=.virtualboard/specs/tech-stack.md,line=2,title=vb validate [spec-schema]::",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newValidateCommand(), "--format", "SARIF", "--only-features")
+	if ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected validation failure, got %v", err)
+	}
+	var sarif struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(out), &sarif); err != nil {
+		t.Fatalf("expected bare SARIF even with --json: %v\n%s", err, out)
+	}
+	if len(sarif.Runs[0].Tool.Driver.Rules) != len(validator.DefaultRules()) || len(sarif.Runs[0].Results) != 2 {
+		t.Fatalf("unexpected SARIF %+v", sarif)
+	}
+	opts.JSONOutput = false
+
+	if out, err = runCommand(t, newValidateCommand(), "--format", "junit", "FTR-0002"); err != nil || !strings.Contains(out, `<testcase name="FTR-0002" classname="features"`) {
+		t.Fatalf("expected passing JUnit report: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "--format", "checkstyle", "tech-stack.md"); ExitCode(err) != ExitCodeValidation || !strings.Contains(out, `<file name=".virtualboard/specs/tech-stack.md">`) {
+		t.Fatalf("expected failing checkstyle report: %v\n%s", err, out)
+	}
+	if _, err = runCommand(t, newValidateCommand(), "--format", "html"); ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "unsupported format") {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+}
+
+// gitCommitAll commits the fixture's current state in a fresh or existing repo.
+func gitCommitAll(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "snapshot"}} {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)
+		git := exec.Command("git", args...)
+		git.Dir = dir
+		if out, err := git.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestValidateCommandChangedSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	if _, err := runCommand(t, newValidateCommand(), "--changed-since", "HEAD"); ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "not a git repository") {
+		t.Fatalf("expected not a repository error, got %v", err)
+	}
+
+	dependent := buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Dependent")
+	dependent.FrontMatter.Dependencies = []string{"FTR-0002"}
+	if err := mgr.Save(dependent); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	changed := buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", "Changed")
+	unrelated := buildFeatureFile(t, fix, mgr, "FTR-0003", "backlog", "Unrelated")
+	if err := os.Rename(unrelated.Path, filepath.Join(filepath.Dir(unrelated.Path), "FTR-0003-other.md")); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	gitCommitAll(t, fix.Root)
+
+	out, err := runCommand(t, newValidateCommand(), "--changed-since", "HEAD")
+	if err != nil || !strings.Contains(out, "Validated 0 features and 0 specs changed since HEAD") {
+		t.Fatalf("expected nothing to validate: %v\n%s", err, out)
+	}
+
+	changed.FrontMatter.Title = "Changed Title"
+	if err := mgr.Save(changed); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	fix.WriteFile(t, "specs/tech-stack.md", []byte("---\nspec_type: unknown\ntitle: Technology Stack\nstatus: approved\nlast_updated: 2024-01-15\napplicability: [backend]\n---\n"))
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newValidateCommand(), "--changed-since", "HEAD")
+	if err != nil {
+		t.Fatalf("expected a JSON report, got %v", err)
+	}
+	var payload struct {
+		Success bool `json:"success"`
+		Data    struct {
+			ChangedSince string `json:"changed_since"`
+			Features     struct {
+				Total   int                        `json:"total"`
+				Invalid int                        `json:"invalid"`
+				Results map[string]json.RawMessage `json:"results"`
+			} `json:"features"`
+			Specs struct {
+				Total   int `json:"total"`
+				Invalid int `json:"invalid"`
+			} `json:"specs"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if payload.Success || payload.Data.ChangedSince != "HEAD" || payload.Data.Features.Total != 2 || payload.Data.Features.Invalid != 1 ||
+		payload.Data.Features.Results["FTR-0003"] != nil || payload.Data.Specs.Total != 1 || payload.Data.Specs.Invalid != 1 {
+		t.Fatalf("expected the changed files and their neighbourhood: %+v", payload.Data)
+	}
+	opts.JSONOutput = false
+
+	out, err = runCommand(t, newValidateCommand(), "--changed-since", "HEAD", "--only-features", "--fix")
+	if err != nil || !strings.Contains(out, "Validated 2 features changed since HEAD") {
+		t.Fatalf("expected the fixed neighbourhood to pass: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(changed.Path), "FTR-0002-changed-title.md")); err != nil {
+		t.Fatalf("expected the changed feature to be renamed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(unrelated.Path), "FTR-0003-other.md")); err != nil {
+		t.Fatalf("expected the unchanged feature to be left alone: %v", err)
+	}
+
+	if _, err := runCommand(t, newValidateCommand(), "--changed-since", "HEAD", "FTR-0001"); ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "cannot be combined with a target") {
+		t.Fatalf("expected target conflict, got %v", err)
+	}
+	if _, err := runCommand(t, newValidateCommand(), "--changed-since", "no-such-ref"); ExitCode(err) != ExitCodeValidation || !strings.Contains(err.Error(), "unknown git ref") {
+		t.Fatalf("expected unknown ref error, got %v", err)
+	}
+}
+
+func TestValidateCommandChangedSinceDeleted(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	mgr := feature.NewManager(opts)
+
+	dependent := buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Dependent")
+	dependent.FrontMatter.Dependencies = []string{"FTR-0002"}
+	if err := mgr.Save(dependent); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	deleted := buildFeatureFile(t, fix, mgr, "FTR-0002", "backlog", "Deleted")
+	buildFeatureFile(t, fix, mgr, "FTR-0003", "backlog", "Unrelated")
+	gitCommitAll(t, fix.Root)
+	if err := os.Remove(deleted.Path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only a deletion changed, so the feature depending on it is validated.
+	out, err := runCommand(t, newValidateCommand(), "--changed-since", "HEAD", "--only-features")
+	if err != nil {
+		t.Fatalf("expected a JSON report, got %v", err)
+	}
+	var payload struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Features struct {
+				Total   int                        `json:"total"`
+				Invalid int                        `json:"invalid"`
+				Results map[string]json.RawMessage `json:"results"`
+			} `json:"features"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if payload.Success || payload.Data.Features.Total != 1 || payload.Data.Features.Invalid != 1 || payload.Data.Features.Results["FTR-0001"] == nil {
+		t.Fatalf("expected the dependent of the deleted feature, got %+v", payload.Data.Features)
+	}
+}
+
+func TestValidateCommandFixReport(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, true)
+	mgr := feature.NewManager(opts)
+	misplaced := buildFeatureFile(t, fix, mgr, "FTR-0001", "backlog", "Misplaced")
+	misplaced.FrontMatter.Status = "in-progress"
+	misplaced.FrontMatter.Labels = []string{"api", "api "}
+	data, err := misplaced.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if err := os.WriteFile(misplaced.Path, data, 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	out, err := runCommand(t, newValidateCommand(), "--fix", "--only-features")
+	if ExitCode(err) != ExitCodeValidation {
+		t.Fatalf("expected the unfixed file to fail in dry-run, got %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"Would apply 2 fix(es):\n",
+		`  - FTR-0001 [labels] cleaned labels ["api", "api "] to ["api"]` + "\n",
+		"  - FTR-0001 [directory] moved from features/backlog to features/in-progress for status in-progress\n",
+		"rename to .virtualboard/features/in-progress/FTR-0001-misplaced.md\n",
+		"-    - 'api '\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	opts.JSONOutput = true
+	out, err = runCommand(t, newValidateCommand(), "--fix", "FTR-0001")
+	if err != nil {
+		t.Fatalf("expected a JSON report, got %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Fixes   []validator.Fix `json:"fixes"`
+			Patches []patch.Patch   `json:"patches"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out)
+	}
+	if len(payload.Data.Fixes) != 2 || payload.Data.Fixes[1].Kind != "directory" ||
+		len(payload.Data.Patches) != 1 || payload.Data.Patches[0].Op != patch.Rename || !strings.Contains(payload.Data.Patches[0].Diff, "rename from") {
+		t.Fatalf("expected fixes and a patch in JSON: %+v", payload.Data)
+	}
+
+	opts.JSONOutput = false
+	opts.DryRun = false
+	out, err = runCommand(t, newValidateCommand(), "--fix")
+	if err != nil || !strings.Contains(out, "Applied 2 fix(es):\n") || strings.Contains(out, "rename from") {
+		t.Fatalf("expected applied fixes without a diff: %v\n%s", err, out)
+	}
+	if out, err = runCommand(t, newValidateCommand(), "--fix", "FTR-0001"); err != nil || strings.Contains(out, "fix(es)") {
+		t.Fatalf("expected nothing left to fix: %v\n%s", err, out)
+	}
+}
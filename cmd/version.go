@@ -10,8 +10,9 @@ import (
 
 func newVersionCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "version",
-		Short: "Print the CLI version",
+		Use:         "version",
+		Short:       "Print the CLI version",
+		Annotations: map[string]string{annotationAllowInvalidConfig: "true"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts, err := options()
 			if err != nil {
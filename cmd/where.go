@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+func newWhereCommand() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "where",
+		Short: "Show which workspace root vb resolved and how",
+		Long: `Show the workspace root vb operates on.
+
+vb walks up from --root (or the current directory) until it finds a .virtualboard
+directory, stopping at the repository root or the filesystem root. An invalid
+config.yaml or workflow.yaml is reported instead of failing, so it can be repaired.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationAllowInvalidConfig: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := options()
+			if err != nil {
+				return err
+			}
+
+			project := opts.RootDir
+			if filepath.Base(project) == config.WorkspaceDirName {
+				project = filepath.Dir(project)
+			}
+			workflowFile := ""
+			if _, err := os.Stat(filepath.Join(opts.RootDir, workflow.FileName)); err == nil {
+				workflowFile = filepath.Join(opts.RootDir, workflow.FileName)
+			}
+			configFiles := opts.Settings.Sources
+			if configFiles == nil {
+				configFiles = []string{}
+			}
+			configError := ""
+			if err := opts.ConfigError(); err != nil {
+				configError = err.Error()
+			}
+			discovered := opts.StartDir != "" && opts.StartDir != opts.RootDir && opts.StartDir != project
+
+			if quiet && !opts.JSONOutput {
+				fmt.Fprintln(cmd.OutOrStdout(), opts.RootDir)
+				return nil
+			}
+
+			if opts.JSONOutput {
+				return respond(cmd, opts, true, "", map[string]interface{}{
+					"root":         opts.RootDir,
+					"project_root": project,
+					"start_dir":    opts.StartDir,
+					"discovered":   discovered,
+					"config_files": configFiles,
+					"workflow":     workflowFile,
+					"config_error": configError,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Workspace: %s\n", opts.RootDir)
+			fmt.Fprintf(out, "Project:   %s\n", project)
+			if discovered {
+				fmt.Fprintf(out, "Found from %s\n", opts.StartDir)
+			}
+			if len(configFiles) == 0 {
+				fmt.Fprintln(out, "Config:    (defaults)")
+			} else {
+				fmt.Fprintf(out, "Config:    %s\n", strings.Join(configFiles, ", "))
+			}
+			if workflowFile == "" {
+				fmt.Fprintln(out, "Workflow:  (built-in)")
+			} else {
+				fmt.Fprintf(out, "Workflow:  %s\n", workflowFile)
+			}
+			if configError != "" {
+				fmt.Fprintf(out, "Error:     %s (using built-in defaults)\n", configError)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Print only the workspace path")
+	return cmd
+}
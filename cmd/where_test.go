@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestWhereCommand(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, false, false, false)
+
+	out, err := runCommand(t, newWhereCommand())
+	if err != nil || !strings.Contains(out, "Workspace: "+opts.RootDir) || !strings.Contains(out, "Config:    (defaults)") || !strings.Contains(out, "Workflow:  (built-in)") {
+		t.Fatalf("unexpected output: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "Found from") {
+		t.Fatalf("root given directly should not be reported as discovered: %s", out)
+	}
+
+	if out, err := runCommand(t, newWhereCommand(), "--quiet"); err != nil || strings.TrimSpace(out) != opts.RootDir {
+		t.Fatalf("unexpected quiet output: %v %q", err, out)
+	}
+
+	fix.WriteFile(t, "config.yaml", []byte("owner: alice\n"))
+	fix.WriteFile(t, "workflow.yaml", []byte("statuses:\n  - name: backlog\n"))
+	opts, _ = setupOptions(t, fix, false, false, false)
+	opts.StartDir = fix.Path("features", "backlog")
+	out, err = runCommand(t, newWhereCommand())
+	if err != nil || !strings.Contains(out, "Found from "+opts.StartDir) || !strings.Contains(out, "config.yaml") || !strings.Contains(out, "workflow.yaml") {
+		t.Fatalf("unexpected output: %v\n%s", err, out)
+	}
+}
+
+func TestWhereCommandJSON(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts, _ := setupOptions(t, fix, true, false, false)
+	opts.StartDir = fix.Path("specs")
+
+	var payload struct {
+		Data struct {
+			Root        string   `json:"root"`
+			ProjectRoot string   `json:"project_root"`
+			Discovered  bool     `json:"discovered"`
+			ConfigFiles []string `json:"config_files"`
+		} `json:"data"`
+	}
+	out, err := runCommand(t, newWhereCommand(), "--quiet")
+	if err != nil {
+		t.Fatalf("where failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.Data.Root != opts.RootDir || payload.Data.ProjectRoot != fix.Root || !payload.Data.Discovered || payload.Data.ConfigFiles == nil {
+		t.Fatalf("unexpected payload: %+v", payload.Data)
+	}
+}
@@ -2,6 +2,7 @@ package audit
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -18,14 +19,20 @@ type Entry struct {
 	Actor     string `json:"actor"`
 	FeatureID string `json:"feature_id,omitempty"`
 	Details   string `json:"details,omitempty"`
-	PrevHash  string `json:"prev_hash"`
-	EntryHash string `json:"entry_hash"`
+	// Changes is the structured change set; entries written before change sets
+	// were recorded have none.
+	Changes   *Changes `json:"changes,omitempty"`
+	PrevHash  string   `json:"prev_hash"`
+	EntryHash string   `json:"entry_hash"`
+	// Signature signs EntryHash when the logger has a signer.
+	Signature *Signature `json:"signature,omitempty"`
 }
 
 // Logger writes append-only JSONL audit entries with hash chain integrity.
 type Logger struct {
 	path     string
 	prevHash string
+	signer   *Signer
 	mu       sync.Mutex
 }
 
@@ -42,11 +49,51 @@ func NewLogger(auditPath string) (*Logger, error) {
 	}, nil
 }
 
+// SetSigner makes the logger sign every entry it writes; nil turns signing off.
+func (l *Logger) SetSigner(s *Signer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.signer = s
+}
+
 // Log writes a new audit entry. Thread-safe.
 func (l *Logger) Log(action, actor, featureID, details string) error {
+	return l.LogChanges(action, actor, featureID, details, nil)
+}
+
+// LogChanges writes a new audit entry with a structured change set. An empty
+// change set is omitted. Thread-safe.
+func (l *Logger) LogChanges(action, actor, featureID, details string, changes *Changes) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	dir := filepath.Dir(l.path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	// #nosec G304 -- audit path is constructed from controlled RootDir configuration
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	// Other loggers, in this process or another, may have appended since the
+	// last write, so the chain continues from the file's current last entry.
+	// The file lock keeps other processes from appending between reading that
+	// entry and writing the next one.
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	prev, err := lastHash(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit chain: %w", err)
+	}
+	l.prevHash = prev
+
 	entry := Entry{
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Action:    action,
@@ -55,7 +102,19 @@ func (l *Logger) Log(action, actor, featureID, details string) error {
 		Details:   details,
 		PrevHash:  l.prevHash,
 	}
+	if !changes.Empty() {
+		// Round-trip the values through JSON so the hash matches the entry as
+		// it is read back for verification.
+		normalized, err := changes.normalize()
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit changes: %w", err)
+		}
+		entry.Changes = normalized
+	}
 	entry.EntryHash = computeHash(entry)
+	if l.signer != nil {
+		l.signer.sign(&entry)
+	}
 
 	data, err := json.Marshal(entry)
 	if err != nil {
@@ -63,18 +122,6 @@ func (l *Logger) Log(action, actor, featureID, details string) error {
 	}
 	data = append(data, '\n')
 
-	dir := filepath.Dir(l.path)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
-		return fmt.Errorf("failed to create audit directory: %w", err)
-	}
-
-	// #nosec G304 -- audit path is constructed from controlled RootDir configuration
-	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
-	if err != nil {
-		return fmt.Errorf("failed to open audit log: %w", err)
-	}
-	defer f.Close()
-
 	if _, err := f.Write(data); err != nil {
 		return fmt.Errorf("failed to write audit entry: %w", err)
 	}
@@ -84,44 +131,140 @@ func (l *Logger) Log(action, actor, featureID, details string) error {
 }
 
 // computeHash computes SHA-256 of the entry content + prev hash for chain integrity.
+// The change set, when present, is appended as JSON, so entries without one hash
+// as they always have. The signature is not hashed; it signs the hash.
 func computeHash(e Entry) string {
 	input := e.Timestamp + e.Action + e.Actor + e.FeatureID + e.Details + e.PrevHash
+	if e.Changes != nil {
+		if data, err := json.Marshal(e.Changes); err == nil {
+			input += string(data)
+		}
+	}
 	h := sha256.Sum256([]byte(input))
 	return fmt.Sprintf("%x", h)
 }
 
-// lastHash reads the last line of the audit file and extracts its entry_hash.
-// Returns empty string for missing or empty files.
-func lastHash(path string) (string, error) {
+// maxLineSize bounds a single audit entry. Entries with large change sets exceed
+// bufio.Scanner's 64KB default, so every reader of the log uses this limit.
+const maxLineSize = 16 * 1024 * 1024
+
+// ReadEntries parses every entry in the audit file in chronological order.
+// A missing file yields no entries. Blank lines are skipped, and so are malformed
+// ones: their 1-based line numbers are returned so callers can warn, and Verify
+// reports them in detail.
+func ReadEntries(path string) ([]Entry, []int, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries := []Entry{}
+	var skipped []int
+	for _, l := range lines {
+		if l.entry == nil {
+			skipped = append(skipped, l.no)
+			continue
+		}
+		entries = append(entries, *l.entry)
+	}
+	return entries, skipped, nil
+}
+
+// line is one non-blank line of the audit file.
+type line struct {
+	no    int
+	entry *Entry
+	err   error
+}
+
+// readLines parses every non-blank line of the audit file, keeping malformed
+// lines with their parse error. A missing file yields no lines.
+func readLines(path string) ([]line, error) {
 	// #nosec G304 -- audit path is constructed from controlled RootDir configuration
 	f, err := os.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil
+			return nil, nil
 		}
-		return "", err
+		return nil, err
 	}
 	defer f.Close()
 
-	var lastLine string
+	var lines []line
 	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	no := 0
 	for scanner.Scan() {
-		line := scanner.Text()
-		if line != "" {
-			lastLine = line
+		no++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			lines = append(lines, line{no: no, err: err})
+			continue
 		}
+		lines = append(lines, line{no: no, entry: &entry})
 	}
 	if err := scanner.Err(); err != nil {
-		return "", err
+		return nil, err
 	}
+	return lines, nil
+}
 
-	if lastLine == "" {
-		return "", nil
+// lastHash reads the last line of the audit file and extracts its entry_hash.
+// Returns empty string for missing or empty files. A corrupt last line also
+// yields an empty string, so the next entry starts a fresh chain; Verify reports
+// the restart.
+func lastHash(path string) (string, error) {
+	line, err := lastLine(path)
+	if err != nil || line == nil {
+		return "", err
 	}
-
 	var entry Entry
-	if err := json.Unmarshal([]byte(lastLine), &entry); err != nil {
+	if err := json.Unmarshal(line, &entry); err != nil {
 		return "", nil // corrupt last line; start fresh chain
 	}
 	return entry.EntryHash, nil
 }
+
+// lastLine returns the last non-empty line of the file, reading backwards from
+// the end so appending stays cheap as the log grows. It returns nil for missing
+// or empty files.
+func lastLine(path string) ([]byte, error) {
+	// #nosec G304 -- audit path is constructed from controlled RootDir configuration
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	const chunk = 4096
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		size := int64(chunk)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+		buf := make([]byte, size)
+		if _, err := f.ReadAt(buf, offset); err != nil {
+			return nil, err
+		}
+		tail = append(buf, tail...)
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if offset == 0 && len(trimmed) > 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -286,3 +288,143 @@ func TestLogOptionalFields(t *testing.T) {
 		t.Fatalf("expected action 'lock', got %q", e.Action)
 	}
 }
+
+func TestReadEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+
+	entries, skipped, err := ReadEntries(path)
+	if err != nil || len(entries) != 0 || len(skipped) != 0 {
+		t.Fatalf("expected no entries for missing file, got %v %v (%v)", entries, skipped, err)
+	}
+
+	l, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log("create", "tester", "FTR-0001", "title=A"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log("move", "tester", "FTR-0001", "status=in-progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err = ReadEntries(path)
+	if err != nil {
+		t.Fatalf("ReadEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != "create" || entries[1].PrevHash != entries[0].EntryHash {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("\n{broken\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	// An entry larger than bufio.Scanner's default token size still reads back.
+	large := &Changes{Fields: []FieldChange{{Field: "description", Old: "", New: strings.Repeat("x", 100*1024)}}}
+	if err := l.LogChanges("update", "tester", "FTR-0001", "description", large); err != nil {
+		t.Fatal(err)
+	}
+	entries, skipped, err = ReadEntries(path)
+	if err != nil {
+		t.Fatalf("malformed and long lines must not fail the read: %v", err)
+	}
+	if len(entries) != 3 || entries[2].Action != "update" || len(skipped) != 1 || skipped[0] != 4 {
+		t.Fatalf("expected the malformed line 4 to be skipped, got %d entries, skipped %v", len(entries), skipped)
+	}
+
+	if _, _, err := ReadEntries(dir); err == nil {
+		t.Fatalf("expected error when reading a directory")
+	}
+}
+
+func TestLoggersShareChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	first, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []*Logger{first, second, first} {
+		if err := l.Log("move", "tester", "FTR-0001", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := Verify(path)
+	if err != nil || !result.OK() || result.Entries != 3 {
+		t.Fatalf("expected loggers on one file to extend a single chain, got %+v (%v)", result, err)
+	}
+}
+
+func TestLogWaitsForFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another process holding the lock is simulated by a separate descriptor.
+	held, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+	if err := lockFile(held); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- l.Log("move", "tester", "FTR-0001", "") }()
+	select {
+	case err := <-done:
+		t.Fatalf("expected Log to wait for the file lock, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := unlockFile(held); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("log failed after unlock: %v", err)
+	}
+	if result, err := Verify(path); err != nil || !result.OK() || result.Entries != 1 {
+		t.Fatalf("expected one entry, got %+v (%v)", result, err)
+	}
+}
+
+func TestLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	long := strings.Repeat("x", 10000)
+	if err := os.WriteFile(path, []byte("first\n"+long+"\r\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	line, err := lastLine(path)
+	if err != nil || string(line) != long {
+		t.Fatalf("expected the long last line, got %d bytes (%v)", len(line), err)
+	}
+
+	if err := os.WriteFile(path, []byte("only"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if line, err := lastLine(path); err != nil || string(line) != "only" {
+		t.Fatalf("expected a line without a newline, got %q (%v)", line, err)
+	}
+
+	if err := os.WriteFile(path, []byte("\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if line, err := lastLine(path); err != nil || line != nil {
+		t.Fatalf("expected no line for blank content, got %q (%v)", line, err)
+	}
+
+	if _, err := lastLine(t.TempDir()); err == nil {
+		t.Fatal("expected an error reading a directory")
+	}
+}
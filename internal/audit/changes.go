@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Section change kinds.
+const (
+	SectionAdded   = "added"
+	SectionRemoved = "removed"
+	SectionEdited  = "edited"
+)
+
+// Changes is a structured record of what an audited operation changed.
+type Changes struct {
+	Fields   []FieldChange   `json:"fields,omitempty"`
+	Sections []SectionChange `json:"sections,omitempty"`
+	Path     *PathChange     `json:"path,omitempty"`
+	// Snapshot is the SHA-256 of the feature file before the change, whose
+	// content is kept in the workspace trash so the change can be reverted.
+	Snapshot string `json:"snapshot,omitempty"`
+	// Result is the SHA-256 of the feature file after the change; empty when the
+	// change removed it. A revert refuses to overwrite a file that no longer
+	// matches it.
+	Result string `json:"result,omitempty"`
+	// Reverts is the entry_hash of the entry a revert undid.
+	Reverts string `json:"reverts,omitempty"`
+}
+
+// FieldChange is a frontmatter field's value before and after the change; nil
+// stands for an empty or missing value.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// SectionChange names a body section that was added, removed or edited.
+type SectionChange struct {
+	Section string `json:"section"`
+	Change  string `json:"change"`
+}
+
+// PathChange is a file move, relative to the project root. From is empty for a
+// created file and To for a deleted one.
+type PathChange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Empty reports whether the change set records nothing; a nil set is empty.
+func (c *Changes) Empty() bool {
+	return c == nil || (len(c.Fields) == 0 && len(c.Sections) == 0 && c.Path == nil && c.Snapshot == "" && c.Result == "" && c.Reverts == "")
+}
+
+// Field returns the change to the named field, if any.
+func (c *Changes) Field(name string) (FieldChange, bool) {
+	if c != nil {
+		for _, f := range c.Fields {
+			if f.Field == name {
+				return f, true
+			}
+		}
+	}
+	return FieldChange{}, false
+}
+
+// String summarizes the change set, e.g.
+// "priority: medium -> high; sections edited: Goal; path: a.md -> b.md".
+func (c *Changes) String() string {
+	if c.Empty() {
+		return ""
+	}
+	var parts []string
+	for _, f := range c.Fields {
+		parts = append(parts, fmt.Sprintf("%s: %s -> %s", f.Field, formatValue(f.Old), formatValue(f.New)))
+	}
+	for _, kind := range []string{SectionAdded, SectionRemoved, SectionEdited} {
+		var names []string
+		for _, s := range c.Sections {
+			if s.Change == kind {
+				names = append(names, s.Section)
+			}
+		}
+		if len(names) > 0 {
+			parts = append(parts, fmt.Sprintf("sections %s: %s", kind, strings.Join(names, ", ")))
+		}
+	}
+	if c.Path != nil {
+		parts = append(parts, fmt.Sprintf("path: %s -> %s", formatValue(c.Path.From), formatValue(c.Path.To)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// normalize returns a copy of the change set whose values have the types JSON
+// decoding produces.
+func (c *Changes) normalize() (*Changes, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var out Changes
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if value == "" {
+			return "(none)"
+		}
+		return value
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = fmt.Sprint(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []string:
+		return "[" + strings.Join(value, ", ") + "]"
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChangesString(t *testing.T) {
+	var none *Changes
+	if !none.Empty() || none.String() != "" || !(&Changes{}).Empty() {
+		t.Fatal("nil and zero change sets should be empty")
+	}
+	if snapshot := (&Changes{Snapshot: "abc"}); snapshot.Empty() || snapshot.String() != "" {
+		t.Fatal("a snapshot alone is recorded but not summarized")
+	}
+	if (&Changes{Reverts: "abc"}).Empty() {
+		t.Fatal("a revert reference alone should be recorded")
+	}
+	if _, ok := none.Field("priority"); ok {
+		t.Fatal("a nil change set has no fields")
+	}
+
+	c := &Changes{
+		Fields: []FieldChange{
+			{Field: "priority", Old: "medium", New: "high"},
+			{Field: "epic", Old: "", New: "billing"},
+			{Field: "labels", Old: []string{"api"}, New: []interface{}{"api", "ui"}},
+			{Field: "estimate", Old: nil, New: 3},
+		},
+		Sections: []SectionChange{
+			{Section: "Notes", Change: SectionAdded},
+			{Section: "Summary", Change: SectionEdited},
+			{Section: "Goal", Change: SectionEdited},
+		},
+		Path: &PathChange{From: "a.md", To: "b.md"},
+	}
+	want := "priority: medium -> high; epic: (none) -> billing; labels: [api] -> [api, ui]; estimate: (none) -> 3; " +
+		"sections added: Notes; sections edited: Summary, Goal; path: a.md -> b.md"
+	if got := c.String(); got != want {
+		t.Fatalf("unexpected summary:\n%s\nwant:\n%s", got, want)
+	}
+	if f, ok := c.Field("epic"); !ok || f.New != "billing" {
+		t.Fatalf("expected the epic change, got %+v", f)
+	}
+	if got := (&Changes{Path: &PathChange{To: "new.md"}}).String(); got != "path: (none) -> new.md" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+}
+
+func TestLogChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log("lock", "tester", "FTR-0001", "legacy"); err != nil {
+		t.Fatal(err)
+	}
+	changes := &Changes{Fields: []FieldChange{
+		{Field: "estimate", Old: 2, New: 3.5},
+		{Field: "labels", Old: []string{"a"}, New: nil},
+		{Field: "custom", Old: map[string]int{"b": 1, "a": 2}, New: true},
+	}}
+	if err := l.LogChanges("update", "tester", "FTR-0001", changes.String(), changes); err != nil {
+		t.Fatalf("LogChanges failed: %v", err)
+	}
+	if err := l.LogChanges("update", "tester", "FTR-0001", "nothing", &Changes{}); err != nil {
+		t.Fatalf("LogChanges failed: %v", err)
+	}
+
+	entries, _, err := ReadEntries(path)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v (%v)", entries, err)
+	}
+	if entries[0].Changes != nil || entries[2].Changes != nil {
+		t.Fatal("entries without changes should omit them")
+	}
+	if f, ok := entries[1].Changes.Field("estimate"); !ok || f.Old != float64(2) || f.New != 3.5 {
+		t.Fatalf("unexpected change %+v", f)
+	}
+	result, err := Verify(path)
+	if err != nil || !result.OK() {
+		t.Fatalf("expected entries with changes to verify, got %+v (%v)", result, err)
+	}
+
+	// Entries without changes hash exactly as before change sets existed.
+	legacy := entries[0]
+	input := legacy.Timestamp + legacy.Action + legacy.Actor + legacy.FeatureID + legacy.Details + legacy.PrevHash
+	if legacy.EntryHash != fmt.Sprintf("%x", sha256.Sum256([]byte(input))) {
+		t.Fatal("legacy entry hash changed")
+	}
+
+	// Editing a recorded change breaks the entry's hash.
+	edited := entries[1]
+	edited.Changes.Fields[0].New = 4.0
+	if computeHash(edited) == edited.EntryHash {
+		t.Fatal("changes should be covered by the entry hash")
+	}
+
+	bad := &Changes{Fields: []FieldChange{{Field: "x", New: math.NaN()}}}
+	if err := l.LogChanges("update", "tester", "", "", bad); err == nil || !strings.Contains(err.Error(), "audit changes") {
+		t.Fatalf("expected a marshal error, got %v", err)
+	}
+}
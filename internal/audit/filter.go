@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter selects audit entries. Values within a field are OR'ed, fields are
+// AND'ed, and empty fields match everything.
+type Filter struct {
+	FeatureIDs []string
+	Actors     []string
+	Actions    []string
+	// Fields keeps entries whose change set touches one of the frontmatter fields.
+	Fields []string
+	// Since and Until bound the entry timestamps, inclusively; zero is unbounded.
+	Since time.Time
+	Until time.Time
+}
+
+// ParseTime parses a time range bound given as an RFC 3339 timestamp or a
+// YYYY-MM-DD date. A date is the start of that day in UTC, or its last second
+// when endOfDay is set, so "--until 2026-01-31" includes the whole day.
+func ParseTime(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD or an RFC 3339 timestamp", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+// Match reports whether the entry passes the filter. Feature IDs, actors and
+// actions compare case-insensitively. Entries with an unparsable timestamp never
+// match a time range.
+func (f Filter) Match(e Entry) bool {
+	if !matchAny(f.FeatureIDs, e.FeatureID) || !matchAny(f.Actors, e.Actor) || !matchAny(f.Actions, e.Action) {
+		return false
+	}
+	if len(f.Fields) > 0 && !f.changesField(e.Changes) {
+		return false
+	}
+	if f.Since.IsZero() && f.Until.IsZero() {
+		return true
+	}
+	ts, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return false
+	}
+	if !f.Since.IsZero() && ts.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && ts.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries that match the filter, in their original order.
+func (f Filter) Apply(entries []Entry) []Entry {
+	matched := []Entry{}
+	for _, e := range entries {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+func (f Filter) changesField(changes *Changes) bool {
+	if changes == nil {
+		return false
+	}
+	for _, change := range changes.Fields {
+		if matchAny(f.Fields, change.Field) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(values []string, target string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	start, err := ParseTime("2026-01-31", false)
+	if err != nil || !start.Equal(time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected start of day %v (%v)", start, err)
+	}
+	end, err := ParseTime(" 2026-01-31 ", true)
+	if err != nil || !end.Equal(time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)) {
+		t.Fatalf("unexpected end of day %v (%v)", end, err)
+	}
+	ts, err := ParseTime("2026-01-31T10:00:00+02:00", true)
+	if err != nil || !ts.Equal(time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected timestamp %v (%v)", ts, err)
+	}
+	if _, err := ParseTime("31/01/2026", false); err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := []Entry{
+		{Timestamp: "2026-01-01T09:00:00Z", Action: "create", Actor: "alice", FeatureID: "FTR-0001"},
+		{Timestamp: "2026-01-02T09:00:00Z", Action: "move", Actor: "bob", FeatureID: "FTR-0001"},
+		{Timestamp: "2026-01-03T09:00:00Z", Action: "lock", Actor: "alice", FeatureID: "FTR-0002"},
+		{Timestamp: "not a time", Action: "move", Actor: "alice", FeatureID: "FTR-0002",
+			Changes: &Changes{Fields: []FieldChange{{Field: "status", Old: "backlog", New: "review"}}}},
+	}
+	day := func(s string, end bool) time.Time {
+		parsed, err := ParseTime(s, end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"empty", Filter{}, 4},
+		{"feature", Filter{FeatureIDs: []string{"ftr-0001"}}, 2},
+		{"actor and action", Filter{Actors: []string{"alice"}, Actions: []string{"move", " create "}}, 2},
+		{"since", Filter{Since: day("2026-01-02", false)}, 2},
+		{"until", Filter{Until: day("2026-01-02", true)}, 2},
+		{"range", Filter{Since: day("2026-01-02", false), Until: day("2026-01-02", true)}, 1},
+		{"field", Filter{Fields: []string{"Status"}}, 1},
+		{"other field", Filter{Fields: []string{"priority"}}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Apply(entries); len(got) != tt.want {
+			t.Fatalf("%s: expected %d entries, got %+v", tt.name, tt.want, got)
+		}
+	}
+	if got := (Filter{Actors: []string{"carol"}}).Apply(entries); got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty, non-nil result, got %#v", got)
+	}
+}
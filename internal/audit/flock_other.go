@@ -0,0 +1,12 @@
+//go:build !unix && !windows
+
+package audit
+
+import "os"
+
+// lockFile is a no-op on platforms without file locking; the in-process mutex
+// still serialises writers within one process.
+func lockFile(*os.File) error { return nil }
+
+// unlockFile is a no-op on platforms without file locking.
+func unlockFile(*os.File) error { return nil }
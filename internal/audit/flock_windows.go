@@ -0,0 +1,25 @@
+//go:build windows
+
+package audit
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Windows byte-range locks are mandatory, so the lock covers a byte far past the
+// end of the log rather than its content, which must stay readable.
+const lockOffset = 0xFFFFFFFF
+
+// lockFile takes an exclusive lock on f, blocking until it is free.
+func lockFile(f *os.File) error {
+	ol := windows.Overlapped{OffsetHigh: lockOffset}
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &ol)
+}
+
+// unlockFile releases the lock taken by lockFile.
+func unlockFile(f *os.File) error {
+	ol := windows.Overlapped{OffsetHigh: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol)
+}
@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Signing methods.
+const (
+	// MethodHMAC signs with HMAC-SHA256 over a shared secret.
+	MethodHMAC = "hmac-sha256"
+	// MethodEd25519 signs with an Ed25519 private key.
+	MethodEd25519 = "ed25519"
+)
+
+// PublicKeyExt is the extension of the per-signer public key files in the audit
+// keys directory.
+const PublicKeyExt = ".pub"
+
+var (
+	// ErrUnknownSigner indicates no key is available to check a signature.
+	ErrUnknownSigner = errors.New("no key for signer")
+	// ErrBadSignature indicates a signature does not match its entry.
+	ErrBadSignature = errors.New("signature does not match")
+)
+
+// Signature is an entry's signature over its entry_hash, which covers the entry's
+// contents and the hash of the entry before it. It is not part of the hash.
+type Signature struct {
+	Method string `json:"method"`
+	// Signer names the key that signed the entry.
+	Signer string `json:"signer"`
+	// Value is the base64-encoded signature.
+	Value string `json:"value"`
+}
+
+// Signer signs audit entries with one key.
+type Signer struct {
+	method  string
+	name    string
+	secret  []byte
+	private ed25519.PrivateKey
+}
+
+// NewHMACSigner returns a signer that signs as name with an HMAC secret.
+func NewHMACSigner(name string, secret []byte) (*Signer, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("HMAC secret for %s is empty", name)
+	}
+	return &Signer{method: MethodHMAC, name: name, secret: secret}, nil
+}
+
+// NewEd25519Signer returns a signer that signs as name with an Ed25519 private key.
+func NewEd25519Signer(name string, key ed25519.PrivateKey) *Signer {
+	return &Signer{method: MethodEd25519, name: name, private: key}
+}
+
+// Name returns the signer name recorded with each signature.
+func (s *Signer) Name() string {
+	return s.name
+}
+
+// sign sets the entry's signature over its entry_hash.
+func (s *Signer) sign(e *Entry) {
+	var value []byte
+	switch s.method {
+	case MethodHMAC:
+		value = hmacSum(s.secret, e.EntryHash)
+	default:
+		value = ed25519.Sign(s.private, []byte(e.EntryHash))
+	}
+	e.Signature = &Signature{Method: s.method, Signer: s.name, Value: base64.StdEncoding.EncodeToString(value)}
+}
+
+func hmacSum(secret []byte, message string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// Keyring holds the keys used to check signatures: an Ed25519 public key per
+// signer and, optionally, the shared HMAC secret.
+type Keyring struct {
+	public map[string]ed25519.PublicKey
+	secret []byte
+}
+
+// NewKeyring returns an empty keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{public: map[string]ed25519.PublicKey{}}
+}
+
+// LoadKeyring reads the Ed25519 public key of every signer from the <signer>.pub
+// files in dir. A missing directory yields an empty keyring.
+func LoadKeyring(dir string) (*Keyring, error) {
+	k := NewKeyring()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return k, nil
+		}
+		return nil, fmt.Errorf("failed to read audit keys: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, PublicKeyExt) {
+			continue
+		}
+		// #nosec G304 -- key files are listed from the configured audit keys directory
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit key %s: %w", name, err)
+		}
+		key, err := ParsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit key %s: %w", name, err)
+		}
+		k.AddPublicKey(strings.TrimSuffix(name, PublicKeyExt), key)
+	}
+	return k, nil
+}
+
+// AddPublicKey trusts key for Ed25519 signatures by signer.
+func (k *Keyring) AddPublicKey(signer string, key ed25519.PublicKey) {
+	k.public[signer] = key
+}
+
+// SetSecret trusts secret for HMAC signatures. The secret is shared, so anyone
+// holding it can sign under any signer name; only Ed25519 proves who signed.
+func (k *Keyring) SetSecret(secret []byte) {
+	k.secret = secret
+}
+
+// Check verifies the entry's signature over its entry_hash. It returns
+// ErrUnknownSigner when the keyring has no key for the signer and method, and
+// ErrBadSignature when the signature does not match. An unsigned entry is an
+// error.
+func (k *Keyring) Check(e Entry) error {
+	sig := e.Signature
+	if sig == nil {
+		return errors.New("entry is not signed")
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	switch sig.Method {
+	case MethodEd25519:
+		key, ok := k.public[sig.Signer]
+		if !ok {
+			return fmt.Errorf("%w %q (%s)", ErrUnknownSigner, sig.Signer, sig.Method)
+		}
+		if !ed25519.Verify(key, []byte(e.EntryHash), value) {
+			return ErrBadSignature
+		}
+	case MethodHMAC:
+		if len(k.secret) == 0 {
+			return fmt.Errorf("%w %q (%s)", ErrUnknownSigner, sig.Signer, sig.Method)
+		}
+		if !hmac.Equal(hmacSum(k.secret, e.EntryHash), value) {
+			return ErrBadSignature
+		}
+	default:
+		return fmt.Errorf("unknown signature method %q", sig.Method)
+	}
+	return nil
+}
+
+// ParsePrivateKey parses an Ed25519 private key given as a PKCS #8 PEM block, as
+// written by `openssl genpkey -algorithm ed25519`, or as a base64-encoded 32-byte
+// seed or 64-byte key.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("not an Ed25519 private key")
+		}
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("expected a PEM block or base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("Ed25519 private key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// ParsePublicKey parses an Ed25519 public key given as a PKIX PEM block, as
+// written by `openssl pkey -pubout`, or as a base64-encoded 32-byte key.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("not an Ed25519 public key")
+		}
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("expected a PEM block or base64: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("Ed25519 public key must be %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
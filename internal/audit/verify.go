@@ -0,0 +1,190 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of problem Verify reports.
+const (
+	// ProblemMalformed marks a line that is not a valid JSON entry.
+	ProblemMalformed = "malformed"
+	// ProblemModified marks an entry whose hash does not match its contents.
+	ProblemModified = "modified"
+	// ProblemReordered marks an entry that links to an entry other than the one before it.
+	ProblemReordered = "reordered"
+	// ProblemMissing marks an entry that links to an entry no longer in the log.
+	ProblemMissing = "missing"
+	// ProblemRestarted marks an entry that starts a new chain in the middle of the log.
+	ProblemRestarted = "restarted"
+	// ProblemSignature marks an entry whose signature does not verify.
+	ProblemSignature = "signature"
+	// ProblemUnsigned marks an entry without a verified signature when signatures are required.
+	ProblemUnsigned = "unsigned"
+)
+
+// Signature states of an attributed entry.
+const (
+	SignatureVerified = "verified"
+	SignatureUnsigned = "unsigned"
+	// SignatureUnknown marks a signature the keyring has no key for.
+	SignatureUnknown = "unknown-signer"
+	SignatureInvalid = "invalid"
+)
+
+// Problem is a break in the audit log's hash chain.
+type Problem struct {
+	// Line is the 1-based line number of the offending entry.
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// String renders the problem as "line N: message".
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Attribution ties an entry to the signer whose signature was checked.
+type Attribution struct {
+	Line int `json:"line"`
+	// Actor is the user the entry names, which is not itself verified.
+	Actor  string `json:"actor"`
+	Signer string `json:"signer,omitempty"`
+	Method string `json:"method,omitempty"`
+	Status string `json:"status"`
+}
+
+// VerifyOptions configures signature checks.
+type VerifyOptions struct {
+	// Keys checks entry signatures; nil skips them.
+	Keys *Keyring
+	// RequireSigned reports every entry without a verified signature.
+	RequireSigned bool
+}
+
+// Verification is the result of checking an audit log's hash chain.
+type Verification struct {
+	// Entries counts the well-formed entries checked.
+	Entries  int       `json:"entries"`
+	Problems []Problem `json:"problems"`
+	// Signed counts the entries with a verified signature.
+	Signed int `json:"signed"`
+	// Attributions lists each entry's signer when signatures were checked.
+	Attributions []Attribution `json:"attributions,omitempty"`
+}
+
+// OK reports whether the chain is intact.
+func (v *Verification) OK() bool {
+	return len(v.Problems) == 0
+}
+
+// Verify recomputes the hash of every entry in the audit file and checks that
+// each entry links to the one before it. Problems are reported in file order, so
+// the first one is the first broken link. A missing file verifies as empty.
+// Removing entries from the end of the log leaves the chain intact and cannot be
+// detected.
+func Verify(path string) (*Verification, error) {
+	return VerifyWith(path, VerifyOptions{})
+}
+
+// VerifyWith checks the hash chain like Verify and, given keys, each entry's
+// signature. A rewritten log can have a valid chain, but its signatures fail
+// unless it was re-signed with a trusted key; with RequireSigned, stripped
+// signatures are reported too.
+func VerifyWith(path string, opts VerifyOptions) (*Verification, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lineOf := map[string]int{}
+	for _, l := range lines {
+		if l.entry != nil {
+			if _, ok := lineOf[l.entry.EntryHash]; !ok {
+				lineOf[l.entry.EntryHash] = l.no
+			}
+		}
+	}
+
+	result := &Verification{Problems: []Problem{}}
+	report := func(line int, kind, format string, args ...interface{}) {
+		result.Problems = append(result.Problems, Problem{Line: line, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+	var prev *line
+	malformed := 0
+	for i := range lines {
+		l := &lines[i]
+		if l.entry == nil {
+			report(l.no, ProblemMalformed, "not a valid audit entry: %v", l.err)
+			malformed = l.no
+			continue
+		}
+		result.Entries++
+		e := l.entry
+		if computeHash(*e) != e.EntryHash {
+			report(l.no, ProblemModified, "entry_hash does not match the entry; it was edited after it was written")
+		}
+		if opts.Keys != nil {
+			attribution := checkSignature(opts.Keys, l.no, *e)
+			switch attribution.Status {
+			case SignatureVerified:
+				result.Signed++
+			case SignatureInvalid:
+				report(l.no, ProblemSignature, "signature by %s does not verify; the entry was rewritten or signed with another key", attribution.Signer)
+			case SignatureUnknown:
+				if opts.RequireSigned {
+					report(l.no, ProblemUnsigned, "signed by %s, whose key is not trusted", attribution.Signer)
+				}
+			default:
+				if opts.RequireSigned {
+					report(l.no, ProblemUnsigned, "entry is not signed")
+				}
+			}
+			result.Attributions = append(result.Attributions, attribution)
+		}
+
+		expected := ""
+		if prev != nil {
+			expected = prev.entry.EntryHash
+		}
+		if e.PrevHash != expected {
+			linked, found := lineOf[e.PrevHash]
+			switch {
+			case e.PrevHash == "" && malformed > 0:
+				report(l.no, ProblemRestarted, "starts a new hash chain after the malformed line %d", malformed)
+			case e.PrevHash == "":
+				report(l.no, ProblemRestarted, "starts a new hash chain; the entries before it are no longer linked")
+			case found && prev == nil:
+				report(l.no, ProblemReordered, "follows line %d but is the first entry", linked)
+			case found:
+				report(l.no, ProblemReordered, "follows line %d instead of line %d", linked, prev.no)
+			case prev == nil:
+				report(l.no, ProblemMissing, "follows an entry that is not in the log; earlier entries were deleted")
+			default:
+				report(l.no, ProblemMissing, "follows an entry that is not in the log; entries after line %d were deleted or rewritten", prev.no)
+			}
+		}
+		prev = l
+		malformed = 0
+	}
+	return result, nil
+}
+
+func checkSignature(keys *Keyring, no int, e Entry) Attribution {
+	attribution := Attribution{Line: no, Actor: e.Actor, Status: SignatureUnsigned}
+	if e.Signature == nil {
+		return attribution
+	}
+	attribution.Signer = e.Signature.Signer
+	attribution.Method = e.Signature.Method
+	switch err := keys.Check(e); {
+	case err == nil:
+		attribution.Status = SignatureVerified
+	case errors.Is(err, ErrUnknownSigner):
+		attribution.Status = SignatureUnknown
+	default:
+		attribution.Status = SignatureInvalid
+	}
+	return attribution
+}
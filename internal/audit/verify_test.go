@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeChain logs n entries and returns the file's lines.
+func writeChain(t *testing.T, path string, n int) []string {
+	t.Helper()
+	l, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		if err := l.Log("update", "tester", "FTR-0001", string(rune('A'+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func writeLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyIntact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	result, err := Verify(path)
+	if err != nil || !result.OK() || result.Entries != 0 {
+		t.Fatalf("expected a missing log to verify, got %+v (%v)", result, err)
+	}
+
+	writeChain(t, path, 3)
+	result, err = Verify(path)
+	if err != nil || !result.OK() || result.Entries != 3 {
+		t.Fatalf("expected an intact chain, got %+v (%v)", result, err)
+	}
+
+	if _, err := Verify(t.TempDir()); err == nil {
+		t.Fatal("expected an error reading a directory")
+	}
+}
+
+func TestVerifyProblems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	lines := writeChain(t, path, 4)
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  []Problem
+	}{
+		{
+			name:  "edited",
+			lines: []string{lines[0], strings.Replace(lines[1], `"details":"B"`, `"details":"X"`, 1), lines[2], lines[3]},
+			want:  []Problem{{Line: 2, Kind: ProblemModified}},
+		},
+		{
+			name:  "deleted",
+			lines: []string{lines[0], lines[2], lines[3]},
+			want:  []Problem{{Line: 2, Kind: ProblemMissing, Message: "entries after line 1 were deleted"}},
+		},
+		{
+			name:  "deleted first",
+			lines: []string{lines[1], lines[2]},
+			want:  []Problem{{Line: 1, Kind: ProblemMissing, Message: "earlier entries were deleted"}},
+		},
+		{
+			name:  "reordered",
+			lines: []string{lines[0], lines[2], lines[1], lines[3]},
+			want: []Problem{
+				{Line: 2, Kind: ProblemReordered, Message: "follows line 3 instead of line 1"},
+				{Line: 3, Kind: ProblemReordered, Message: "follows line 1 instead of line 2"},
+				{Line: 4, Kind: ProblemReordered, Message: "follows line 2 instead of line 3"},
+			},
+		},
+		{
+			name:  "moved to the front",
+			lines: []string{lines[1], lines[0]},
+			want: []Problem{
+				{Line: 1, Kind: ProblemReordered, Message: "follows line 2 but is the first entry"},
+				{Line: 2, Kind: ProblemRestarted, Message: "entries before it are no longer linked"},
+			},
+		},
+		{
+			name:  "malformed",
+			lines: []string{lines[0], "", "{broken", lines[1]},
+			want:  []Problem{{Line: 3, Kind: ProblemMalformed}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeLines(t, path, tt.lines...)
+			result, err := Verify(path)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if len(result.Problems) != len(tt.want) {
+				t.Fatalf("expected %d problem(s), got %+v", len(tt.want), result.Problems)
+			}
+			for i, want := range tt.want {
+				got := result.Problems[i]
+				if got.Line != want.Line || got.Kind != want.Kind || !strings.Contains(got.Message, want.Message) {
+					t.Fatalf("expected %+v, got %+v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyReportsRestartAfterCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	lines := writeChain(t, path, 1)
+	writeLines(t, path, lines[0], `{"truncated`)
+
+	l, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log("move", "tester", "FTR-0001", "after corruption"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Entries != 2 || len(result.Problems) != 2 || result.Problems[0].Kind != ProblemMalformed ||
+		result.Problems[1].String() != "line 3: starts a new hash chain after the malformed line 2" {
+		t.Fatalf("expected the malformed line and the restart, got %+v", result)
+	}
+}
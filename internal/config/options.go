@@ -10,21 +10,38 @@ import (
 	"sync"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/virtualboard/vb-cli/internal/workflow"
 )
 
+// WorkspaceDirName is the directory holding a VirtualBoard workspace.
+const WorkspaceDirName = ".virtualboard"
+
 // ctxKeyOptions is used to store options within a cobra command context.
 type ctxKeyOptions struct{}
 
 // Options contains global flags shared by all commands.
 type Options struct {
-	RootDir    string
+	RootDir string
+	// StartDir is the directory workspace discovery started from (--root or the working directory).
+	StartDir   string
 	JSONOutput bool
 	Verbose    bool
 	DryRun     bool
 	LogFile    string
+	// Settings are the merged defaults from config files and VB_* environment variables.
+	Settings Settings
+	// AllowInvalidConfig is set before Init by commands that must work on a broken
+	// workspace, such as vb init, vb where and vb version. Init then falls back to the
+	// built-in settings and workflow when config.yaml or workflow.yaml fails to load,
+	// and ConfigError reports why.
+	AllowInvalidConfig bool
 
 	logger   *logrus.Logger
 	logClose func() error
+	workflow *workflow.Workflow
+	// configErr is the config.yaml or workflow.yaml load error tolerated by AllowInvalidConfig.
+	configErr error
 }
 
 var (
@@ -34,7 +51,7 @@ var (
 
 // New creates a new Options instance populated with defaults.
 func New() *Options {
-	return &Options{}
+	return &Options{Settings: DefaultSettings()}
 }
 
 // Init populates options and configures logging.
@@ -56,13 +73,13 @@ func (o *Options) Init(root string, jsonOut, verbose, dry bool, logFile string)
 		return fmt.Errorf("root path invalid: %w", err)
 	}
 
-	if filepath.Base(absRoot) != ".virtualboard" {
-		workspace := filepath.Join(absRoot, ".virtualboard")
-		if info, err := os.Stat(workspace); err == nil && info.IsDir() {
-			absRoot = workspace
-		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("failed to inspect workspace: %w", err)
-		}
+	start := absRoot
+	workspace, found, err := discoverWorkspace(start)
+	if err != nil {
+		return err
+	}
+	if found {
+		absRoot = workspace
 	}
 
 	featuresPath := filepath.Join(absRoot, "features")
@@ -75,8 +92,20 @@ func (o *Options) Init(root string, jsonOut, verbose, dry bool, logFile string)
 		}
 	}
 
+	wf, settings, err := loadWorkspaceConfig(absRoot)
+	if err != nil {
+		if !o.AllowInvalidConfig {
+			return err
+		}
+		wf, settings = nil, DefaultSettings()
+	}
+	o.configErr = err
+
 	o.RootDir = absRoot
-	o.JSONOutput = jsonOut
+	o.StartDir = start
+	o.workflow = wf
+	o.Settings = settings
+	o.JSONOutput = jsonOut || settings.JSON
 	o.Verbose = verbose
 	o.DryRun = dry
 	o.LogFile = logFile
@@ -103,11 +132,70 @@ func (o *Options) Init(root string, jsonOut, verbose, dry bool, logFile string)
 	}
 
 	o.logger = logger
+	logger.WithFields(logrus.Fields{"root": absRoot, "start": start}).Info("Resolved workspace root")
+	if len(settings.Sources) > 0 {
+		logger.WithField("files", settings.Sources).Info("Loaded configuration")
+	}
+	if o.configErr != nil {
+		logger.WithError(o.configErr).Warn("Using built-in settings and workflow")
+	}
 	SetCurrent(o)
 
 	return nil
 }
 
+// loadWorkspaceConfig loads the workspace workflow and the merged settings.
+func loadWorkspaceConfig(root string) (*workflow.Workflow, Settings, error) {
+	wf, err := workflow.Load(root)
+	if err != nil {
+		return nil, Settings{}, err
+	}
+	settings, err := LoadSettings(root)
+	if err != nil {
+		return nil, Settings{}, err
+	}
+	if err := settings.checkRules(wf); err != nil {
+		return nil, Settings{}, err
+	}
+	return wf, settings, nil
+}
+
+// discoverWorkspace walks up from start looking for a .virtualboard directory, git-style.
+// The search stops at the first workspace found, at the repository root (a directory
+// containing .git) or at the filesystem root. A start directory that already holds a
+// features/ directory and no .virtualboard directory is treated as a workspace
+// itself and is not searched past; an unrelated features/ directory, such as
+// Cucumber's, never hides a .virtualboard next to it.
+func discoverWorkspace(start string) (string, bool, error) {
+	dir := start
+	for {
+		if filepath.Base(dir) == WorkspaceDirName {
+			return dir, true, nil
+		}
+		candidate := filepath.Join(dir, WorkspaceDirName)
+		info, err := os.Stat(candidate)
+		if err == nil && info.IsDir() {
+			return candidate, true, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", false, fmt.Errorf("failed to inspect workspace: %w", err)
+		}
+		if dir == start {
+			if _, err := os.Stat(filepath.Join(start, "features")); err == nil {
+				return "", false, nil
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", false, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false, nil
+		}
+		dir = parent
+	}
+}
+
 // SetCurrent stores the provided options as the globally accessible configuration.
 func SetCurrent(o *Options) {
 	optionsMu.Lock()
@@ -149,6 +237,20 @@ func FromContext(ctx context.Context) (*Options, error) {
 	return Current()
 }
 
+// Workflow returns the workspace workflow, or the built-in default when none was loaded.
+func (o *Options) Workflow() *workflow.Workflow {
+	if o.workflow == nil {
+		return workflow.Default()
+	}
+	return o.workflow
+}
+
+// ConfigError returns why config.yaml or workflow.yaml could not be loaded when
+// AllowInvalidConfig let Init continue with the built-in defaults.
+func (o *Options) ConfigError() error {
+	return o.configErr
+}
+
 // Logger exposes the configured logger.
 func (o *Options) Logger() *logrus.Logger {
 	return o.logger
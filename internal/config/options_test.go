@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -121,3 +122,124 @@ func assertSamePath(t *testing.T, actual, expected string) {
 		t.Fatalf("expected root %s, got %s", expectedResolved, actualResolved)
 	}
 }
+
+func TestOptionsWorkflow(t *testing.T) {
+	if New().Workflow().Initial != "backlog" {
+		t.Fatalf("expected default workflow before init")
+	}
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "workflow.yaml"), []byte("statuses:\n  - name: todo\n    transitions: [qa]\n  - name: qa\n"), 0o600); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	opts := New()
+	if err := opts.Init(root, false, false, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Workflow().Initial != "todo" || opts.Workflow().DirectoryForStatus("qa") != "features/qa" {
+		t.Fatalf("expected workspace workflow, got %+v", opts.Workflow())
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "workflow.yaml"), []byte("statuses: []\n"), 0o600); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := New().Init(root, false, false, false, ""); err == nil {
+		t.Fatalf("expected error for invalid workflow")
+	}
+}
+
+func TestOptionsInitAllowInvalidConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "workflow.yaml"), []byte("statuses: []\n"), 0o600); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, SettingsFileName), []byte("lock: [\n"), 0o600); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	opts := New()
+	opts.AllowInvalidConfig = true
+	if err := opts.Init(root, false, false, false, ""); err != nil {
+		t.Fatalf("invalid config should be tolerated: %v", err)
+	}
+	if opts.ConfigError() == nil || opts.Workflow().Initial != "backlog" || opts.Settings.Lock.TTL != 30 {
+		t.Fatalf("expected defaults and a config error, got %v %+v", opts.ConfigError(), opts.Settings)
+	}
+
+	if err := os.Remove(filepath.Join(root, "workflow.yaml")); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	if err := opts.Init(root, false, false, false, ""); err != nil || opts.ConfigError() == nil || !strings.Contains(opts.ConfigError().Error(), "config.yaml") {
+		t.Fatalf("expected config.yaml error, got %v %v", err, opts.ConfigError())
+	}
+}
+
+func TestOptionsInitDiscoversWorkspaceInParents(t *testing.T) {
+	repo := t.TempDir()
+	workspace := filepath.Join(repo, WorkspaceDirName)
+	nested := filepath.Join(repo, "services", "api")
+	for _, dir := range []string{filepath.Join(workspace, "features", "backlog"), nested, filepath.Join(repo, ".git")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+	}
+
+	opts := New()
+	if err := opts.Init(nested, false, false, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSamePath(t, opts.RootDir, workspace)
+	assertSamePath(t, opts.StartDir, nested)
+
+	inside := New()
+	if err := inside.Init(filepath.Join(workspace, "features", "backlog"), false, false, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSamePath(t, inside.RootDir, workspace)
+}
+
+func TestDiscoverWorkspaceStopsAtRepoRoot(t *testing.T) {
+	outer := t.TempDir()
+	repo := filepath.Join(outer, "repo")
+	nested := filepath.Join(repo, "pkg")
+	for _, dir := range []string{filepath.Join(outer, WorkspaceDirName), filepath.Join(repo, ".git"), nested} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+	}
+
+	if _, found, err := discoverWorkspace(nested); err != nil || found {
+		t.Fatalf("search should stop at the repository root, found=%v err=%v", found, err)
+	}
+	if _, found, err := discoverWorkspace(string(filepath.Separator)); err != nil || found {
+		t.Fatalf("search should stop at the filesystem root, found=%v err=%v", found, err)
+	}
+
+	legacy := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(legacy, "features"), 0o755); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if _, found, _ := discoverWorkspace(legacy); found {
+		t.Fatalf("a directory with features/ should not be searched past")
+	}
+}
+
+func TestDiscoverWorkspacePrefersVirtualboardOverFeatures(t *testing.T) {
+	repo := t.TempDir()
+	workspace := filepath.Join(repo, WorkspaceDirName)
+	for _, dir := range []string{filepath.Join(repo, ".git"), filepath.Join(repo, "features"), filepath.Join(workspace, "features")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+	}
+
+	found, ok, err := discoverWorkspace(repo)
+	if err != nil || !ok || found != workspace {
+		t.Fatalf("expected %s, got %s (found=%v, err=%v)", workspace, found, ok, err)
+	}
+	opts := New()
+	if err := opts.Init(repo, false, false, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSamePath(t, opts.RootDir, workspace)
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/virtualboard/vb-cli/internal/fields"
+	"github.com/virtualboard/vb-cli/internal/rules"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+// SettingsFileName is the name of both the workspace and user configuration files.
+const SettingsFileName = "config.yaml"
+
+// UserConfigEnv overrides the location of the user-level configuration file.
+const UserConfigEnv = "VB_CONFIG"
+
+// Settings holds command defaults loaded from configuration files and the environment.
+//
+// Values are merged in increasing order of precedence: built-in defaults, the user
+// config file, the workspace .virtualboard/config.yaml, VB_* environment variables,
+// and finally explicit command-line flags.
+type Settings struct {
+	// JSON enables machine-readable output by default.
+	JSON bool `yaml:"json" json:"json"`
+	// Owner is assigned to newly created features instead of "unassigned".
+	Owner string `yaml:"owner" json:"owner"`
+	// Editor is the command used to open files for manual merging.
+	Editor   string           `yaml:"editor" json:"editor"`
+	Lock     LockSettings     `yaml:"lock" json:"lock"`
+	Index    IndexSettings    `yaml:"index" json:"index"`
+	Template TemplateSettings `yaml:"template" json:"template"`
+	// Checklist configures the task-list gate on finishing features.
+	Checklist ChecklistSettings `yaml:"checklist" json:"checklist"`
+	// Rules declares content requirements per status, keyed by status name.
+	Rules map[string]StatusRules `yaml:"rules" json:"rules,omitempty"`
+	// Fields declares custom frontmatter fields, e.g. `due: date`.
+	Fields fields.Set `yaml:"fields" json:"fields,omitempty"`
+	// Validation configures the rules run by vb validate.
+	Validation ValidationSettings `yaml:"validation" json:"validation"`
+	// Audit configures signing and verification of the audit log.
+	Audit AuditSettings `yaml:"audit" json:"audit"`
+
+	// Sources lists the configuration files that contributed values, lowest precedence first.
+	Sources []string `yaml:"-" json:"sources"`
+}
+
+// LockSettings configures vb lock.
+type LockSettings struct {
+	TTL int `yaml:"ttl" json:"ttl"`
+}
+
+// IndexSettings configures vb index.
+type IndexSettings struct {
+	Format string `yaml:"format" json:"format"`
+	Output string `yaml:"output" json:"output"`
+	// Columns lists custom fields shown as extra index columns.
+	Columns []string `yaml:"columns" json:"columns,omitempty"`
+}
+
+// ChecklistSettings configures how Markdown task lists in feature bodies gate the done status.
+type ChecklistSettings struct {
+	// BlockDone rejects moving a feature to done, and flags done features in
+	// vb validate, while items in Sections are unchecked.
+	BlockDone bool `yaml:"block_done" json:"block_done"`
+	// Sections lists the gated sections; empty means every section.
+	Sections []string `yaml:"sections" json:"sections"`
+}
+
+// StatusRules are requirements a feature must meet to enter, or stay in, a status.
+type StatusRules struct {
+	// RequiredSections lists body sections that must be present and non-empty.
+	RequiredSections []string `yaml:"required_sections" json:"required_sections,omitempty"`
+	// RequireOwner rejects features that are unowned or owned by "unassigned".
+	RequireOwner bool `yaml:"require_owner" json:"require_owner,omitempty"`
+}
+
+// ValidationSettings configures vb validate.
+type ValidationSettings struct {
+	// Rules overrides rule severities and options, keyed by rule ID.
+	Rules rules.Overrides `yaml:"rules" json:"rules,omitempty"`
+}
+
+// AuditSettings configures the audit log.
+type AuditSettings struct {
+	Signing SigningSettings `yaml:"signing" json:"signing"`
+	// RequireSigned makes vb audit verify reject entries without a verified signature.
+	RequireSigned bool `yaml:"require_signed" json:"require_signed"`
+	// TrustedKeys is a directory of <signer>.pub keys outside the workspace that
+	// replaces .virtualboard/audit-keys when verifying signatures.
+	TrustedKeys string `yaml:"trusted_keys" json:"trusted_keys"`
+}
+
+// SigningSettings configures how audit entries are signed. The key itself is
+// never stored here: it is read from KeyFile or the VB_AUDIT_KEY variable.
+type SigningSettings struct {
+	// Method is "hmac" or "ed25519"; empty disables signing.
+	Method string `yaml:"method" json:"method"`
+	// Signer names the key in signatures; empty means the OS user.
+	Signer string `yaml:"signer" json:"signer"`
+	// KeyFile holds the HMAC secret or Ed25519 private key; a relative path is
+	// resolved against the project root.
+	KeyFile string `yaml:"key_file" json:"key_file"`
+}
+
+// TemplateSettings configures vb init.
+type TemplateSettings struct {
+	// Source is the URL of the template archive; empty means the official template.
+	Source string `yaml:"source" json:"source"`
+}
+
+// DefaultSettings returns the built-in defaults.
+func DefaultSettings() Settings {
+	return Settings{
+		Lock:  LockSettings{TTL: 30},
+		Index: IndexSettings{Format: "md"},
+		Checklist: ChecklistSettings{
+			Sections: []string{"Acceptance Criteria"},
+		},
+	}
+}
+
+// UserSettingsPath returns the location of the user-level configuration file.
+func UserSettingsPath() (string, error) {
+	if path := os.Getenv(UserConfigEnv); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vb", SettingsFileName), nil
+}
+
+// LoadSettings merges defaults, the user config, the workspace config and the environment.
+func LoadSettings(workspaceRoot string) (Settings, error) {
+	settings := DefaultSettings()
+
+	paths := []string{}
+	if userPath, err := UserSettingsPath(); err == nil {
+		paths = append(paths, userPath)
+	}
+	paths = append(paths, filepath.Join(workspaceRoot, SettingsFileName))
+
+	for i, path := range paths {
+		loaded, err := mergeSettingsFile(&settings, path, i == len(paths)-1)
+		if err != nil {
+			return Settings{}, err
+		}
+		if loaded {
+			settings.Sources = append(settings.Sources, path)
+		}
+	}
+
+	if err := applySettingsEnv(&settings); err != nil {
+		return Settings{}, err
+	}
+	if err := settings.validate(); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+// userOnlySettings are keys that make vb run a command, download code or trust
+// signing keys. The workspace config is committed, so anyone landing a commit could
+// set them for every teammate; they are only honoured from the user config and the
+// environment.
+type userOnlySettings struct {
+	Editor   *string `yaml:"editor"`
+	Template struct {
+		Source *string `yaml:"source"`
+	} `yaml:"template"`
+	Audit struct {
+		TrustedKeys *string `yaml:"trusted_keys"`
+	} `yaml:"audit"`
+}
+
+// mergeSettingsFile decodes path over settings; keys absent from the file keep their
+// value. The workspace file may not set the user-only keys.
+func mergeSettingsFile(settings *Settings, path string, workspace bool) (bool, error) {
+	// #nosec G304 -- config paths come from the workspace root or the user's config directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if workspace {
+		var probe userOnlySettings
+		if err := yaml.Unmarshal(data, &probe); err == nil {
+			if probe.Editor != nil {
+				return false, fmt.Errorf("invalid config %s: editor can only be set in the user config or VB_EDITOR", path)
+			}
+			if probe.Template.Source != nil {
+				return false, fmt.Errorf("invalid config %s: template.source can only be set in the user config or VB_TEMPLATE_SOURCE", path)
+			}
+			if probe.Audit.TrustedKeys != nil {
+				return false, fmt.Errorf("invalid config %s: audit.trusted_keys can only be set in the user config or VB_AUDIT_TRUSTED_KEYS", path)
+			}
+		}
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(settings); err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// applySettingsEnv overrides settings from VB_* environment variables.
+func applySettingsEnv(settings *Settings) error {
+	if value, ok := os.LookupEnv("VB_JSON"); ok && value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid VB_JSON value %q", value)
+		}
+		settings.JSON = parsed
+	}
+	if value, ok := os.LookupEnv("VB_LOCK_TTL"); ok && value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid VB_LOCK_TTL value %q", value)
+		}
+		settings.Lock.TTL = parsed
+	}
+	if value, ok := os.LookupEnv("VB_AUDIT_REQUIRE_SIGNED"); ok && value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid VB_AUDIT_REQUIRE_SIGNED value %q", value)
+		}
+		settings.Audit.RequireSigned = parsed
+	}
+	overrides := map[string]*string{
+		"VB_OWNER":              &settings.Owner,
+		"VB_EDITOR":             &settings.Editor,
+		"VB_INDEX_FORMAT":       &settings.Index.Format,
+		"VB_INDEX_OUTPUT":       &settings.Index.Output,
+		"VB_TEMPLATE_SOURCE":    &settings.Template.Source,
+		"VB_AUDIT_SIGNING":      &settings.Audit.Signing.Method,
+		"VB_AUDIT_SIGNER":       &settings.Audit.Signing.Signer,
+		"VB_AUDIT_KEY_FILE":     &settings.Audit.Signing.KeyFile,
+		"VB_AUDIT_TRUSTED_KEYS": &settings.Audit.TrustedKeys,
+	}
+	for name, target := range overrides {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			*target = value
+		}
+	}
+	return nil
+}
+
+func (s *Settings) validate() error {
+	if s.Lock.TTL <= 0 {
+		return fmt.Errorf("invalid config: lock.ttl must be positive")
+	}
+	s.Index.Format = strings.ToLower(strings.TrimSpace(s.Index.Format))
+	if s.Index.Format == "" {
+		s.Index.Format = "md"
+	}
+	s.Owner = strings.TrimSpace(s.Owner)
+	s.Audit.Signing.Method = strings.ToLower(strings.TrimSpace(s.Audit.Signing.Method))
+	switch s.Audit.Signing.Method {
+	case "", SigningHMAC, SigningEd25519:
+	default:
+		return fmt.Errorf("invalid config: audit.signing.method must be %s or %s", SigningHMAC, SigningEd25519)
+	}
+	s.Audit.Signing.Signer = strings.TrimSpace(s.Audit.Signing.Signer)
+	if err := s.Fields.Normalize(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if err := s.Validation.Rules.Normalize(); err != nil {
+		return fmt.Errorf("invalid config: validation.rules: %w", err)
+	}
+	if len(s.Rules) > 0 {
+		rules := make(map[string]StatusRules, len(s.Rules))
+		for status, r := range s.Rules {
+			for _, section := range r.RequiredSections {
+				if strings.TrimSpace(section) == "" {
+					return fmt.Errorf("invalid config: rules.%s.required_sections must not contain empty names", status)
+				}
+			}
+			rules[strings.ToLower(strings.TrimSpace(status))] = r
+		}
+		s.Rules = rules
+	}
+	return nil
+}
+
+// checkRules rejects rules declared for statuses the workflow does not define.
+func (s *Settings) checkRules(wf *workflow.Workflow) error {
+	for status := range s.Rules {
+		if err := wf.ValidateStatus(status); err != nil {
+			return fmt.Errorf("invalid config: rules.%s: %w", status, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateSettings points the user config at a temp file and clears VB_* overrides.
+func isolateSettings(t *testing.T) string {
+	t.Helper()
+	userPath := filepath.Join(t.TempDir(), "user.yaml")
+	t.Setenv(UserConfigEnv, userPath)
+	for _, name := range []string{"VB_JSON", "VB_LOCK_TTL", "VB_OWNER", "VB_EDITOR", "VB_INDEX_FORMAT", "VB_INDEX_OUTPUT", "VB_TEMPLATE_SOURCE", "VB_AUDIT_SIGNING", "VB_AUDIT_SIGNER", "VB_AUDIT_KEY_FILE", "VB_AUDIT_REQUIRE_SIGNED"} {
+		t.Setenv(name, "")
+	}
+	return userPath
+}
+
+func writeSettings(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+}
+
+func TestLoadSettingsDefaults(t *testing.T) {
+	isolateSettings(t)
+	settings, err := LoadSettings(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.JSON || settings.Lock.TTL != 30 || settings.Index.Format != "md" || len(settings.Sources) != 0 {
+		t.Fatalf("unexpected defaults: %+v", settings)
+	}
+	if settings.Checklist.BlockDone || len(settings.Checklist.Sections) != 1 || settings.Checklist.Sections[0] != "Acceptance Criteria" {
+		t.Fatalf("unexpected checklist defaults: %+v", settings.Checklist)
+	}
+}
+
+func TestLoadSettingsPrecedence(t *testing.T) {
+	userPath := isolateSettings(t)
+	workspace := t.TempDir()
+
+	writeSettings(t, userPath, "owner: alice\neditor: nano\nlock:\n  ttl: 45\nindex:\n  format: json\n")
+	writeSettings(t, filepath.Join(workspace, SettingsFileName), "owner: bob\nindex:\n  output: features/INDEX.json\nchecklist:\n  block_done: true\n  sections: [Acceptance Criteria, Test Plan]\n")
+
+	settings, err := LoadSettings(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Owner != "bob" || settings.Editor != "nano" || settings.Lock.TTL != 45 {
+		t.Fatalf("workspace should override user config only where set: %+v", settings)
+	}
+	if settings.Index.Format != "json" || settings.Index.Output != "features/INDEX.json" {
+		t.Fatalf("unexpected index settings: %+v", settings.Index)
+	}
+	if !settings.Checklist.BlockDone || len(settings.Checklist.Sections) != 2 {
+		t.Fatalf("unexpected checklist settings: %+v", settings.Checklist)
+	}
+	if len(settings.Sources) != 2 || settings.Sources[0] != userPath {
+		t.Fatalf("unexpected sources: %v", settings.Sources)
+	}
+
+	t.Setenv("VB_JSON", "true")
+	t.Setenv("VB_LOCK_TTL", "5")
+	t.Setenv("VB_OWNER", "carol")
+	t.Setenv("VB_INDEX_FORMAT", "HTML")
+	t.Setenv("VB_TEMPLATE_SOURCE", "https://example.com/template.zip")
+	settings, err = LoadSettings(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !settings.JSON || settings.Lock.TTL != 5 || settings.Owner != "carol" || settings.Index.Format != "html" {
+		t.Fatalf("environment should override config files: %+v", settings)
+	}
+	if settings.Template.Source != "https://example.com/template.zip" {
+		t.Fatalf("unexpected template source: %s", settings.Template.Source)
+	}
+}
+
+func TestLoadSettingsAudit(t *testing.T) {
+	isolateSettings(t)
+	workspace := t.TempDir()
+	writeSettings(t, filepath.Join(workspace, SettingsFileName), "audit:\n  signing:\n    method: \" Ed25519 \"\n    signer: ci\n    key_file: /keys/audit.key\n")
+
+	settings, err := LoadSettings(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Audit.Signing != (SigningSettings{Method: SigningEd25519, Signer: "ci", KeyFile: "/keys/audit.key"}) || settings.Audit.RequireSigned {
+		t.Fatalf("unexpected audit settings: %+v", settings.Audit)
+	}
+
+	t.Setenv("VB_AUDIT_SIGNING", "hmac")
+	t.Setenv("VB_AUDIT_SIGNER", "alice")
+	t.Setenv("VB_AUDIT_KEY_FILE", "/tmp/secret")
+	t.Setenv("VB_AUDIT_REQUIRE_SIGNED", "true")
+	settings, err = LoadSettings(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Audit.Signing != (SigningSettings{Method: SigningHMAC, Signer: "alice", KeyFile: "/tmp/secret"}) || !settings.Audit.RequireSigned {
+		t.Fatalf("environment should override audit settings: %+v", settings.Audit)
+	}
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		workspace string
+		env       map[string]string
+		want      string
+	}{
+		{"unknown key", "colour: blue\n", nil, "invalid config"},
+		{"malformed yaml", "lock: [\n", nil, "invalid config"},
+		{"non-positive ttl", "lock:\n  ttl: 0\n", nil, "lock.ttl must be positive"},
+		{"bad VB_JSON", "", map[string]string{"VB_JSON": "maybe"}, "VB_JSON"},
+		{"bad VB_LOCK_TTL", "", map[string]string{"VB_LOCK_TTL": "soon"}, "VB_LOCK_TTL"},
+		{"empty required section", "rules:\n  review:\n    required_sections: [\"\"]\n", nil, "rules.review.required_sections"},
+		{"workspace editor", "editor: ./evil.sh\n", nil, "editor can only be set in the user config or VB_EDITOR"},
+		{"workspace template source", "template:\n  source: https://example.com/evil.zip\n", nil, "template.source can only be set in the user config or VB_TEMPLATE_SOURCE"},
+		{"workspace trusted keys", "audit:\n  trusted_keys: ./keys\n", nil, "audit.trusted_keys can only be set in the user config or VB_AUDIT_TRUSTED_KEYS"},
+		{"bad signing method", "audit:\n  signing:\n    method: rsa\n", nil, "audit.signing.method must be hmac or ed25519"},
+		{"bad VB_AUDIT_REQUIRE_SIGNED", "", map[string]string{"VB_AUDIT_REQUIRE_SIGNED": "always"}, "VB_AUDIT_REQUIRE_SIGNED"},
+		{"bad rule severity", "validation:\n  rules:\n    filename: loud\n", nil, "validation.rules: rule filename: unknown severity"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			isolateSettings(t)
+			workspace := t.TempDir()
+			writeSettings(t, filepath.Join(workspace, SettingsFileName), tc.workspace)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			_, err := LoadSettings(workspace)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+
+	userPath := isolateSettings(t)
+	if err := os.Mkdir(userPath, 0o750); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if _, err := LoadSettings(t.TempDir()); err == nil || !strings.Contains(err.Error(), "failed to read config") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestUserSettingsPath(t *testing.T) {
+	t.Setenv(UserConfigEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	t.Setenv("HOME", "/tmp/home")
+	path, err := UserSettingsPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(path, filepath.Join("vb", SettingsFileName)) {
+		t.Fatalf("unexpected user config path: %s", path)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "")
+	if _, err := UserSettingsPath(); err == nil {
+		t.Fatalf("expected error without a config directory")
+	}
+	if _, err := LoadSettings(t.TempDir()); err != nil {
+		t.Fatalf("missing user config directory should be ignored: %v", err)
+	}
+}
+
+func TestOptionsInitAppliesSettings(t *testing.T) {
+	isolateSettings(t)
+	root := t.TempDir()
+	writeSettings(t, filepath.Join(root, SettingsFileName), "json: true\nowner: alice\n")
+
+	opts := New()
+	if err := opts.Init(root, false, true, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.JSONOutput || opts.Settings.Owner != "alice" {
+		t.Fatalf("expected settings to be applied: %+v", opts)
+	}
+
+	writeSettings(t, filepath.Join(root, SettingsFileName), "json: maybe\n")
+	if err := New().Init(root, false, false, false, ""); err == nil {
+		t.Fatalf("expected error for invalid config")
+	}
+}
+
+func TestLoadSettingsFields(t *testing.T) {
+	userPath := isolateSettings(t)
+	workspace := t.TempDir()
+	writeSettings(t, userPath, "fields:\n  due: date\n")
+	writeSettings(t, filepath.Join(workspace, SettingsFileName), "fields:\n  team:\n    type: enum\n    values: [core]\nindex:\n  columns: [team]\n")
+
+	settings, err := LoadSettings(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Fields["due"].Type != "date" || settings.Fields["team"].Name != "team" || len(settings.Index.Columns) != 1 {
+		t.Fatalf("expected fields from both files: %+v", settings)
+	}
+
+	writeSettings(t, filepath.Join(workspace, SettingsFileName), "fields:\n  size: huge\n")
+	if _, err := LoadSettings(workspace); err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Fatalf("expected invalid field type error, got %v", err)
+	}
+}
+
+func TestSettingsRules(t *testing.T) {
+	isolateSettings(t)
+	root := t.TempDir()
+	writeSettings(t, filepath.Join(root, SettingsFileName), "rules:\n  Review:\n    required_sections: [Acceptance Criteria]\n  in-progress:\n    require_owner: true\n")
+
+	opts := New()
+	if err := opts.Init(root, false, false, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	review, ok := opts.Settings.Rules["review"]
+	if !ok || len(review.RequiredSections) != 1 || !opts.Settings.Rules["in-progress"].RequireOwner {
+		t.Fatalf("expected normalised rules, got %+v", opts.Settings.Rules)
+	}
+
+	writeSettings(t, filepath.Join(root, SettingsFileName), "rules:\n  qa:\n    require_owner: true\n")
+	if err := New().Init(root, false, false, false, ""); err == nil || !strings.Contains(err.Error(), "rules.qa: invalid status 'qa'") {
+		t.Fatalf("expected unknown status error, got %v", err)
+	}
+}
+
+func TestSettingsValidationRules(t *testing.T) {
+	isolateSettings(t)
+	workspace := t.TempDir()
+	writeSettings(t, filepath.Join(workspace, SettingsFileName), "validation:\n  rules:\n    Filename: Warning\n    title-length:\n      severity: error\n      max: 50\n")
+
+	settings, err := LoadSettings(workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := settings.Validation.Rules
+	if rules["filename"].Severity != "warning" || rules["title-length"].Severity != "error" || rules["title-length"].Options["max"] != 50 {
+		t.Fatalf("expected normalised rule overrides, got %+v", rules)
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+)
+
+// Audit signing methods accepted in audit.signing.method.
+const (
+	SigningHMAC    = "hmac"
+	SigningEd25519 = "ed25519"
+)
+
+// AuditKeyEnv holds the audit signing key itself, taking precedence over
+// audit.signing.key_file.
+const AuditKeyEnv = "VB_AUDIT_KEY"
+
+// AuditKeysDirName is the workspace directory holding one <signer>.pub Ed25519
+// public key per signer.
+const AuditKeysDirName = "audit-keys"
+
+// AuditKeysDir returns the directory of the signers' public keys: audit.trusted_keys
+// when set, otherwise the workspace's audit keys directory. Keys in the workspace
+// can be rewritten by whoever rewrites the log, so they are only as trustworthy as
+// the review protecting them; pinned keys outside the workspace are not.
+func (o *Options) AuditKeysDir() string {
+	if dir := o.Settings.Audit.TrustedKeys; dir != "" {
+		return dir
+	}
+	return filepath.Join(o.RootDir, AuditKeysDirName)
+}
+
+// AuditSigner returns the signer configured in audit.signing, or nil when signing
+// is off.
+func (o *Options) AuditSigner() (*audit.Signer, error) {
+	signing := o.Settings.Audit.Signing
+	if signing.Method == "" {
+		return nil, nil
+	}
+	key, err := o.auditKey()
+	if err != nil {
+		return nil, err
+	}
+	name := signing.Signer
+	if name == "" {
+		name = os.Getenv("USER")
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if signing.Method == SigningHMAC {
+		return audit.NewHMACSigner(name, key)
+	}
+	private, err := audit.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit signing key: %w", err)
+	}
+	return audit.NewEd25519Signer(name, private), nil
+}
+
+// AuditKeyring returns the keys that verify signatures: the signers' public keys
+// from AuditKeysDir and, when HMAC signing is configured, the local secret.
+func (o *Options) AuditKeyring() (*audit.Keyring, error) {
+	if dir := o.Settings.Audit.TrustedKeys; dir != "" {
+		// A mistyped pinned directory must not quietly trust nobody.
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("failed to read trusted audit keys: %w", err)
+		}
+	}
+	keys, err := audit.LoadKeyring(o.AuditKeysDir())
+	if err != nil {
+		return nil, err
+	}
+	if o.Settings.Audit.Signing.Method == SigningHMAC {
+		secret, err := o.auditKey()
+		if err != nil {
+			return nil, err
+		}
+		keys.SetSecret(secret)
+	}
+	return keys, nil
+}
+
+// auditKey reads the signing key from VB_AUDIT_KEY or audit.signing.key_file.
+// Surrounding whitespace is trimmed, so a trailing newline is not part of an
+// HMAC secret.
+func (o *Options) auditKey() ([]byte, error) {
+	if value := strings.TrimSpace(os.Getenv(AuditKeyEnv)); value != "" {
+		return []byte(value), nil
+	}
+	path := o.Settings.Audit.Signing.KeyFile
+	if path == "" {
+		return nil, fmt.Errorf("audit signing is enabled but no key is set: use %s or audit.signing.key_file", AuditKeyEnv)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(o.RootDir), path)
+	}
+	// #nosec G304 -- key file path comes from the user's configuration
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit signing key: %w", err)
+	}
+	key := []byte(strings.TrimSpace(string(data)))
+	if len(key) == 0 {
+		return nil, fmt.Errorf("audit signing key %s is empty", path)
+	}
+	return key, nil
+}
+
+// AuditLogger returns a logger for the workspace audit log that signs entries as
+// configured in audit.signing. When signing is configured but the signer cannot be
+// built, it returns an error rather than a logger writing unsigned entries, so
+// mutating commands fail instead of silently weakening the log.
+func (o *Options) AuditLogger() (*audit.Logger, error) {
+	logger, err := audit.NewLogger(filepath.Join(o.RootDir, "audit.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	signer, err := o.AuditSigner()
+	if err != nil {
+		return nil, fmt.Errorf("audit signing is configured but unavailable: %w", err)
+	}
+	logger.SetSigner(signer)
+	return logger, nil
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+)
+
+func signingOptions(t *testing.T, signing SigningSettings) *Options {
+	t.Helper()
+	t.Setenv(AuditKeyEnv, "")
+	t.Setenv("USER", "alice")
+	root := filepath.Join(t.TempDir(), WorkspaceDirName)
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	opts := New()
+	opts.RootDir = root
+	opts.Settings.Audit.Signing = signing
+	return opts
+}
+
+func TestAuditSigner(t *testing.T) {
+	opts := signingOptions(t, SigningSettings{})
+	if signer, err := opts.AuditSigner(); signer != nil || err != nil {
+		t.Fatalf("expected signing to be off, got %v (%v)", signer, err)
+	}
+
+	opts = signingOptions(t, SigningSettings{Method: SigningHMAC})
+	if _, err := opts.AuditSigner(); err == nil || !strings.Contains(err.Error(), AuditKeyEnv) {
+		t.Fatalf("expected a missing key error, got %v", err)
+	}
+	t.Setenv(AuditKeyEnv, "s3cret\n")
+	signer, err := opts.AuditSigner()
+	if err != nil || signer.Name() != "alice" {
+		t.Fatalf("expected an HMAC signer for $USER, got %v (%v)", signer, err)
+	}
+
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	opts = signingOptions(t, SigningSettings{Method: SigningEd25519, Signer: "ci", KeyFile: "keys/audit.key"})
+	keyPath := filepath.Join(filepath.Dir(opts.RootDir), "keys", "audit.key")
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	writeSettings(t, keyPath, base64.StdEncoding.EncodeToString(key.Seed())+"\n")
+	signer, err = opts.AuditSigner()
+	if err != nil || signer.Name() != "ci" {
+		t.Fatalf("expected an Ed25519 signer from the key file, got %v (%v)", signer, err)
+	}
+
+	writeSettings(t, keyPath, "not a key")
+	if _, err := opts.AuditSigner(); err == nil || !strings.Contains(err.Error(), "invalid audit signing key") {
+		t.Fatalf("expected an invalid key error, got %v", err)
+	}
+	writeSettings(t, keyPath, "\n")
+	if _, err := opts.AuditSigner(); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Fatalf("expected an empty key error, got %v", err)
+	}
+	opts.Settings.Audit.Signing.KeyFile = filepath.Join(t.TempDir(), "missing.key")
+	if _, err := opts.AuditSigner(); err == nil || !strings.Contains(err.Error(), "failed to read audit signing key") {
+		t.Fatalf("expected a read error, got %v", err)
+	}
+}
+
+func TestAuditLoggerAndKeyring(t *testing.T) {
+	opts := signingOptions(t, SigningSettings{Method: SigningHMAC})
+	t.Setenv(AuditKeyEnv, "s3cret")
+	logger, err := opts.AuditLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log("create", "alice", "FTR-0001", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := opts.AuditKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(opts.RootDir, "audit.jsonl")
+	result, err := audit.VerifyWith(path, audit.VerifyOptions{Keys: keys, RequireSigned: true})
+	if err != nil || !result.OK() || result.Signed != 1 || result.Attributions[0].Signer != "alice" {
+		t.Fatalf("expected a verified HMAC entry, got %+v (%v)", result, err)
+	}
+
+	// A key that cannot be loaded refuses a logger rather than write unsigned entries.
+	t.Setenv(AuditKeyEnv, "")
+	if _, err := opts.AuditLogger(); err == nil || !strings.Contains(err.Error(), "audit signing is configured but unavailable") {
+		t.Fatalf("expected a signing error, got %v", err)
+	}
+	if _, err := opts.AuditKeyring(); err == nil {
+		t.Fatal("expected the keyring to need the HMAC secret")
+	}
+
+	if err := os.MkdirAll(opts.AuditKeysDir(), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	writeSettings(t, filepath.Join(opts.AuditKeysDir(), "bob.pub"), "garbage")
+	opts.Settings.Audit.Signing.Method = ""
+	if _, err := opts.AuditKeyring(); err == nil {
+		t.Fatal("expected an invalid public key error")
+	}
+
+	writeSettings(t, path, "")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := opts.AuditLogger(); err == nil {
+		t.Fatal("expected an error opening a directory as the audit log")
+	}
+}
+
+func TestAuditKeyringPinnedKeys(t *testing.T) {
+	opts := signingOptions(t, SigningSettings{Method: SigningEd25519, Signer: "alice"})
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	t.Setenv(AuditKeyEnv, base64.StdEncoding.EncodeToString(key.Seed()))
+	logger, err := opts.AuditLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log("create", "alice", "FTR-0001", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A key planted in the workspace is ignored once keys are pinned elsewhere.
+	if err := os.MkdirAll(opts.AuditKeysDir(), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	writeSettings(t, filepath.Join(opts.AuditKeysDir(), "alice.pub"), base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)))
+	pinned := t.TempDir()
+	opts.Settings.Audit.TrustedKeys = pinned
+	if opts.AuditKeysDir() != pinned {
+		t.Fatalf("expected the pinned directory, got %s", opts.AuditKeysDir())
+	}
+	keys, err := opts.AuditKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(opts.RootDir, "audit.jsonl")
+	result, err := audit.VerifyWith(path, audit.VerifyOptions{Keys: keys, RequireSigned: true})
+	if err != nil || result.OK() {
+		t.Fatalf("expected the workspace key to be ignored, got %+v (%v)", result, err)
+	}
+
+	writeSettings(t, filepath.Join(pinned, "alice.pub"), base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)))
+	if keys, err = opts.AuditKeyring(); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = audit.VerifyWith(path, audit.VerifyOptions{Keys: keys, RequireSigned: true}); err != nil || !result.OK() {
+		t.Fatalf("expected the pinned key to verify, got %+v (%v)", result, err)
+	}
+
+	opts.Settings.Audit.TrustedKeys = filepath.Join(pinned, "missing")
+	if _, err := opts.AuditKeyring(); err == nil || !strings.Contains(err.Error(), "trusted audit keys") {
+		t.Fatalf("expected a missing pinned directory to fail, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package epic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+var (
+	// ErrNotFound indicates an epic could not be located.
+	ErrNotFound = errors.New("epic not found")
+	// ErrExists indicates an epic with the same ID is already defined.
+	ErrExists = errors.New("epic already exists")
+)
+
+// InvalidFile represents an epic file that failed to parse or validate.
+type InvalidFile struct {
+	Path   string
+	Reason string
+}
+
+// InvalidFileError aggregates multiple parse failures.
+type InvalidFileError struct {
+	Files []InvalidFile
+}
+
+func (e *InvalidFileError) Error() string {
+	var parts []string
+	for _, f := range e.Files {
+		parts = append(parts, fmt.Sprintf("%s: %s", f.Path, f.Reason))
+	}
+	return fmt.Sprintf("failed to parse %d epic file(s): %s", len(e.Files), strings.Join(parts, "; "))
+}
+
+// Manager encapsulates epic file operations.
+type Manager struct {
+	opts *config.Options
+	log  *logrus.Entry
+	// planned collects the changes skipped in dry-run mode.
+	planned patch.Recorder
+}
+
+// NewManager constructs a manager with shared configuration.
+func NewManager(opts *config.Options) *Manager {
+	return &Manager{
+		opts: opts,
+		log:  opts.Logger().WithField("component", "epic"),
+	}
+}
+
+// Patches returns the file changes planned so far in dry-run mode, in order.
+func (m *Manager) Patches() []patch.Patch {
+	return m.planned.Patches()
+}
+
+// EpicsDir returns the path to the epics directory.
+func (m *Manager) EpicsDir() string {
+	return filepath.Join(m.opts.RootDir, "epics")
+}
+
+// Defined reports whether the workspace has an epics directory. Workspaces
+// without one treat epic references as free-form labels.
+func (m *Manager) Defined() bool {
+	info, err := os.Stat(m.EpicsDir())
+	return err == nil && info.IsDir()
+}
+
+// LoadByID returns the epic with the given ID, ignoring case.
+func (m *Manager) LoadByID(id string) (*Epic, error) {
+	epics, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range epics {
+		if Key(e.FrontMatter.ID) == Key(id) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Save persists the epic to disk.
+func (m *Manager) Save(e *Epic) error {
+	data, err := e.Encode()
+	if err != nil {
+		return err
+	}
+	if m.opts.DryRun {
+		m.log.WithFields(logrus.Fields{
+			"action": "save",
+			"path":   e.Path,
+			"dryRun": true,
+		}).Info("Skipping write in dry-run mode")
+		p, err := patch.Write(filepath.Dir(m.opts.RootDir), e.Path, e.Path, data)
+		m.planned.Add(p)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0o750); err != nil {
+		return fmt.Errorf("failed to create epics directory: %w", err)
+	}
+	return util.WriteFileAtomic(e.Path, data, 0o644)
+}
+
+// Create defines a new epic. The ID defaults to the slugified title.
+func (m *Manager) Create(title, id, owner, targetDate string) (*Epic, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("epic title must not be empty")
+	}
+	if id == "" {
+		id = util.Slugify(title)
+	}
+	id = Key(id)
+	if owner == "" {
+		owner = m.opts.Settings.Owner
+	}
+	today := time.Now().Format("2006-01-02")
+	e := &Epic{
+		Path: filepath.Join(m.EpicsDir(), id+".md"),
+		FrontMatter: FrontMatter{
+			ID:         id,
+			Title:      title,
+			Status:     Statuses[0],
+			Owner:      owner,
+			TargetDate: targetDate,
+			Created:    today,
+			Updated:    today,
+		},
+		Body: fmt.Sprintf("# %s\n\n## Goal\n\n## Scope\n\n## Milestones\n", title),
+	}
+	if problems := e.Validate(); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid epic: %s", strings.Join(problems, "; "))
+	}
+
+	if _, err := m.LoadByID(id); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrExists, id)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err := m.Save(e); err != nil {
+		return nil, err
+	}
+	m.log.WithFields(logrus.Fields{
+		"action": "new",
+		"id":     id,
+		"path":   e.Path,
+	}).Info("Epic created")
+	return e, nil
+}
+
+// List returns all epics sorted by ID. Files that fail to parse or validate are
+// reported together as an InvalidFileError.
+func (m *Manager) List() ([]*Epic, error) {
+	entries, err := os.ReadDir(m.EpicsDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []*Epic{}, nil
+		}
+		return nil, err
+	}
+
+	epics := []*Epic{}
+	var invalidFiles []InvalidFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".md" || strings.EqualFold(name, "index.md") || strings.EqualFold(name, "readme.md") {
+			continue
+		}
+		path := filepath.Join(m.EpicsDir(), name)
+		// #nosec G304 -- epic paths are derived from repository structure during discovery
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil, readErr
+		}
+		e, parseErr := Parse(path, data)
+		if parseErr != nil {
+			invalidFiles = append(invalidFiles, InvalidFile{Path: path, Reason: parseErr.Error()})
+			continue
+		}
+		if problems := e.Validate(); len(problems) > 0 {
+			invalidFiles = append(invalidFiles, InvalidFile{Path: path, Reason: strings.Join(problems, "; ")})
+			continue
+		}
+		epics = append(epics, e)
+	}
+
+	if len(invalidFiles) > 0 {
+		return nil, &InvalidFileError{Files: invalidFiles}
+	}
+	sort.Slice(epics, func(i, j int) bool {
+		return epics[i].FrontMatter.ID < epics[j].FrontMatter.ID
+	})
+	return epics, nil
+}
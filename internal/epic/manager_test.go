@@ -0,0 +1,109 @@
+package epic
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestManagerCreateAndLoad(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	opts.Settings.Owner = "bob"
+	mgr := NewManager(opts)
+
+	if mgr.Defined() {
+		t.Fatalf("expected no epics directory in a fresh workspace")
+	}
+	if epics, err := mgr.List(); err != nil || len(epics) != 0 {
+		t.Fatalf("expected empty list, got %v %v", epics, err)
+	}
+
+	e, err := mgr.Create("  Checkout Revamp ", "", "", "2026-12-01")
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if e.FrontMatter.ID != "checkout-revamp" || e.FrontMatter.Status != "planned" || e.FrontMatter.Owner != "bob" {
+		t.Fatalf("unexpected epic %+v", e.FrontMatter)
+	}
+	if e.Path != fix.Path("epics", "checkout-revamp.md") || !mgr.Defined() {
+		t.Fatalf("expected epic file at %s", e.Path)
+	}
+	if !strings.Contains(e.Body, "## Goal") {
+		t.Fatalf("expected default body, got %q", e.Body)
+	}
+
+	if _, err := mgr.Create("Search", "Search-V2", "carol", ""); err != nil {
+		t.Fatalf("create with explicit id failed: %v", err)
+	}
+	loaded, err := mgr.LoadByID("SEARCH-v2")
+	if err != nil || loaded.FrontMatter.Owner != "carol" || loaded.FrontMatter.ID != "search-v2" {
+		t.Fatalf("unexpected load: %v %+v", err, loaded)
+	}
+
+	epics, err := mgr.List()
+	if err != nil || len(epics) != 2 || epics[0].FrontMatter.ID != "checkout-revamp" {
+		t.Fatalf("unexpected list: %v %v", epics, err)
+	}
+
+	if _, err := mgr.Create("Checkout revamp", "", "", ""); !errors.Is(err, ErrExists) {
+		t.Fatalf("expected exists error, got %v", err)
+	}
+	if _, err := mgr.LoadByID("nope"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := mgr.Create("   ", "", "", ""); err == nil {
+		t.Fatalf("expected empty title error")
+	}
+	if _, err := mgr.Create("Later", "", "", "next year"); err == nil || !strings.Contains(err.Error(), "target_date") {
+		t.Fatalf("expected target date error, got %v", err)
+	}
+}
+
+func TestManagerCreateDryRun(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, true))
+	e, err := mgr.Create("Checkout", "", "", "")
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, statErr := os.Stat(e.Path); !errors.Is(statErr, os.ErrNotExist) {
+		t.Fatalf("expected no file in dry-run, got %v", statErr)
+	}
+	patches := mgr.Patches()
+	if len(patches) != 1 || patches[0].Op != patch.Create || patches[0].Path != ".virtualboard/epics/checkout.md" ||
+		!strings.Contains(patches[0].Diff, "+title: Checkout") {
+		t.Fatalf("expected a create patch, got %+v", patches)
+	}
+}
+
+func TestManagerListInvalid(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	valid := "---\nid: checkout\ntitle: Checkout\nstatus: active\ncreated: 2026-01-01\nupdated: 2026-01-01\n---\n"
+	fix.WriteFile(t, "epics/checkout.md", []byte(valid))
+	fix.WriteFile(t, "epics/README.md", []byte("ignored"))
+	fix.WriteFile(t, "epics/notes.txt", []byte("ignored"))
+	if epics, err := mgr.List(); err != nil || len(epics) != 1 {
+		t.Fatalf("expected one epic, got %v %v", epics, err)
+	}
+
+	fix.WriteFile(t, "epics/broken.md", []byte("broken"))
+	fix.WriteFile(t, "epics/copy.md", []byte(strings.Replace(valid, "id: checkout", "id: copy\n", 1)))
+	fix.WriteFile(t, "epics/wrong.md", []byte(strings.Replace(valid, "status: active", "status: open", 1)))
+	_, err := mgr.List()
+	var invalid *InvalidFileError
+	if !errors.As(err, &invalid) || len(invalid.Files) != 2 {
+		t.Fatalf("expected two invalid files, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed to parse 2 epic file(s)") || !strings.Contains(err.Error(), "no frontmatter found") || !strings.Contains(err.Error(), `status "open"`) {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if _, err := mgr.LoadByID("checkout"); !errors.As(err, &invalid) {
+		t.Fatalf("expected load to surface parse errors, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package epic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+// Statuses lists the allowed epic statuses, in lifecycle order.
+var Statuses = []string{"planned", "active", "done", "cancelled"}
+
+// FrontMatter represents the YAML header of an epic.
+type FrontMatter struct {
+	ID         string `yaml:"id" json:"id"`
+	Title      string `yaml:"title" json:"title"`
+	Status     string `yaml:"status" json:"status"`
+	Owner      string `yaml:"owner,omitempty" json:"owner,omitempty"`
+	TargetDate string `yaml:"target_date,omitempty" json:"target_date,omitempty"`
+	Created    string `yaml:"created" json:"created"`
+	Updated    string `yaml:"updated" json:"updated"`
+}
+
+// Epic groups features that deliver a larger outcome together.
+type Epic struct {
+	Path        string
+	FrontMatter FrontMatter
+	Body        string
+}
+
+var (
+	// ErrNoFrontmatter indicates missing frontmatter.
+	ErrNoFrontmatter = errors.New("no frontmatter found")
+	// ErrInvalidFrontmatter indicates unparsable frontmatter.
+	ErrInvalidFrontmatter = errors.New("invalid frontmatter format")
+)
+
+const frontmatterDelimiter = "---"
+
+var idPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// Parse reads an epic file and extracts frontmatter and body.
+func Parse(path string, data []byte) (*Epic, error) {
+	lines := strings.Split(string(data), "\n")
+	if len(lines) < 3 || strings.TrimSpace(lines[0]) != frontmatterDelimiter {
+		return nil, ErrNoFrontmatter
+	}
+	endIdx := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontmatterDelimiter {
+			endIdx = i
+			break
+		}
+	}
+	if endIdx == -1 {
+		return nil, ErrNoFrontmatter
+	}
+
+	var fm FrontMatter
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:endIdx], "\n")), &fm); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFrontmatter, err)
+	}
+	return &Epic{
+		Path:        path,
+		FrontMatter: fm,
+		Body:        strings.TrimLeft(strings.Join(lines[endIdx+1:], "\n"), "\n"),
+	}, nil
+}
+
+// Encode serializes the epic back to markdown with frontmatter.
+func (e *Epic) Encode() ([]byte, error) {
+	fmData, err := yaml.Marshal(&e.FrontMatter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal frontmatter: %w", err)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(frontmatterDelimiter + "\n")
+	buf.Write(fmData)
+	buf.WriteString(frontmatterDelimiter + "\n\n")
+	buf.WriteString(e.Body)
+	return buf.Bytes(), nil
+}
+
+// Validate returns every problem with the epic's frontmatter.
+func (e *Epic) Validate() []string {
+	var problems []string
+	fm := e.FrontMatter
+	if !idPattern.MatchString(fm.ID) {
+		problems = append(problems, fmt.Sprintf("id %q must be lowercase letters, digits and dashes", fm.ID))
+	} else if base := strings.TrimSuffix(filepath.Base(e.Path), ".md"); base != fm.ID {
+		problems = append(problems, fmt.Sprintf("filename '%s.md' should be '%s.md'", base, fm.ID))
+	}
+	if strings.TrimSpace(fm.Title) == "" {
+		problems = append(problems, "title is required")
+	}
+	if !validStatus(fm.Status) {
+		problems = append(problems, fmt.Sprintf("status %q must be one of %s", fm.Status, strings.Join(Statuses, ", ")))
+	}
+	for name, value := range map[string]string{"created": fm.Created, "updated": fm.Updated, "target_date": fm.TargetDate} {
+		if value == "" && name == "target_date" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s date must be YYYY-MM-DD", name))
+		}
+	}
+	return problems
+}
+
+func validStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Progress rolls up the statuses of an epic's features.
+type Progress struct {
+	Total    int            `json:"total"`
+	Done     int            `json:"done"`
+	Percent  int            `json:"percent"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// Rollup counts features per epic, keyed by the lowercased epic reference.
+// Features without an epic are skipped.
+func Rollup(features []*feature.Feature, wf *workflow.Workflow) map[string]*Progress {
+	out := map[string]*Progress{}
+	for _, feat := range features {
+		key := Key(feat.FrontMatter.Epic)
+		if key == "" {
+			continue
+		}
+		p, ok := out[key]
+		if !ok {
+			p = &Progress{ByStatus: map[string]int{}}
+			out[key] = p
+		}
+		p.Total++
+		p.ByStatus[feat.FrontMatter.Status]++
+		if wf.IsDone(feat.FrontMatter.Status) {
+			p.Done++
+		}
+		p.Percent = p.Done * 100 / p.Total
+	}
+	return out
+}
+
+// Key normalises an epic reference for comparison.
+func Key(ref string) string {
+	return strings.ToLower(strings.TrimSpace(ref))
+}
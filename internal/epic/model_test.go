@@ -0,0 +1,107 @@
+package epic
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+const sampleEpic = `---
+id: checkout
+title: Checkout revamp
+status: active
+owner: alice
+target_date: 2026-12-01
+created: 2026-01-01
+updated: 2026-01-02
+---
+
+# Checkout revamp
+
+## Goal
+`
+
+func TestParseAndEncode(t *testing.T) {
+	e, err := Parse("/tmp/epics/checkout.md", []byte(sampleEpic))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	want := FrontMatter{ID: "checkout", Title: "Checkout revamp", Status: "active", Owner: "alice", TargetDate: "2026-12-01", Created: "2026-01-01", Updated: "2026-01-02"}
+	if e.FrontMatter != want {
+		t.Fatalf("unexpected frontmatter %+v", e.FrontMatter)
+	}
+	if !strings.HasPrefix(e.Body, "# Checkout revamp") {
+		t.Fatalf("unexpected body %q", e.Body)
+	}
+	if problems := e.Validate(); len(problems) != 0 {
+		t.Fatalf("expected valid epic, got %v", problems)
+	}
+
+	data, err := e.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	again, err := Parse(e.Path, data)
+	if err != nil || again.FrontMatter != want || again.Body != e.Body {
+		t.Fatalf("round trip mismatch: %v %+v", err, again)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]error{
+		"no frontmatter":   ErrNoFrontmatter,
+		"---\nid: a\nbody": ErrNoFrontmatter,
+		"---\n: [\n---\n":  ErrInvalidFrontmatter,
+	}
+	for input, want := range cases {
+		if _, err := Parse("x.md", []byte(input)); !errors.Is(err, want) {
+			t.Fatalf("%q: expected %v, got %v", input, want, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	e := &Epic{Path: "/tmp/epics/other.md", FrontMatter: FrontMatter{ID: "checkout", Status: "open", Created: "2026-01-01", Updated: "soon", TargetDate: "Q4"}}
+	got := strings.Join(e.Validate(), "\n")
+	for _, want := range []string{
+		"filename 'other.md' should be 'checkout.md'",
+		"title is required",
+		`status "open" must be one of planned, active, done, cancelled`,
+		"updated date must be YYYY-MM-DD",
+		"target_date date must be YYYY-MM-DD",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "created") {
+		t.Fatalf("did not expect a created error:\n%s", got)
+	}
+
+	e.FrontMatter.ID = "Bad ID"
+	if got := e.Validate(); !strings.Contains(got[0], "lowercase letters, digits and dashes") {
+		t.Fatalf("expected id error, got %v", got)
+	}
+}
+
+func TestRollup(t *testing.T) {
+	features := []*feature.Feature{
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0001", Status: "done", Epic: "checkout"}},
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0002", Status: "in-progress", Epic: " Checkout "}},
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0003", Status: "backlog", Epic: "checkout"}},
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0004", Status: "backlog", Epic: "search"}},
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0005", Status: "done"}},
+	}
+	got := Rollup(features, workflow.Default())
+	want := map[string]*Progress{
+		"checkout": {Total: 3, Done: 1, Percent: 33, ByStatus: map[string]int{"done": 1, "in-progress": 1, "backlog": 1}},
+		"search":   {Total: 1, Done: 0, Percent: 0, ByStatus: map[string]int{"backlog": 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected rollup %+v", got)
+	}
+}
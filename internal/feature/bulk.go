@@ -0,0 +1,155 @@
+package feature
+
+import (
+	"fmt"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+)
+
+// BulkResult is the outcome of a bulk operation for a single feature.
+type BulkResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+// bulkChange describes what a bulk step staged for one feature.
+type bulkChange struct {
+	path    string
+	message string
+	audit   string // audit details; empty when the step is not audited
+	changes *audit.Changes
+}
+
+// MoveFeatures moves every feature in ids to newStatus. Each feature is checked like
+// MoveFeature; those that pass are written in a single transaction.
+func (m *Manager) MoveFeatures(ids []string, newStatus, owner string) ([]BulkResult, error) {
+	return m.runBulk("move", ids, func(tx *Transaction, id string) (bulkChange, error) {
+		feat, err := m.LoadByID(id)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		before := captureState(feat)
+		from, snapshot, err := m.stageMove(tx, feat, newStatus, owner)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		m.logMove(feat, from)
+		changes := m.changeSet(before, feat)
+		changes.Snapshot = snapshot
+		return bulkChange{
+			path:    feat.Path,
+			message: fmt.Sprintf("Moved %s to %s", feat.FrontMatter.ID, feat.FrontMatter.Status),
+			audit:   fmt.Sprintf("status=%s", feat.FrontMatter.Status),
+			changes: changes,
+		}, nil
+	})
+}
+
+// UpdateFeatures applies fn to every feature in ids and saves those it accepts in a
+// single transaction.
+func (m *Manager) UpdateFeatures(ids []string, fn func(*Feature) error) ([]BulkResult, error) {
+	return m.runBulk("update", ids, func(tx *Transaction, id string) (bulkChange, error) {
+		feat, err := m.LoadByID(id)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		before := captureState(feat)
+		if err := fn(feat); err != nil {
+			return bulkChange{}, err
+		}
+		snapshot, err := m.stash(feat.Path)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		feat.UpdateTimestamp()
+		if err := tx.Save(feat); err != nil {
+			return bulkChange{}, err
+		}
+		changes := m.changeSet(before, feat)
+		changes.Snapshot = snapshot
+		return bulkChange{
+			path:    feat.Path,
+			message: fmt.Sprintf("Updated %s", feat.FrontMatter.ID),
+			audit:   changes.String(),
+			changes: changes,
+		}, nil
+	})
+}
+
+// DeleteFeatures removes every feature in ids in a single transaction.
+func (m *Manager) DeleteFeatures(ids []string) ([]BulkResult, error) {
+	return m.runBulk("delete", ids, func(tx *Transaction, id string) (bulkChange, error) {
+		path, err := m.findByID(id)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		before := m.loadState(path)
+		snapshot, err := m.stash(path)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		tx.Delete(path)
+		changes := m.changeSet(before, nil)
+		changes.Snapshot = snapshot
+		return bulkChange{
+			path:    path,
+			message: fmt.Sprintf("Deleted %s", id),
+			audit:   fmt.Sprintf("path=%s", path),
+			changes: changes,
+		}, nil
+	})
+}
+
+// runBulk stages every feature under its operational lock and commits the staged
+// changes together. Features that fail to stage are reported and left untouched. If
+// the commit fails, nothing is written and every staged feature is reported as failed.
+func (m *Manager) runBulk(action string, ids []string, stage func(*Transaction, string) (bulkChange, error)) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ids))
+	changes := make([]bulkChange, len(ids))
+	tx := m.Begin()
+
+	var releases []func()
+	defer func() {
+		for _, release := range releases {
+			release()
+		}
+	}()
+
+	for i, id := range ids {
+		results[i].ID = id
+		release, err := m.acquireOpLock(featureLockID(id))
+		if err == nil {
+			releases = append(releases, release)
+			changes[i], err = stage(tx, id)
+		}
+		if err != nil {
+			results[i].Err = err
+			results[i].Message = err.Error()
+			continue
+		}
+		results[i].Success = true
+		results[i].Path = changes[i].path
+		results[i].Message = changes[i].message
+	}
+
+	if err := tx.Commit(); err != nil {
+		for i := range results {
+			if results[i].Success {
+				results[i].Success = false
+				results[i].Err = err
+				results[i].Message = err.Error()
+			}
+		}
+		return results, err
+	}
+
+	for i, result := range results {
+		if result.Success && (changes[i].audit != "" || changes[i].changes != nil) {
+			m.auditEvent(action, result.ID, changes[i].audit, changes[i].changes)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,168 @@
+package feature
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestMoveFeaturesStagesValidFeatures(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0001", "backlog", "One", nil))
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0002", "done", "Two", nil))
+
+	results, err := mgr.MoveFeatures([]string{"FTR-0001", "FTR-0002", "FTR-0404"}, "in-progress", "dana")
+	if err != nil {
+		t.Fatalf("bulk move failed: %v", err)
+	}
+	if !results[0].Success || results[0].Message != "Moved FTR-0001 to in-progress" {
+		t.Fatalf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Success || !errors.Is(results[1].Err, ErrInvalidTransition) {
+		t.Fatalf("expected invalid transition: %+v", results[1])
+	}
+	if results[2].Success || !errors.Is(results[2].Err, ErrNotFound) {
+		t.Fatalf("expected not found: %+v", results[2])
+	}
+	moved, _ := mgr.LoadByID("FTR-0001")
+	if moved.FrontMatter.Status != "in-progress" || moved.FrontMatter.Owner != "dana" {
+		t.Fatalf("unexpected moved feature: %+v", moved.FrontMatter)
+	}
+
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	if err != nil {
+		t.Fatalf("read audit failed: %v", err)
+	}
+	moves := []string{}
+	for _, entry := range entries {
+		if entry.Action == "move" {
+			moves = append(moves, entry.FeatureID)
+		}
+	}
+	if len(moves) != 1 || moves[0] != "FTR-0001" {
+		t.Fatalf("expected one move audit entry, got %v", moves)
+	}
+}
+
+func TestMoveFeaturesSkipsFeaturesThatCannotBeStashed(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Kept", nil)
+	mustWriteFeature(t, fix, feat)
+	if err := os.WriteFile(mgr.TrashDir(), []byte("not a directory"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := mgr.MoveFeatures([]string{"FTR-0001"}, "in-progress", "")
+	if err != nil {
+		t.Fatalf("bulk move failed: %v", err)
+	}
+	if results[0].Success || !strings.Contains(results[0].Message, "failed to stash") {
+		t.Fatalf("expected a stash failure: %+v", results[0])
+	}
+	if _, err := os.Stat(feat.Path); err != nil {
+		t.Fatalf("a feature that could not be stashed must not move: %v", err)
+	}
+	if kept, _ := mgr.LoadByID("FTR-0001"); kept.FrontMatter.Status != "backlog" {
+		t.Fatalf("expected the feature to stay in backlog, got %s", kept.FrontMatter.Status)
+	}
+}
+
+func TestBulkCommitFailureMarksEveryFeatureFailed(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	one := newTestFeature(fix, "FTR-0001", "backlog", "One", nil)
+	two := newTestFeature(fix, "FTR-0002", "backlog", "Two", nil)
+	mustWriteFeature(t, fix, one)
+	mustWriteFeature(t, fix, two)
+	original, _ := os.ReadFile(one.Path)
+	failWritesTo(t, two.Path)
+
+	results, err := mgr.UpdateFeatures([]string{"FTR-0001", "FTR-0002"}, func(feat *Feature) error {
+		feat.FrontMatter.Priority = "high"
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expected commit failure")
+	}
+	for _, result := range results {
+		if result.Success || !strings.Contains(result.Message, "disk full") {
+			t.Fatalf("expected every feature to fail: %+v", result)
+		}
+	}
+	if data, _ := os.ReadFile(one.Path); string(data) != string(original) {
+		t.Fatalf("expected first feature to be rolled back")
+	}
+}
+
+func TestUpdateFeaturesReportsApplyErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0001", "backlog", "One", nil))
+
+	results, err := mgr.UpdateFeatures([]string{"FTR-0001"}, func(*Feature) error { return errors.New("bad field") })
+	if err != nil || results[0].Success || results[0].Message != "bad field" {
+		t.Fatalf("unexpected results: %v %+v", err, results)
+	}
+}
+
+func TestDeleteFeaturesWithLockConflict(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	one := newTestFeature(fix, "FTR-0001", "backlog", "One", nil)
+	two := newTestFeature(fix, "FTR-0002", "backlog", "Two", nil)
+	mustWriteFeature(t, fix, one)
+	mustWriteFeature(t, fix, two)
+
+	if _, err := mgr.lockMgr.Acquire(featureLockID("ftr-0002"), "someone", 1, false); err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	results, err := mgr.DeleteFeatures([]string{"FTR-0001", "FTR-0002", "FTR-0404"})
+	if err != nil {
+		t.Fatalf("bulk delete failed: %v", err)
+	}
+	if !results[0].Success || results[0].Path != one.Path {
+		t.Fatalf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Success || !strings.Contains(results[1].Message, "operational lock") {
+		t.Fatalf("expected lock conflict: %+v", results[1])
+	}
+	if results[2].Success {
+		t.Fatalf("expected missing feature to fail")
+	}
+	if _, err := os.Stat(one.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected first feature to be deleted")
+	}
+	if _, err := os.Stat(two.Path); err != nil {
+		t.Fatalf("locked feature should remain: %v", err)
+	}
+}
+
+func TestBulkDryRunSkipsAudit(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, true))
+	one := newTestFeature(fix, "FTR-0001", "backlog", "One", nil)
+	mustWriteFeature(t, fix, one)
+
+	results, err := mgr.DeleteFeatures([]string{"FTR-0001"})
+	if err != nil || !results[0].Success {
+		t.Fatalf("dry-run delete failed: %v %+v", err, results)
+	}
+	if _, err := os.Stat(one.Path); err != nil {
+		t.Fatalf("dry-run should keep the file: %v", err)
+	}
+	entries, _, _ := audit.ReadEntries(mgr.AuditPath())
+	for _, entry := range entries {
+		if entry.Action == "delete" {
+			t.Fatalf("dry-run should not audit deletes, got %+v", entry)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package feature
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+)
+
+// featureState is a feature's state before a change, kept to build the audit
+// change set afterwards.
+type featureState struct {
+	path     string
+	fields   map[string]interface{}
+	sections map[string]string
+	order    []string
+}
+
+// captureState captures the feature's path, frontmatter and sections.
+func captureState(feat *Feature) *featureState {
+	order, sections := ExtractSections(feat.Body)
+	return &featureState{
+		path:     feat.Path,
+		fields:   normalizeFields(feat.FrontMatter.Map()),
+		sections: sections,
+		order:    order,
+	}
+}
+
+// loadState captures the feature as it is on disk at path; nil when the file
+// cannot be read.
+func (m *Manager) loadState(path string) *featureState {
+	// #nosec G304 -- feature paths are derived from repository structure during discovery
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	feat, err := Parse(path, data)
+	if err != nil {
+		return nil
+	}
+	m.attachFields(feat)
+	return captureState(feat)
+}
+
+// changeSet describes how a feature changed: the frontmatter fields with their old
+// and new values, the sections added, removed or edited, the file move and the
+// hash of the file written. A nil before describes a created feature, whose
+// sections are not listed; a nil after describes a deleted one.
+func (m *Manager) changeSet(before *featureState, after *Feature) *audit.Changes {
+	old, updated := &featureState{}, &featureState{}
+	if before != nil {
+		old = before
+	}
+	if after != nil {
+		updated = captureState(after)
+	}
+	changes := &audit.Changes{}
+
+	keys := map[string]bool{}
+	for key := range old.fields {
+		keys[key] = true
+	}
+	for key := range updated.fields {
+		keys[key] = true
+	}
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if from, to := old.fields[name], updated.fields[name]; !reflect.DeepEqual(from, to) {
+			changes.Fields = append(changes.Fields, audit.FieldChange{Field: name, Old: from, New: to})
+		}
+	}
+
+	if before != nil && after != nil {
+		for _, name := range updated.order {
+			content, existed := old.sections[name]
+			switch {
+			case !existed:
+				changes.Sections = append(changes.Sections, audit.SectionChange{Section: name, Change: audit.SectionAdded})
+			case strings.TrimSpace(content) != strings.TrimSpace(updated.sections[name]):
+				changes.Sections = append(changes.Sections, audit.SectionChange{Section: name, Change: audit.SectionEdited})
+			}
+		}
+		for _, name := range old.order {
+			if _, kept := updated.sections[name]; !kept {
+				changes.Sections = append(changes.Sections, audit.SectionChange{Section: name, Change: audit.SectionRemoved})
+			}
+		}
+	}
+
+	if old.path != updated.path {
+		changes.Path = &audit.PathChange{From: m.projectPath(old.path), To: m.projectPath(updated.path)}
+	}
+	if after != nil {
+		if data, err := after.Encode(); err == nil {
+			changes.Result = contentHash(data)
+		}
+	}
+	return changes
+}
+
+// projectPath returns path relative to the project root with forward slashes.
+func (m *Manager) projectPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if rel, err := filepath.Rel(filepath.Dir(m.opts.RootDir), path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// normalizeFields gives field values the types JSON decoding produces, so they
+// compare and hash the same after the audit entry is read back. Empty values are
+// dropped.
+func normalizeFields(fields map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return out
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return map[string]interface{}{}
+	}
+	for key, value := range out {
+		switch v := value.(type) {
+		case nil:
+			delete(out, key)
+		case string:
+			if v == "" {
+				delete(out, key)
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				delete(out, key)
+			}
+		}
+	}
+	return out
+}
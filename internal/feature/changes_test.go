@@ -0,0 +1,156 @@
+package feature
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+// lastAudit returns the newest audit entry, checking that the chain verifies.
+func lastAudit(t *testing.T, mgr *Manager) audit.Entry {
+	t.Helper()
+	result, err := audit.Verify(mgr.AuditPath())
+	if err != nil || !result.OK() {
+		t.Fatalf("expected an intact audit chain, got %+v (%v)", result, err)
+	}
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("expected audit entries: %v", err)
+	}
+	return entries[len(entries)-1]
+}
+
+func TestUpdateFeatureAuditsChanges(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0001", "backlog", "Audited", []string{"api"}))
+
+	feat, err := mgr.LoadByID("FTR-0001")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	feat.FrontMatter.Priority = "high"
+	feat.FrontMatter.Labels = append(feat.FrontMatter.Labels, "ui")
+	feat.Body = "## Summary\n\nRewritten.\n\n## Notes\n\nNew.\n"
+	if err := mgr.UpdateFeature(feat); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	entry := lastAudit(t, mgr)
+	if entry.Action != "update" || entry.FeatureID != "FTR-0001" {
+		t.Fatalf("expected an update entry, got %+v", entry)
+	}
+	priority, ok := entry.Changes.Field("priority")
+	if !ok || priority.Old != "medium" || priority.New != "high" {
+		t.Fatalf("expected the priority change, got %+v", entry.Changes)
+	}
+	labels, ok := entry.Changes.Field("labels")
+	if !ok || len(labels.Old.([]interface{})) != 1 || len(labels.New.([]interface{})) != 2 {
+		t.Fatalf("expected the label change, got %+v", labels)
+	}
+	if _, ok := entry.Changes.Field("title"); ok {
+		t.Fatalf("unchanged fields should not be recorded: %+v", entry.Changes)
+	}
+	want := []audit.SectionChange{
+		{Section: "Summary", Change: audit.SectionEdited},
+		{Section: "Notes", Change: audit.SectionAdded},
+		{Section: "Details", Change: audit.SectionRemoved},
+	}
+	if len(entry.Changes.Sections) != len(want) {
+		t.Fatalf("unexpected sections %+v", entry.Changes.Sections)
+	}
+	for i, s := range want {
+		if entry.Changes.Sections[i] != s {
+			t.Fatalf("expected %+v, got %+v", s, entry.Changes.Sections[i])
+		}
+	}
+	if entry.Changes.Path != nil || !strings.Contains(entry.Details, "priority: medium -> high") {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+}
+
+func TestMoveCreateDeleteAuditChanges(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	created, err := mgr.CreateFeature("Tracked", []string{"api"})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	entry := lastAudit(t, mgr)
+	title, _ := entry.Changes.Field("title")
+	if entry.Action != "create" || title.Old != nil || title.New != "Tracked" || len(entry.Changes.Sections) != 0 ||
+		entry.Changes.Path == nil || entry.Changes.Path.From != "" || entry.Changes.Path.To != ".virtualboard/features/backlog/FTR-0001-tracked.md" {
+		t.Fatalf("unexpected create entry %+v", entry)
+	}
+
+	if _, _, err := mgr.MoveFeature(created.FrontMatter.ID, "in-progress", "alice"); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	entry = lastAudit(t, mgr)
+	status, _ := entry.Changes.Field("status")
+	owner, _ := entry.Changes.Field("owner")
+	if entry.Details != "status=in-progress" || status.Old != "backlog" || status.New != "in-progress" || owner.New != "alice" ||
+		entry.Changes.Path.To != ".virtualboard/features/in-progress/FTR-0001-tracked.md" {
+		t.Fatalf("unexpected move entry %+v", entry)
+	}
+
+	if _, err := mgr.DeleteFeature(created.FrontMatter.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	entry = lastAudit(t, mgr)
+	status, _ = entry.Changes.Field("status")
+	if entry.Action != "delete" || status.Old != "in-progress" || status.New != nil ||
+		entry.Changes.Path.From != ".virtualboard/features/in-progress/FTR-0001-tracked.md" || entry.Changes.Path.To != "" {
+		t.Fatalf("unexpected delete entry %+v", entry)
+	}
+}
+
+func TestUpdateFeaturesAuditsChanges(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0001", "backlog", "One", nil))
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0002", "backlog", "Two", nil))
+
+	results, err := mgr.UpdateFeatures([]string{"FTR-0001", "FTR-0002"}, func(feat *Feature) error {
+		feat.FrontMatter.Owner = "bob"
+		return nil
+	})
+	if err != nil || !results[0].Success || !results[1].Success {
+		t.Fatalf("bulk update failed: %v %+v", err, results)
+	}
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	entries = audit.Filter{Actions: []string{"update"}}.Apply(entries)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected one audit entry per feature, got %+v (%v)", entries, err)
+	}
+	for i, entry := range entries {
+		owner, ok := entry.Changes.Field("owner")
+		if entry.FeatureID != results[i].ID || !ok || owner.Old != "owner" || owner.New != "bob" {
+			t.Fatalf("unexpected entry %+v", entry)
+		}
+	}
+	lastAudit(t, mgr)
+}
+
+func TestChangeSetWithoutChanges(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Same", nil)
+	changes := mgr.changeSet(captureState(feat), feat)
+	if len(changes.Fields) > 0 || len(changes.Sections) > 0 || changes.Path != nil || changes.Result == "" {
+		t.Fatalf("expected only the result hash, got %+v", changes)
+	}
+	if state := mgr.loadState(fix.Path("features", "missing.md")); state != nil {
+		t.Fatalf("expected no state for a missing file")
+	}
+	fix.WriteFile(t, "features/backlog/broken.md", []byte("---\nid: [\n---\n"))
+	if state := mgr.loadState(fix.Path("features", "backlog", "broken.md")); state != nil {
+		t.Fatalf("expected no state for an unparsable file")
+	}
+	if got := mgr.projectPath(""); got != "" {
+		t.Fatalf("expected an empty path, got %q", got)
+	}
+}
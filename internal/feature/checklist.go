@@ -0,0 +1,136 @@
+package feature
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var taskPattern = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+// Task is a Markdown task list item ("- [ ] ..." or "- [x] ...") in a feature body.
+type Task struct {
+	// Section is the H2 section containing the item, or "" for the intro.
+	Section string `json:"section"`
+	Text    string `json:"text"`
+	Done    bool   `json:"done"`
+}
+
+// Checklist counts the task items of one section, or of the whole body when
+// Section is empty.
+type Checklist struct {
+	Section string `json:"section,omitempty"`
+	Total   int    `json:"total"`
+	Done    int    `json:"done"`
+	Percent int    `json:"percent"`
+}
+
+// String renders the checklist as "done/total (percent%)".
+func (c Checklist) String() string {
+	return fmt.Sprintf("%d/%d (%d%%)", c.Done, c.Total, c.Percent)
+}
+
+func (c *Checklist) add(done bool) {
+	c.Total++
+	if done {
+		c.Done++
+	}
+	c.Percent = c.Done * 100 / c.Total
+}
+
+// Tasks returns every task list item in the body, in order. Items inside fenced
+// code blocks are ignored.
+func (f *Feature) Tasks() []Task {
+	tasks := []Task{}
+	section := ""
+	fenced := false
+	for _, line := range strings.Split(f.Body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			section = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		}
+		if m := taskPattern.FindStringSubmatch(line); m != nil {
+			tasks = append(tasks, Task{Section: section, Text: strings.TrimSpace(m[2]), Done: m[1] != " "})
+		}
+	}
+	return tasks
+}
+
+// Checklists returns the progress of each section that has task items, in body order.
+func (f *Feature) Checklists() []Checklist {
+	lists := []Checklist{}
+	index := map[string]int{}
+	for _, task := range f.Tasks() {
+		i, ok := index[task.Section]
+		if !ok {
+			i = len(lists)
+			index[task.Section] = i
+			lists = append(lists, Checklist{Section: task.Section})
+		}
+		lists[i].add(task.Done)
+	}
+	return lists
+}
+
+// ChecklistTotal returns the progress over every task item in the body.
+func (f *Feature) ChecklistTotal() Checklist {
+	var total Checklist
+	for _, task := range f.Tasks() {
+		total.add(task.Done)
+	}
+	return total
+}
+
+// UncheckedIn returns the unchecked task items in the named sections, matched
+// case-insensitively. With no sections, every section is considered.
+func (f *Feature) UncheckedIn(sections []string) []Task {
+	unchecked := []Task{}
+	for _, task := range f.Tasks() {
+		if task.Done {
+			continue
+		}
+		if len(sections) == 0 || containsFold(sections, task.Section) {
+			unchecked = append(unchecked, task)
+		}
+	}
+	return unchecked
+}
+
+// DescribeUnchecked summarises unchecked items per section, e.g.
+// "2 unchecked item(s) in Acceptance Criteria".
+func DescribeUnchecked(tasks []Task) string {
+	var order []string
+	counts := map[string]int{}
+	for _, task := range tasks {
+		name := task.Section
+		if name == "" {
+			name = "the introduction"
+		}
+		if counts[name] == 0 {
+			order = append(order, name)
+		}
+		counts[name]++
+	}
+	parts := make([]string, 0, len(order))
+	for _, name := range order {
+		parts = append(parts, fmt.Sprintf("%d unchecked item(s) in %s", counts[name], name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package feature
+
+import (
+	"reflect"
+	"testing"
+)
+
+const checklistBody = `Intro
+- [ ] intro task
+
+## Summary
+Plain text.
+
+## Acceptance Criteria
+- [x] Logs in
+* [X] Logs out
+- [ ] Resets password
+  + [ ] nested item
+
+` + "```" + `
+- [ ] not a task in a code block
+` + "```" + `
+
+## Test Plan
+- [x] Unit tests
+- not a task
+- [] not a task either
+`
+
+func TestTasksAndChecklists(t *testing.T) {
+	feat := &Feature{Body: checklistBody}
+	tasks := feat.Tasks()
+	if len(tasks) != 6 {
+		t.Fatalf("expected 6 tasks, got %+v", tasks)
+	}
+	if tasks[0] != (Task{Section: "", Text: "intro task"}) || tasks[2] != (Task{Section: "Acceptance Criteria", Text: "Logs out", Done: true}) {
+		t.Fatalf("unexpected tasks %+v", tasks)
+	}
+
+	want := []Checklist{
+		{Section: "", Total: 1, Done: 0, Percent: 0},
+		{Section: "Acceptance Criteria", Total: 4, Done: 2, Percent: 50},
+		{Section: "Test Plan", Total: 1, Done: 1, Percent: 100},
+	}
+	if got := feat.Checklists(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	total := feat.ChecklistTotal()
+	if total != (Checklist{Total: 6, Done: 3, Percent: 50}) || total.String() != "3/6 (50%)" {
+		t.Fatalf("unexpected total %+v", total)
+	}
+
+	empty := &Feature{Body: "## Summary\nNothing\n"}
+	if len(empty.Tasks()) != 0 || len(empty.Checklists()) != 0 || empty.ChecklistTotal().Total != 0 {
+		t.Fatalf("expected no tasks")
+	}
+}
+
+func TestUncheckedIn(t *testing.T) {
+	feat := &Feature{Body: checklistBody}
+	gated := feat.UncheckedIn([]string{" acceptance criteria "})
+	if len(gated) != 2 || gated[0].Text != "Resets password" {
+		t.Fatalf("unexpected unchecked items %+v", gated)
+	}
+	if got := DescribeUnchecked(gated); got != "2 unchecked item(s) in Acceptance Criteria" {
+		t.Fatalf("unexpected description %q", got)
+	}
+	all := feat.UncheckedIn(nil)
+	if got := DescribeUnchecked(all); got != "1 unchecked item(s) in the introduction, 2 unchecked item(s) in Acceptance Criteria" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
@@ -13,6 +13,13 @@ var (
 	ErrInvalidTransition = errors.New("invalid status transition")
 	// ErrDependencyBlocked indicates dependencies are incomplete.
 	ErrDependencyBlocked = errors.New("dependency not satisfied")
+	// ErrRuleViolation indicates the feature does not meet the target status's
+	// rules. It wraps ErrInvalidTransition, so callers treat it as one.
+	ErrRuleViolation = fmt.Errorf("%w: status rules not met", ErrInvalidTransition)
+	// ErrNotRevertible indicates an audited operation cannot be reverted.
+	ErrNotRevertible = errors.New("cannot revert")
+	// ErrRevertConflict indicates the feature changed after the operation being reverted.
+	ErrRevertConflict = errors.New("feature changed since")
 )
 
 // InvalidFileError represents one or more markdown files that failed to parse as feature specs.
@@ -0,0 +1,395 @@
+package feature
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent matches the indentation yaml.Marshal has always produced for feature files.
+const defaultIndent = 4
+
+// frontMatterSource keeps the parsed YAML document so rewrites can preserve
+// unknown keys, comments, key order and quoting.
+type frontMatterSource struct {
+	doc      *yaml.Node
+	raw      []byte
+	original FrontMatter
+	indent   int
+}
+
+// fieldValue is a single known frontmatter key and its value.
+type fieldValue struct {
+	key       string
+	scalar    string
+	list      []string
+	isList    bool
+	omitEmpty bool
+}
+
+func (v fieldValue) empty() bool {
+	if v.isList {
+		return len(v.list) == 0
+	}
+	return v.scalar == ""
+}
+
+func (v fieldValue) equal(other fieldValue) bool {
+	if v.isList {
+		if len(v.list) != len(other.list) {
+			return false
+		}
+		for i := range v.list {
+			if v.list[i] != other.list[i] {
+				return false
+			}
+		}
+		return true
+	}
+	return v.scalar == other.scalar
+}
+
+// fieldValues lists the known keys in FrontMatter declaration order.
+func fieldValues(fm FrontMatter) []fieldValue {
+	return []fieldValue{
+		{key: "id", scalar: fm.ID},
+		{key: "title", scalar: fm.Title},
+		{key: "status", scalar: fm.Status},
+		{key: "owner", scalar: fm.Owner},
+		{key: "priority", scalar: fm.Priority},
+		{key: "complexity", scalar: fm.Complexity},
+		{key: "created", scalar: fm.Created},
+		{key: "updated", scalar: fm.Updated},
+		{key: "labels", list: fm.Labels, isList: true},
+		{key: "dependencies", list: fm.Dependencies, isList: true},
+		{key: "epic", scalar: fm.Epic, omitEmpty: true},
+		{key: "risk_notes", scalar: fm.RiskNotes, omitEmpty: true},
+	}
+}
+
+// IsBuiltinField reports whether name is one of the fixed FrontMatter keys.
+func IsBuiltinField(name string) bool {
+	for _, field := range fieldValues(FrontMatter{}) {
+		if field.key == name {
+			return true
+		}
+	}
+	return false
+}
+
+func frontMatterEqual(a, b FrontMatter) bool {
+	av, bv := fieldValues(a), fieldValues(b)
+	for i := range av {
+		if !av[i].equal(bv[i]) {
+			return false
+		}
+	}
+	if len(a.Custom) != len(b.Custom) {
+		return false
+	}
+	for key, value := range a.Custom {
+		other, ok := b.Custom[key]
+		if !ok || !reflect.DeepEqual(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneFrontMatter(fm FrontMatter) FrontMatter {
+	clone := fm
+	if fm.Labels != nil {
+		clone.Labels = append([]string{}, fm.Labels...)
+	}
+	if fm.Dependencies != nil {
+		clone.Dependencies = append([]string{}, fm.Dependencies...)
+	}
+	if fm.Custom != nil {
+		clone.Custom = make(map[string]interface{}, len(fm.Custom))
+		for key, value := range fm.Custom {
+			if list, ok := value.([]string); ok {
+				value = append([]string{}, list...)
+			}
+			clone.Custom[key] = value
+		}
+	}
+	return clone
+}
+
+// customValues collects the non-built-in keys of a frontmatter mapping. Scalars become
+// strings, ints, floats or bools and sequences of scalars become string lists; nested
+// structures are left out and are only ever written back untouched.
+func customValues(mapping *yaml.Node) map[string]interface{} {
+	var values map[string]interface{}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, node := mapping.Content[i].Value, mapping.Content[i+1]
+		if IsBuiltinField(key) {
+			continue
+		}
+		value, ok := customValue(node)
+		if !ok {
+			continue
+		}
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+		values[key] = value
+	}
+	return values
+}
+
+func customValue(node *yaml.Node) (interface{}, bool) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return nil, false
+		case "!!int":
+			var n int
+			if err := node.Decode(&n); err == nil {
+				return n, true
+			}
+		case "!!float":
+			var f float64
+			if err := node.Decode(&f); err == nil {
+				return f, true
+			}
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err == nil {
+				return b, true
+			}
+		}
+		return node.Value, true
+	case yaml.SequenceNode:
+		list := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, false
+			}
+			list = append(list, item.Value)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// newFrontMatterSource parses raw frontmatter into a document node; it returns nil
+// when the frontmatter is not a mapping, in which case encoding falls back to yaml.Marshal.
+func newFrontMatterSource(raw []byte, fm FrontMatter) (*frontMatterSource, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	return &frontMatterSource{
+		doc:      &doc,
+		raw:      append([]byte{}, raw...),
+		original: cloneFrontMatter(fm),
+		indent:   detectIndent(raw),
+	}, nil
+}
+
+// detectIndent returns the indentation of the first indented line, or defaultIndent.
+func detectIndent(raw []byte) int {
+	for _, line := range strings.Split(string(raw), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if indent < 2 {
+			return 2
+		}
+		if indent > 9 {
+			return 9
+		}
+		return indent
+	}
+	return defaultIndent
+}
+
+// encode renders fm, emitting the original text untouched when nothing changed and
+// otherwise updating only the keys whose values differ.
+func (s *frontMatterSource) encode(fm FrontMatter) ([]byte, error) {
+	if frontMatterEqual(s.original, fm) {
+		return s.raw, nil
+	}
+
+	mapping := s.doc.Content[0]
+	current := fieldValues(fm)
+	previous := fieldValues(s.original)
+	for i, field := range current {
+		if field.equal(previous[i]) {
+			continue
+		}
+		idx := findKey(mapping, field.key)
+		if field.empty() && field.omitEmpty {
+			if idx >= 0 {
+				mapping.Content = append(mapping.Content[:idx], mapping.Content[idx+2:]...)
+			}
+			continue
+		}
+		if idx < 0 {
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.key}
+			mapping.Content = append(mapping.Content, key, newValueNode(nil, field))
+			continue
+		}
+		mapping.Content[idx+1] = newValueNode(mapping.Content[idx+1], field)
+	}
+	s.syncCustom(mapping, fm.Custom)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(s.indent)
+	if err := enc.Encode(s.doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	s.raw = bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	s.original = cloneFrontMatter(fm)
+	return s.raw, nil
+}
+
+// syncCustom applies added, changed and removed custom fields to the mapping.
+func (s *frontMatterSource) syncCustom(mapping *yaml.Node, custom map[string]interface{}) {
+	keys := make([]string, 0, len(custom)+len(s.original.Custom))
+	for key := range s.original.Custom {
+		keys = append(keys, key)
+	}
+	for key := range custom {
+		if _, seen := s.original.Custom[key]; !seen {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := custom[key]
+		previous, hadPrevious := s.original.Custom[key]
+		if ok && hadPrevious && reflect.DeepEqual(value, previous) {
+			continue
+		}
+		idx := findKey(mapping, key)
+		if !ok {
+			if idx >= 0 {
+				mapping.Content = append(mapping.Content[:idx], mapping.Content[idx+2:]...)
+			}
+			continue
+		}
+		if idx < 0 {
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			mapping.Content = append(mapping.Content, keyNode, newCustomNode(nil, value))
+			continue
+		}
+		mapping.Content[idx+1] = newCustomNode(mapping.Content[idx+1], value)
+	}
+}
+
+// newCustomNode builds the node for a custom field value.
+func newCustomNode(prev *yaml.Node, value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case string:
+		return newScalarNode(prev, v)
+	case []string:
+		return newValueNode(prev, fieldValue{list: v, isList: true})
+	}
+	node := &yaml.Node{}
+	_ = node.Encode(value) // remaining custom values are ints, floats or bools
+	if prev != nil {
+		node.LineComment = prev.LineComment
+		node.HeadComment = prev.HeadComment
+		node.FootComment = prev.FootComment
+	}
+	return node
+}
+
+func findKey(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// newValueNode builds the node for field, reusing the previous node's style and comments.
+func newValueNode(prev *yaml.Node, field fieldValue) *yaml.Node {
+	if field.isList {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		var prevItems []*yaml.Node
+		if prev != nil && prev.Kind == yaml.SequenceNode {
+			seq.Style = prev.Style
+			seq.LineComment = prev.LineComment
+			seq.HeadComment = prev.HeadComment
+			seq.FootComment = prev.FootComment
+			prevItems = prev.Content
+		}
+		for _, item := range field.list {
+			var reuse *yaml.Node
+			for _, p := range prevItems {
+				if p.Kind == yaml.ScalarNode && p.Value == item {
+					reuse = p
+					break
+				}
+			}
+			seq.Content = append(seq.Content, newScalarNode(reuse, item))
+		}
+		return seq
+	}
+	return newScalarNode(prev, field.scalar)
+}
+
+// newScalarNode returns a string scalar, keeping the previous quoting style and, for
+// plain values, the previous tag when the new value still resolves to it (e.g. dates).
+func newScalarNode(prev *yaml.Node, value string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if prev == nil || prev.Kind != yaml.ScalarNode {
+		return node
+	}
+	node.LineComment = prev.LineComment
+	node.HeadComment = prev.HeadComment
+	node.FootComment = prev.FootComment
+	if prev.Style != 0 {
+		node.Style = prev.Style
+		return node
+	}
+	if prev.Tag != "!!str" && plainTag(value) == prev.Tag {
+		node.Tag = prev.Tag
+	}
+	return node
+}
+
+// plainTag reports the tag YAML would resolve for value written as a plain scalar.
+func plainTag(value string) string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil || len(doc.Content) != 1 {
+		return ""
+	}
+	node := doc.Content[0]
+	if node.Kind != yaml.ScalarNode || node.Style != 0 || node.Value != value {
+		return ""
+	}
+	return node.Tag
+}
+
+// marshalFrontMatter encodes features that were not read from disk, appending custom fields.
+func marshalFrontMatter(fm FrontMatter) ([]byte, error) {
+	data, err := yaml.Marshal(fm)
+	if err != nil || len(fm.Custom) == 0 {
+		return data, err
+	}
+	custom, err := yaml.Marshal(fm.Custom)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, custom...), nil
+}
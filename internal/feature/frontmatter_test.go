@@ -0,0 +1,224 @@
+package feature
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const roundTripSpec = `---
+# Owned by the payments team
+id: FTR-0042
+title: "Checkout: saved cards"
+status: backlog
+owner: alice
+priority: high
+complexity: M
+created: 2026-01-05
+updated: 2026-01-05 # bumped by vb
+estimate: 3d
+jira: PAY-118
+labels: [payments, 'ux']
+dependencies:
+  - FTR-0001
+epic: checkout
+---
+## Summary
+
+Body text.
+`
+
+func TestEncodeUnchangedIsByteIdentical(t *testing.T) {
+	feat, err := Parse("spec.md", []byte(roundTripSpec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	out, err := feat.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if string(out) != roundTripSpec {
+		t.Fatalf("expected byte-identical output, got:\n%s", out)
+	}
+}
+
+func TestEncodePreservesUnknownKeysCommentsAndStyle(t *testing.T) {
+	feat, err := Parse("spec.md", []byte(roundTripSpec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	feat.FrontMatter.Status = "in-progress"
+	feat.FrontMatter.Updated = "2026-02-10"
+	feat.FrontMatter.Labels = append(feat.FrontMatter.Labels, "mobile")
+	feat.FrontMatter.Epic = ""
+	feat.FrontMatter.RiskNotes = "PCI scope"
+
+	out, err := feat.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	text := string(out)
+	for _, want := range []string{
+		"# Owned by the payments team\nid: FTR-0042\n",
+		"title: \"Checkout: saved cards\"\n",
+		"status: in-progress\n",
+		"created: 2026-01-05\n",
+		"updated: 2026-02-10 # bumped by vb\n",
+		"estimate: 3d\njira: PAY-118\n",
+		"labels: [payments, 'ux', mobile]\n",
+		"dependencies:\n  - FTR-0001\n",
+		"risk_notes: PCI scope\n---\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "epic:") {
+		t.Fatalf("expected emptied epic to be removed:\n%s", text)
+	}
+
+	again, err := feat.Encode()
+	if err != nil || string(again) != text {
+		t.Fatalf("second encode should be stable: %v\n%s", err, again)
+	}
+
+	reparsed, err := Parse("spec.md", out)
+	if err != nil {
+		t.Fatalf("reparse failed: %v", err)
+	}
+	if reparsed.FrontMatter.Status != "in-progress" || len(reparsed.FrontMatter.Labels) != 3 {
+		t.Fatalf("unexpected reparsed frontmatter: %+v", reparsed.FrontMatter)
+	}
+}
+
+func TestEncodeQuotesValuesThatChangeType(t *testing.T) {
+	feat, err := Parse("spec.md", []byte(roundTripSpec))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	feat.FrontMatter.Updated = "soon"
+	feat.FrontMatter.Owner = "true"
+	out, err := feat.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if !strings.Contains(string(out), "updated: soon # bumped by vb\n") || !strings.Contains(string(out), "owner: \"true\"\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestEncodeDefaultsAndDetectedIndent(t *testing.T) {
+	feat, err := Parse("spec.md", []byte("---\nid: FTR-0001\nlabels:\n    - a\n---\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	feat.FrontMatter.Dependencies = []string{"FTR-0002"}
+	out, err := feat.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if !strings.Contains(string(out), "labels:\n    - a\ndependencies:\n    - FTR-0002\n") {
+		t.Fatalf("expected detected 4-space indent:\n%s", out)
+	}
+
+	if got := detectIndent([]byte("id: x\n # comment\n labels:\n")); got != 2 {
+		t.Fatalf("expected indent clamped to 2, got %d", got)
+	}
+	if got := detectIndent([]byte("id: x\nlabels:\n            - a\n")); got != 9 {
+		t.Fatalf("expected indent clamped to 9, got %d", got)
+	}
+	if got := detectIndent([]byte("id: x\n")); got != defaultIndent {
+		t.Fatalf("expected default indent, got %d", got)
+	}
+}
+
+func TestParseNonMappingFrontMatter(t *testing.T) {
+	feat, err := Parse("spec.md", []byte("---\n# only a comment\n---\nbody\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if feat.source != nil {
+		t.Fatalf("expected no source for empty frontmatter")
+	}
+	feat.FrontMatter.ID = "FTR-0001"
+	out, err := feat.Encode()
+	if err != nil || !strings.Contains(string(out), "id: FTR-0001") {
+		t.Fatalf("expected marshal fallback: %v\n%s", err, out)
+	}
+}
+
+func TestPlainTag(t *testing.T) {
+	if plainTag("2026-01-01") != "!!timestamp" || plainTag("12") != "!!int" {
+		t.Fatalf("unexpected plain tags")
+	}
+	if plainTag("a: b") != "" || plainTag("[x") != "" || plainTag("'q'") != "" {
+		t.Fatalf("expected no tag for values that are not plain scalars")
+	}
+}
+
+func TestCustomFieldsRoundTrip(t *testing.T) {
+	src := "---\nid: FTR-0001\ndue: 2026-04-01 # hard deadline\nestimate: 3\ncost: 1.5\nbillable: true\ncustomers: [acme]\nmeta:\n  source: import\nempty:\n---\n"
+	feat, err := Parse("spec.md", []byte(src))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	custom := feat.FrontMatter.Custom
+	if custom["due"] != "2026-04-01" || custom["estimate"] != 3 || custom["cost"] != 1.5 || custom["billable"] != true {
+		t.Fatalf("unexpected custom scalars: %#v", custom)
+	}
+	if list, ok := custom["customers"].([]string); !ok || len(list) != 1 || list[0] != "acme" {
+		t.Fatalf("unexpected custom list: %#v", custom["customers"])
+	}
+	if _, ok := custom["meta"]; ok {
+		t.Fatalf("nested values should not be exposed")
+	}
+	if _, ok := custom["empty"]; ok {
+		t.Fatalf("null values should not be exposed")
+	}
+
+	custom["due"] = "2026-05-01"
+	custom["estimate"] = 5
+	custom["customers"] = []string{"acme", "globex"}
+	custom["team"] = "core"
+	delete(custom, "billable")
+	out, err := feat.Encode()
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	text := string(out)
+	for _, want := range []string{"due: 2026-05-01 # hard deadline\n", "estimate: 5\n", "cost: 1.5\n", "customers: [acme, globex]\n", "meta:\n  source: import\n", "team: core\n"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "billable") {
+		t.Fatalf("expected removed custom field to be dropped:\n%s", text)
+	}
+}
+
+func TestMarshalFrontMatterIncludesCustom(t *testing.T) {
+	feat := &Feature{FrontMatter: FrontMatter{ID: "FTR-0001", Custom: map[string]interface{}{"estimate": 2}}}
+	out, err := feat.Encode()
+	if err != nil || !strings.Contains(string(out), "estimate: 2\n") {
+		t.Fatalf("expected custom field in fallback encoding: %v\n%s", err, out)
+	}
+
+	if got := newCustomNode(nil, true); got.Kind != yaml.ScalarNode || got.Tag != "!!bool" {
+		t.Fatalf("unexpected node for bool value: %+v", got)
+	}
+}
+
+func TestFrontMatterMap(t *testing.T) {
+	fm := FrontMatter{ID: "FTR-0001", Custom: map[string]interface{}{"estimate": 2}}
+	doc := fm.Map()
+	if doc["id"] != "FTR-0001" || doc["estimate"] != 2 {
+		t.Fatalf("unexpected map: %#v", doc)
+	}
+	if _, ok := doc["custom"]; ok {
+		t.Fatalf("custom fields should be flattened")
+	}
+	if !IsBuiltinField("risk_notes") || IsBuiltinField("estimate") {
+		t.Fatalf("unexpected builtin field detection")
+	}
+}
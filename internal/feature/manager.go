@@ -16,8 +16,11 @@ import (
 
 	"github.com/virtualboard/vb-cli/internal/audit"
 	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/fields"
 	"github.com/virtualboard/vb-cli/internal/lock"
+	"github.com/virtualboard/vb-cli/internal/patch"
 	"github.com/virtualboard/vb-cli/internal/util"
+	"github.com/virtualboard/vb-cli/internal/workflow"
 )
 
 var idPattern = regexp.MustCompile(`FTR-(\d{4})`)
@@ -28,24 +31,37 @@ type Manager struct {
 	log      *logrus.Entry
 	lockMgr  *lock.Manager
 	auditLog *audit.Logger
+	// auditErr records why the audit log is unavailable; changes are refused
+	// while it is set.
+	auditErr error
+
+	customFields fields.Set
+	// planned collects the changes skipped in dry-run mode.
+	planned patch.Recorder
 }
 
 // NewManager constructs a manager with shared configuration.
 func NewManager(opts *config.Options) *Manager {
-	auditPath := filepath.Join(opts.RootDir, "audit.jsonl")
-	auditLog, _ := audit.NewLogger(auditPath) // best-effort; nil on error
+	auditLog, auditErr := opts.AuditLogger()
 	return &Manager{
 		opts:     opts,
 		log:      opts.Logger().WithField("component", "feature"),
 		lockMgr:  lock.NewManager(opts),
 		auditLog: auditLog,
+		auditErr: auditErr,
 	}
 }
 
-// auditEvent records a mutating operation to the audit log. Best-effort only.
-func (m *Manager) auditEvent(action, featureID, details string) {
-	if m.auditLog != nil {
-		_ = m.auditLog.Log(action, currentUser(), featureID, details)
+// Patches returns the file changes planned so far in dry-run mode, in order.
+func (m *Manager) Patches() []patch.Patch {
+	return m.planned.Patches()
+}
+
+// auditEvent records a mutating operation and its change set to the audit log.
+// Best-effort only; nothing is recorded in dry-run mode.
+func (m *Manager) auditEvent(action, featureID, details string, changes *audit.Changes) {
+	if m.auditLog != nil && !m.opts.DryRun {
+		_ = m.auditLog.LogChanges(action, currentUser(), featureID, details, changes)
 	}
 }
 
@@ -60,19 +76,46 @@ func currentUser() string {
 // withLock acquires a short-lived operational lock, runs fn, then releases.
 // The lock uses a 1-minute TTL for automatic expiry if the process crashes.
 func (m *Manager) withLock(lockID string, fn func() error) error {
-	if m.lockMgr == nil || m.opts.DryRun {
-		return fn()
-	}
-	_, err := m.lockMgr.Acquire(lockID, "vb-cli-op", 1, false)
+	release, err := m.acquireOpLock(lockID)
 	if err != nil {
-		return fmt.Errorf("failed to acquire operational lock %s: %w", lockID, err)
+		return err
 	}
-	defer func() {
-		_ = m.lockMgr.Release(lockID)
-	}()
+	defer release()
 	return fn()
 }
 
+// featureLockID returns the operational lock key guarding changes to one feature.
+// Every operation that changes an existing feature takes it, so moves, updates,
+// deletes and reverts of the same feature exclude each other. The ID is
+// upper-cased so differently cased spellings share the lock.
+func featureLockID(id string) string {
+	return "op-feature-" + Key(id)
+}
+
+// acquireOpLock takes the operational lock and returns the function releasing it.
+func (m *Manager) acquireOpLock(lockID string) (func(), error) {
+	if m.opts.DryRun {
+		return func() {}, nil
+	}
+	if m.auditErr != nil {
+		return nil, m.auditErr
+	}
+	if m.lockMgr == nil {
+		return func() {}, nil
+	}
+	if _, err := m.lockMgr.Acquire(lockID, "vb-cli-op", 1, false); err != nil {
+		return nil, fmt.Errorf("failed to acquire operational lock %s: %w", lockID, err)
+	}
+	return func() {
+		_ = m.lockMgr.Release(lockID)
+	}, nil
+}
+
+// Workflow returns the workspace workflow governing statuses and transitions.
+func (m *Manager) Workflow() *workflow.Workflow {
+	return m.opts.Workflow()
+}
+
 // FeaturesDir returns the path to the features directory.
 func (m *Manager) FeaturesDir() string {
 	return filepath.Join(m.opts.RootDir, "features")
@@ -88,11 +131,49 @@ func (m *Manager) SchemaPath() string {
 	return filepath.Join(m.opts.RootDir, "schemas", "frontmatter.schema.json")
 }
 
+// CustomFields returns the workspace's custom frontmatter fields: schema properties that
+// are not built in, overridden by the fields declared in config.yaml.
+func (m *Manager) CustomFields() (fields.Set, error) {
+	if m.customFields != nil {
+		return m.customFields, nil
+	}
+	set := fields.Set{}
+	// #nosec G304 -- schema path is derived from the validated workspace root
+	data, err := os.ReadFile(m.SchemaPath())
+	if err == nil {
+		set, err = fields.FromSchema(data, IsBuiltinField)
+		if err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	for name := range m.opts.Settings.Fields {
+		if IsBuiltinField(name) {
+			return nil, fmt.Errorf("field %s is built in and cannot be redeclared", name)
+		}
+	}
+	m.customFields = set.Merge(m.opts.Settings.Fields)
+	return m.customFields, nil
+}
+
+// attachFields lets SetField accept the workspace's custom fields.
+func (m *Manager) attachFields(feat *Feature) {
+	if set, err := m.CustomFields(); err == nil {
+		feat.fields = set
+	}
+}
+
 // LocksDir returns the directory for lock files.
 func (m *Manager) LocksDir() string {
 	return filepath.Join(m.opts.RootDir, "locks")
 }
 
+// AuditPath returns the path to the append-only audit log.
+func (m *Manager) AuditPath() string {
+	return filepath.Join(m.opts.RootDir, "audit.jsonl")
+}
+
 // NextID calculates the next available feature ID (e.g., FTR-0005).
 func (m *Manager) NextID() (string, error) {
 	featuresDir := m.FeaturesDir()
@@ -147,6 +228,7 @@ func (m *Manager) LoadByID(id string) (*Feature, error) {
 	if err != nil {
 		return nil, err
 	}
+	m.attachFields(feat)
 	return feat, nil
 }
 
@@ -200,7 +282,9 @@ func (m *Manager) Save(feat *Feature) error {
 			"path":   feat.Path,
 			"dryRun": true,
 		}).Info("Skipping write in dry-run mode")
-		return nil
+		p, err := patch.Write(filepath.Dir(m.opts.RootDir), feat.Path, feat.Path, data)
+		m.planned.Add(p)
+		return err
 	}
 	return util.WriteFileAtomic(feat.Path, data, 0o644)
 }
@@ -220,9 +304,10 @@ func (m *Manager) CreateFeature(title string, labels []string) (*Feature, error)
 			return fmt.Errorf("failed to compute next feature ID: %w", idErr)
 		}
 
+		wf := m.Workflow()
 		slug := util.Slugify(title)
 		filename := fmt.Sprintf("%s-%s.md", nextID, slug)
-		path := filepath.Join(m.FeaturesDir(), "backlog", filename)
+		path := filepath.Join(m.opts.RootDir, wf.DirectoryForStatus(wf.Initial), filename)
 
 		today := time.Now().Format("2006-01-02")
 
@@ -234,8 +319,11 @@ func (m *Manager) CreateFeature(title string, labels []string) (*Feature, error)
 		parsed.Path = path
 		parsed.FrontMatter.ID = nextID
 		parsed.FrontMatter.Title = title
-		parsed.FrontMatter.Status = "backlog"
+		parsed.FrontMatter.Status = wf.Initial
 		parsed.FrontMatter.Owner = "unassigned"
+		if m.opts.Settings.Owner != "" {
+			parsed.FrontMatter.Owner = m.opts.Settings.Owner
+		}
 		parsed.FrontMatter.Created = today
 		parsed.FrontMatter.Updated = today
 		parsed.FrontMatter.Labels = normalizeList(labels)
@@ -256,148 +344,180 @@ func (m *Manager) CreateFeature(title string, labels []string) (*Feature, error)
 		return nil
 	})
 	if err == nil && feat != nil {
-		m.auditEvent("create", feat.FrontMatter.ID, fmt.Sprintf("title=%s", feat.FrontMatter.Title))
+		m.auditEvent("create", feat.FrontMatter.ID, fmt.Sprintf("title=%s", feat.FrontMatter.Title), m.changeSet(nil, feat))
 	}
 	return feat, err
 }
 
-// UpdateFeature persists changes to an existing feature.
+// UpdateFeature persists changes to an existing feature and audits the fields and
+// sections that differ from the file on disk. The previous file is stashed in the
+// trash so the update can be reverted. Uses the feature's operational lock.
 func (m *Manager) UpdateFeature(feat *Feature) error {
-	feat.UpdateTimestamp()
-	return m.Save(feat)
+	var changes *audit.Changes
+	err := m.withLock(featureLockID(feat.FrontMatter.ID), func() error {
+		before := m.loadState(feat.Path)
+		snapshot, err := m.stash(feat.Path)
+		if err != nil {
+			return err
+		}
+		feat.UpdateTimestamp()
+		if err := m.Save(feat); err != nil {
+			return err
+		}
+		changes = m.changeSet(before, feat)
+		changes.Snapshot = snapshot
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.auditEvent("update", feat.FrontMatter.ID, changes.String(), changes)
+	return nil
 }
 
 // MoveFeature updates status and moves file accordingly.
-// Uses a per-feature operational lock to prevent concurrent move races.
+// Uses the feature's operational lock to prevent concurrent changes.
 func (m *Manager) MoveFeature(id, newStatus, owner string) (*Feature, string, error) {
 	var feat *Feature
-	var summary string
-	err := m.withLock(fmt.Sprintf("op-move-%s", id), func() error {
+	var before *featureState
+	var snapshot, summary string
+	err := m.withLock(featureLockID(id), func() error {
 		var loadErr error
 		feat, loadErr = m.LoadByID(id)
 		if loadErr != nil {
 			return loadErr
 		}
-
-		currentStatus := strings.ToLower(feat.FrontMatter.Status)
-		if transErr := ValidateTransition(currentStatus, newStatus); transErr != nil {
-			return fmt.Errorf("%w: %v", ErrInvalidTransition, transErr)
-		}
-
-		if depErr := m.verifyDependenciesForMove(feat, newStatus); depErr != nil {
-			return depErr
-		}
-
-		newStatus = strings.ToLower(newStatus)
-		feat.FrontMatter.Status = newStatus
-		if owner != "" {
-			feat.FrontMatter.Owner = owner
-		} else if feat.FrontMatter.Owner == "" {
-			feat.FrontMatter.Owner = "unassigned"
-		}
-		feat.UpdateTimestamp()
-
-		newDir := filepath.Join(m.opts.RootDir, DirectoryForStatus(newStatus))
-		if newDir == "" {
-			return fmt.Errorf("unknown status directory for %s", newStatus)
+		before = captureState(feat)
+
+		// The new file and the removal of the old one are applied together so a
+		// failure never leaves the feature duplicated or missing.
+		tx := m.Begin()
+		from, stashed, stageErr := m.stageMove(tx, feat, newStatus, owner)
+		if stageErr != nil {
+			return stageErr
 		}
-
-		oldPath := feat.Path
-		needsMove := !strings.EqualFold(newStatus, currentStatus) || filepath.Dir(feat.Path) != newDir
-
-		if needsMove {
-			if !m.opts.DryRun {
-				if mkErr := os.MkdirAll(newDir, 0o750); mkErr != nil {
-					return fmt.Errorf("failed to create status directory: %w", mkErr)
-				}
-			}
-			newPath := filepath.Join(newDir, filepath.Base(feat.Path))
-			feat.Path = newPath
-
-			if saveErr := m.Save(feat); saveErr != nil {
-				return fmt.Errorf("failed to write feature to new location: %w", saveErr)
-			}
-
-			if oldPath != newPath && !m.opts.DryRun {
-				if rmErr := os.Remove(oldPath); rmErr != nil {
-					m.log.WithField("path", oldPath).Warn("Failed to remove old feature file")
-				}
-			}
-		} else {
-			if saveErr := m.Save(feat); saveErr != nil {
-				return saveErr
-			}
+		snapshot = stashed
+		if commitErr := tx.Commit(); commitErr != nil {
+			return commitErr
 		}
 
-		summary = fmt.Sprintf("Moved %s to %s", feat.FrontMatter.ID, newStatus)
-		m.log.WithFields(logrus.Fields{
-			"action":   "move",
-			"id":       feat.FrontMatter.ID,
-			"from":     currentStatus,
-			"to":       newStatus,
-			"owner":    feat.FrontMatter.Owner,
-			"new_path": feat.Path,
-		}).Info("Feature moved")
-
+		summary = fmt.Sprintf("Moved %s to %s", feat.FrontMatter.ID, feat.FrontMatter.Status)
+		m.logMove(feat, from)
 		return nil
 	})
 	if err != nil {
 		return nil, "", err
 	}
-	m.auditEvent("move", feat.FrontMatter.ID, fmt.Sprintf("status=%s", feat.FrontMatter.Status))
+	changes := m.changeSet(before, feat)
+	changes.Snapshot = snapshot
+	m.auditEvent("move", feat.FrontMatter.ID, fmt.Sprintf("status=%s", feat.FrontMatter.Status), changes)
 	return feat, summary, nil
 }
 
-// RenameToMatchTitle renames a feature file to match its current title.
-// Returns true if the file was renamed, false if it already matched.
-func (m *Manager) RenameToMatchTitle(feat *Feature) (bool, error) {
-	expectedName := fmt.Sprintf("%s-%s.md", feat.FrontMatter.ID, util.Slugify(feat.FrontMatter.Title))
-	currentName := filepath.Base(feat.Path)
-
-	if strings.EqualFold(currentName, expectedName) {
-		return false, nil
+// stageMove checks the transition and dependencies, stashes the current file, then
+// updates the feature and stages its write into the status directory. A rejected
+// move leaves no snapshot, and nothing is staged without one. It returns the
+// previous status and the snapshot hash.
+func (m *Manager) stageMove(tx *Transaction, feat *Feature, newStatus, owner string) (string, string, error) {
+	currentStatus := strings.ToLower(feat.FrontMatter.Status)
+	wf := m.Workflow()
+	if err := wf.ValidateTransition(currentStatus, newStatus); err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidTransition, err)
 	}
 
-	oldPath := feat.Path
-	newPath := filepath.Join(filepath.Dir(feat.Path), expectedName)
+	if err := m.verifyDependenciesForMove(feat, newStatus); err != nil {
+		return "", "", err
+	}
+	probe := *feat
+	if owner != "" {
+		probe.FrontMatter.Owner = owner
+	}
+	if violations := m.RuleViolations(&probe, newStatus); len(violations) > 0 {
+		messages := make([]string, 0, len(violations))
+		for _, v := range violations {
+			messages = append(messages, v.Message)
+		}
+		return "", "", fmt.Errorf("%w: %s", ErrRuleViolation, strings.Join(messages, "; "))
+	}
+	if wf.IsDone(newStatus) {
+		if unchecked := m.UncheckedGated(feat); len(unchecked) > 0 {
+			return "", "", fmt.Errorf("%w: %s must be checked before moving to %s", ErrInvalidTransition, DescribeUnchecked(unchecked), wf.Done)
+		}
+	}
+	snapshot, err := m.stash(feat.Path)
+	if err != nil {
+		return "", "", err
+	}
 
-	if m.opts.DryRun {
-		m.log.WithFields(logrus.Fields{
-			"action": "rename",
-			"old":    currentName,
-			"new":    expectedName,
-			"dryRun": true,
-		}).Info("Skipping rename in dry-run mode")
-		feat.Path = newPath
-		return true, nil
+	newStatus = strings.ToLower(newStatus)
+	feat.FrontMatter.Status = newStatus
+	if owner != "" {
+		feat.FrontMatter.Owner = owner
+	} else if feat.FrontMatter.Owner == "" {
+		feat.FrontMatter.Owner = "unassigned"
 	}
+	feat.UpdateTimestamp()
 
-	// Update the feature's path before saving
-	feat.Path = newPath
+	newDir := filepath.Join(m.opts.RootDir, wf.DirectoryForStatus(newStatus))
+	needsMove := !strings.EqualFold(newStatus, currentStatus) || filepath.Dir(feat.Path) != newDir
 
-	// Write the feature to the new path atomically
-	if err := m.Save(feat); err != nil {
-		return false, fmt.Errorf("failed to write feature to new path: %w", err)
+	if needsMove {
+		if err := tx.Move(feat, filepath.Join(newDir, filepath.Base(feat.Path))); err != nil {
+			return "", "", fmt.Errorf("failed to write feature to new location: %w", err)
+		}
+	} else if err := tx.Save(feat); err != nil {
+		return "", "", err
 	}
+	return currentStatus, snapshot, nil
+}
 
-	// Remove the old file only after the new one is successfully written
-	if err := os.Remove(oldPath); err != nil {
-		// Log but don't fail - the new file is already written
-		m.log.WithField("path", oldPath).Warn("Failed to remove old feature file")
+func (m *Manager) logMove(feat *Feature, from string) {
+	m.log.WithFields(logrus.Fields{
+		"action":   "move",
+		"id":       feat.FrontMatter.ID,
+		"from":     from,
+		"to":       feat.FrontMatter.Status,
+		"owner":    feat.FrontMatter.Owner,
+		"new_path": feat.Path,
+	}).Info("Feature moved")
+}
+
+// RenameToMatchTitle renames a feature file to match its current title.
+// Returns true if the file was renamed, false if it already matched.
+func (m *Manager) RenameToMatchTitle(feat *Feature) (bool, error) {
+	oldName := filepath.Base(feat.Path)
+	tx := m.Begin()
+	renamed, err := tx.RenameToMatchTitle(feat)
+	if err != nil || !renamed {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to rename feature file: %w", err)
 	}
 
 	m.log.WithFields(logrus.Fields{
 		"action": "rename",
 		"id":     feat.FrontMatter.ID,
-		"old":    currentName,
-		"new":    expectedName,
+		"old":    oldName,
+		"new":    filepath.Base(feat.Path),
+		"dryRun": m.opts.DryRun,
 	}).Info("Feature file renamed")
 
 	return true, nil
 }
 
+// UncheckedGated returns the unchecked task items that keep the feature from
+// being done under the checklist settings; nil when the gate is disabled.
+func (m *Manager) UncheckedGated(feat *Feature) []Task {
+	if !m.opts.Settings.Checklist.BlockDone {
+		return nil
+	}
+	return feat.UncheckedIn(m.opts.Settings.Checklist.Sections)
+}
+
 func (m *Manager) verifyDependenciesForMove(feat *Feature, target string) error {
-	if strings.ToLower(target) != "in-progress" {
+	wf := m.Workflow()
+	if !wf.RequiresDoneDependencies(target) {
 		return nil
 	}
 	for _, dep := range feat.FrontMatter.Dependencies {
@@ -409,39 +529,60 @@ func (m *Manager) verifyDependenciesForMove(feat *Feature, target string) error
 		if err != nil {
 			return fmt.Errorf("%w: dependency %s missing: %v", ErrDependencyBlocked, dep, err)
 		}
-		if strings.ToLower(depFeature.FrontMatter.Status) != "done" {
-			return fmt.Errorf("%w: dependency %s is not done (status: %s)", ErrDependencyBlocked, dep, depFeature.FrontMatter.Status)
+		if !wf.IsDone(depFeature.FrontMatter.Status) {
+			return fmt.Errorf("%w: dependency %s is not %s (status: %s)", ErrDependencyBlocked, dep, wf.Done, depFeature.FrontMatter.Status)
 		}
 	}
 	return nil
 }
 
-// DeleteFeature removes the feature file from disk.
+// DeleteFeature removes the feature file from disk after stashing it in the
+// trash, so vb undo and vb revert can recreate it.
+// Uses the feature's operational lock, the one moves, updates and reverts take,
+// so none of them can recreate the file while it is deleted.
 func (m *Manager) DeleteFeature(id string) (string, error) {
-	path, err := m.findByID(id)
-	if err != nil {
-		return "", err
-	}
-	if m.opts.DryRun {
+	var path, snapshot string
+	var before *featureState
+	err := m.withLock(featureLockID(id), func() error {
+		var findErr error
+		path, findErr = m.findByID(id)
+		if findErr != nil {
+			return findErr
+		}
+		before = m.loadState(path)
+		if snapshot, findErr = m.stash(path); findErr != nil {
+			return findErr
+		}
+		if m.opts.DryRun {
+			m.log.WithFields(logrus.Fields{
+				"action": "delete",
+				"path":   path,
+				"dryRun": true,
+			}).Info("Skipping delete in dry-run mode")
+			p, err := patch.Remove(filepath.Dir(m.opts.RootDir), path)
+			m.planned.Add(p)
+			return err
+		}
+		if rmErr := os.Remove(path); rmErr != nil {
+			return fmt.Errorf("failed to delete feature: %w", rmErr)
+		}
 		m.log.WithFields(logrus.Fields{
 			"action": "delete",
 			"path":   path,
-			"dryRun": true,
-		}).Info("Skipping delete in dry-run mode")
-		return path, nil
-	}
-	if err := os.Remove(path); err != nil {
-		return "", fmt.Errorf("failed to delete feature: %w", err)
+		}).Info("Feature deleted")
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
-	m.log.WithFields(logrus.Fields{
-		"action": "delete",
-		"path":   path,
-	}).Info("Feature deleted")
-	m.auditEvent("delete", id, fmt.Sprintf("path=%s", path))
+	changes := m.changeSet(before, nil)
+	changes.Snapshot = snapshot
+	m.auditEvent("delete", id, fmt.Sprintf("path=%s", path), changes)
 	return path, nil
 }
 
-// List returns all features metadata.
+// List returns all features metadata. When some files fail to parse, it returns
+// the features that did with an *InvalidFileError naming the others.
 func (m *Manager) List() ([]*Feature, error) {
 	featuresDir := m.FeaturesDir()
 	if _, statErr := os.Stat(featuresDir); statErr != nil {
@@ -481,6 +622,7 @@ func (m *Manager) List() ([]*Feature, error) {
 			})
 			return nil
 		}
+		m.attachFields(feat)
 		features = append(features, feat)
 		return nil
 	})
@@ -488,14 +630,14 @@ func (m *Manager) List() ([]*Feature, error) {
 		return nil, err
 	}
 
-	// If we found invalid files, return them as an error
-	if len(invalidFiles) > 0 {
-		return nil, &InvalidFileError{Files: invalidFiles}
-	}
-
 	sort.Slice(features, func(i, j int) bool {
 		return features[i].FrontMatter.ID < features[j].FrontMatter.ID
 	})
+	// Invalid files are reported as an error alongside the features that parsed,
+	// so callers that only need some features can tolerate them.
+	if len(invalidFiles) > 0 {
+		return features, &InvalidFileError{Files: invalidFiles}
+	}
 	return features, nil
 }
 
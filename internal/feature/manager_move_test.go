@@ -1,8 +1,10 @@
 package feature
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/virtualboard/vb-cli/internal/testutil"
@@ -38,7 +40,7 @@ func TestMoveFeatureUpdatesStatus(t *testing.T) {
 	}
 
 	// Verify the file is in the correct directory
-	expectedDir := filepath.Join(opts.RootDir, DirectoryForStatus("in-progress"))
+	expectedDir := filepath.Join(opts.RootDir, mgr.Workflow().DirectoryForStatus("in-progress"))
 	actualDir := filepath.Dir(movedFeat.Path)
 	if actualDir != expectedDir {
 		t.Fatalf("expected file in directory %s, got %s", expectedDir, actualDir)
@@ -113,7 +115,7 @@ func TestMoveFeatureMultipleTransitions(t *testing.T) {
 		}
 
 		// Verify file is in correct directory
-		expectedDir := filepath.Join(opts.RootDir, DirectoryForStatus(tr.to))
+		expectedDir := filepath.Join(opts.RootDir, mgr.Workflow().DirectoryForStatus(tr.to))
 		actualDir := filepath.Dir(reloaded.Path)
 		if actualDir != expectedDir {
 			t.Fatalf("after moving to %s, file in %s instead of %s", tr.to, actualDir, expectedDir)
@@ -190,3 +192,124 @@ func findSubstring(haystack, needle string) bool {
 	}
 	return false
 }
+
+func TestCustomWorkflowCreateAndMove(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "workflow.yaml", []byte(`initial: todo
+done: shipped
+statuses:
+  - name: todo
+    transitions: [doing, cancelled]
+  - name: doing
+    transitions: [qa, cancelled]
+    requires_done_dependencies: true
+  - name: qa
+    directory: features/quality
+    transitions: [doing, shipped]
+  - name: shipped
+  - name: cancelled
+`))
+	opts := fix.Options(t, false, false, false)
+	mgr := NewManager(opts)
+
+	dep, err := mgr.CreateFeature("Dependency", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if dep.FrontMatter.Status != "todo" || filepath.Dir(dep.Path) != filepath.Join(opts.RootDir, "features", "todo") {
+		t.Fatalf("expected feature created in todo, got %s at %s", dep.FrontMatter.Status, dep.Path)
+	}
+
+	feat, err := mgr.CreateFeature("Dependent", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	feat.FrontMatter.Dependencies = []string{dep.FrontMatter.ID}
+	if err := mgr.Save(feat); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	if _, _, err := mgr.MoveFeature(feat.FrontMatter.ID, "doing", ""); !errors.Is(err, ErrDependencyBlocked) || !strings.Contains(err.Error(), "is not shipped") {
+		t.Fatalf("expected dependency gate on doing, got %v", err)
+	}
+	if _, _, err := mgr.MoveFeature(feat.FrontMatter.ID, "cancelled", ""); err != nil {
+		t.Fatalf("expected cancel without dependency gate: %v", err)
+	}
+
+	for _, status := range []string{"doing", "qa", "shipped"} {
+		if _, _, err := mgr.MoveFeature(dep.FrontMatter.ID, status, ""); err != nil {
+			t.Fatalf("move to %s failed: %v", status, err)
+		}
+	}
+	moved, err := mgr.LoadByID(dep.FrontMatter.ID)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if filepath.Dir(moved.Path) != filepath.Join(opts.RootDir, "features", "shipped") {
+		t.Fatalf("unexpected path %s", moved.Path)
+	}
+	if _, _, err := mgr.MoveFeature(dep.FrontMatter.ID, "done", ""); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("built-in status should be rejected by custom workflow, got %v", err)
+	}
+}
+
+func TestMoveFeaturePreservesCustomFrontMatter(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	fix.WriteFile(t, "features/backlog/FTR-0007-custom.md", []byte("---\nid: FTR-0007\ntitle: Custom\nstatus: backlog # workflow state\nowner: alice\npriority: medium\ncomplexity: S\ncreated: 2026-01-01\nupdated: 2026-01-01\njira: PAY-9\nlabels: []\ndependencies: []\n---\n## Summary\n\nText.\n"))
+
+	moved, _, err := mgr.MoveFeature("FTR-0007", "in-progress", "")
+	if err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	data, err := os.ReadFile(moved.Path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !strings.Contains(string(data), "status: in-progress # workflow state\n") || !strings.Contains(string(data), "jira: PAY-9\n") {
+		t.Fatalf("expected custom key and comment to survive move:\n%s", data)
+	}
+}
+
+func TestMoveFeatureChecklistGate(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := NewManager(opts)
+
+	feat, err := mgr.CreateFeature("Gated", nil)
+	if err != nil {
+		t.Fatalf("create feature failed: %v", err)
+	}
+	feat.Body += "\n## Acceptance Criteria\n- [x] First\n- [ ] Second\n\n## Notes\n- [ ] Optional\n"
+	if err := mgr.Save(feat); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	id := feat.FrontMatter.ID
+	for _, status := range []string{"in-progress", "review"} {
+		if _, _, err := mgr.MoveFeature(id, status, ""); err != nil {
+			t.Fatalf("move to %s failed: %v", status, err)
+		}
+	}
+
+	if mgr.UncheckedGated(feat) != nil {
+		t.Fatalf("expected the gate to be disabled by default")
+	}
+	opts.Settings.Checklist.BlockDone = true
+	_, _, err = mgr.MoveFeature(id, "done", "")
+	if !errors.Is(err, ErrInvalidTransition) || !strings.Contains(err.Error(), "1 unchecked item(s) in Acceptance Criteria must be checked before moving to done") {
+		t.Fatalf("expected checklist gate, got %v", err)
+	}
+	if reloaded, _ := mgr.LoadByID(id); reloaded.FrontMatter.Status != "review" {
+		t.Fatalf("expected the feature to stay in review, got %s", reloaded.FrontMatter.Status)
+	}
+
+	reloaded, _ := mgr.LoadByID(id)
+	reloaded.Body = strings.Replace(reloaded.Body, "- [ ] Second", "- [x] Second", 1)
+	if err := mgr.Save(reloaded); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if _, _, err := mgr.MoveFeature(id, "done", ""); err != nil {
+		t.Fatalf("expected move once acceptance criteria are checked, got %v", err)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/patch"
 	"github.com/virtualboard/vb-cli/internal/testutil"
 	"github.com/virtualboard/vb-cli/internal/util"
+	"github.com/virtualboard/vb-cli/internal/workflow"
 )
 
 func TestManagerLifecycle(t *testing.T) {
@@ -112,6 +117,15 @@ func TestManagerLifecycle(t *testing.T) {
 	} else if _, statErr := os.Stat(path); statErr != nil {
 		t.Fatalf("dry run should keep file: %v", statErr)
 	}
+	planned := dryMgr.Patches()
+	if len(planned) != 2 || planned[0].Op != patch.Create || planned[1].Op != patch.Delete ||
+		planned[1].Path != ".virtualboard/features/backlog/FTR-0300-to-delete.md" ||
+		!strings.Contains(planned[1].Diff, "deleted file mode 100644\n") {
+		t.Fatalf("expected create and delete patches, got %+v", planned)
+	}
+	if len(mgr.Patches()) != 0 {
+		t.Fatalf("writes outside dry-run should not be planned")
+	}
 
 	os.RemoveAll(filepath.Join(opts.RootDir, "features"))
 	if _, err := mgr.List(); err != nil {
@@ -134,7 +148,7 @@ func TestNormalizeList(t *testing.T) {
 func newTestFeature(fix *testutil.Fixture, id, status, title string, labels []string) *Feature {
 	workspace := filepath.Join(fix.Root, ".virtualboard")
 	return &Feature{
-		Path: filepath.Join(workspace, DirectoryForStatus(status), fmt.Sprintf("%s-%s.md", id, util.Slugify(title))),
+		Path: filepath.Join(workspace, workflow.Default().DirectoryForStatus(status), fmt.Sprintf("%s-%s.md", id, util.Slugify(title))),
 		FrontMatter: FrontMatter{
 			ID:           id,
 			Title:        title,
@@ -179,11 +193,14 @@ func TestListWithInvalidFiles(t *testing.T) {
 	// Create another invalid file (malformed frontmatter)
 	fix.WriteFile(t, "features/backlog/invalid2.md", []byte("---\ninvalid yaml: [\n---\n"))
 
-	// List should fail with InvalidFileError
-	_, err := mgr.List()
+	// List should fail with InvalidFileError and still return the valid feature
+	features, err := mgr.List()
 	if err == nil {
 		t.Fatalf("expected error when listing with invalid files")
 	}
+	if len(features) != 1 || features[0].FrontMatter.ID != "FTR-0001" {
+		t.Fatalf("expected the valid feature alongside the error, got %d", len(features))
+	}
 
 	var invalidErr *InvalidFileError
 	if !errors.As(err, &invalidErr) {
@@ -310,12 +327,39 @@ func TestMoveFeatureLocking(t *testing.T) {
 	}
 
 	// Verify operational lock was released
-	lockPath := filepath.Join(opts.RootDir, "locks", fmt.Sprintf("op-move-%s.lock", feat.FrontMatter.ID))
+	lockPath := filepath.Join(opts.RootDir, "locks", featureLockID(feat.FrontMatter.ID)+".lock")
 	if _, err := os.Stat(lockPath); !errors.Is(err, os.ErrNotExist) {
 		t.Fatalf("expected operational lock to be released after move")
 	}
 }
 
+func TestFeatureOperationsShareLock(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Shared Lock", nil)
+	mustWriteFeature(t, fix, feat)
+
+	// A lock held for one operation, under any spelling of the ID, blocks the others.
+	if featureLockID(" ftr-0001") != "op-feature-FTR-0001" {
+		t.Fatalf("unexpected lock key %s", featureLockID(" ftr-0001"))
+	}
+	if _, err := mgr.lockMgr.Acquire(featureLockID("ftr-0001"), "someone", 1, false); err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if _, err := mgr.DeleteFeature("FTR-0001"); err == nil {
+		t.Fatalf("expected delete to wait for the feature lock")
+	}
+	if _, _, err := mgr.MoveFeature("FTR-0001", "in-progress", ""); err == nil {
+		t.Fatalf("expected move to wait for the feature lock")
+	}
+	if err := mgr.UpdateFeature(feat); err == nil {
+		t.Fatalf("expected update to wait for the feature lock")
+	}
+	if _, err := os.Stat(feat.Path); err != nil {
+		t.Fatalf("the feature must be untouched: %v", err)
+	}
+}
+
 func TestWithLockNilLockMgr(t *testing.T) {
 	fix := testutil.NewFixture(t)
 	opts := fix.Options(t, false, false, false)
@@ -388,4 +432,133 @@ func TestRenameToMatchTitleDryRun(t *testing.T) {
 	if _, err := os.Stat(originalPath); err != nil {
 		t.Fatalf("dry-run should not remove old file: %v", err)
 	}
+
+	planned := mgr.Patches()
+	if len(planned) != 1 || planned[0].Op != patch.Rename || planned[0].From != ".virtualboard/features/backlog/FTR-0500-original.md" ||
+		planned[0].Path != ".virtualboard/features/backlog/FTR-0500-updated-name.md" || !strings.Contains(planned[0].Diff, "+title: Updated Name\n") {
+		t.Fatalf("expected a rename patch with the title change, got %+v", planned)
+	}
+}
+
+func TestManagerCustomFields(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "config.yaml", []byte("fields:\n  estimate: int\n  team:\n    type: enum\n    values: [core, platform]\n"))
+	schema, err := os.ReadFile(fix.Path("schemas", "frontmatter.schema.json"))
+	if err != nil {
+		t.Fatalf("read schema failed: %v", err)
+	}
+	fix.WriteFile(t, "schemas/frontmatter.schema.json", []byte(strings.Replace(string(schema), `"epic": {"type": "string"},`, `"epic": {"type": "string"}, "due": {"type": "string", "format": "date"},`, 1)))
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	set, err := mgr.CustomFields()
+	if err != nil {
+		t.Fatalf("custom fields failed: %v", err)
+	}
+	if names := set.Names(); strings.Join(names, ",") != "due,estimate,team" {
+		t.Fatalf("unexpected custom fields: %v", names)
+	}
+
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0001", "backlog", "Custom", nil))
+	feat, err := mgr.LoadByID("FTR-0001")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if err := feat.SetField("estimate", "8"); err != nil {
+		t.Fatalf("set custom field failed: %v", err)
+	}
+	if err := feat.SetField("team", "sales"); err == nil {
+		t.Fatalf("expected enum error")
+	}
+	if err := feat.SetField("due", "2026-06-01"); err != nil {
+		t.Fatalf("set date field failed: %v", err)
+	}
+	if err := feat.SetField("due", ""); err != nil {
+		t.Fatalf("clear field failed: %v", err)
+	}
+	if err := mgr.Save(feat); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	features, err := mgr.List()
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if features[0].FrontMatter.Custom["estimate"] != 8 {
+		t.Fatalf("expected persisted custom field, got %#v", features[0].FrontMatter.Custom)
+	}
+	if _, ok := features[0].FrontMatter.Custom["due"]; ok {
+		t.Fatalf("expected cleared field to be removed")
+	}
+}
+
+func TestManagerCustomFieldsErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "config.yaml", []byte("fields:\n  owner: string\n"))
+	if _, err := NewManager(fix.Options(t, false, false, false)).CustomFields(); err == nil || !strings.Contains(err.Error(), "built in") {
+		t.Fatalf("expected error for redeclared built-in field, got %v", err)
+	}
+
+	fix = testutil.NewFixture(t)
+	fix.WriteFile(t, "schemas/frontmatter.schema.json", []byte("{"))
+	mgr := NewManager(fix.Options(t, false, false, false))
+	if _, err := mgr.CustomFields(); err == nil {
+		t.Fatalf("expected error for invalid schema")
+	}
+	mustWriteFeature(t, fix, newTestFeature(fix, "FTR-0001", "backlog", "Custom", nil))
+	feat, err := mgr.LoadByID("FTR-0001")
+	if err != nil {
+		t.Fatalf("load should not fail on schema errors: %v", err)
+	}
+	if err := feat.SetField("estimate", "1"); err == nil {
+		t.Fatalf("expected unknown field without definitions")
+	}
+
+	fix = testutil.NewFixture(t)
+	if err := os.Remove(fix.Path("schemas", "frontmatter.schema.json")); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := os.Mkdir(fix.Path("schemas", "frontmatter.schema.json"), 0o750); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if _, err := NewManager(fix.Options(t, false, false, false)).CustomFields(); err == nil {
+		t.Fatalf("expected read error")
+	}
+
+	fix = testutil.NewFixture(t)
+	if err := os.Remove(fix.Path("schemas", "frontmatter.schema.json")); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if set, err := NewManager(fix.Options(t, false, false, false)).CustomFields(); err != nil || len(set) != 0 {
+		t.Fatalf("missing schema should yield no fields: %v %v", set, err)
+	}
+}
+
+func TestManagerRefusesChangesWithoutAuditSigner(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	feat, err := NewManager(opts).CreateFeature("Before Signing", nil)
+	if err != nil {
+		t.Fatalf("create feature failed: %v", err)
+	}
+
+	t.Setenv(config.AuditKeyEnv, "")
+	opts.Settings.Audit.Signing = config.SigningSettings{Method: config.SigningHMAC}
+	mgr := NewManager(opts)
+	if _, err := mgr.CreateFeature("Unsigned", nil); err == nil || !strings.Contains(err.Error(), "audit signing is configured but unavailable") {
+		t.Fatalf("expected create to fail without a signing key, got %v", err)
+	}
+	if _, _, err := mgr.MoveFeature(feat.FrontMatter.ID, "in-progress", "tester"); err == nil {
+		t.Fatalf("expected move to fail without a signing key")
+	}
+	if err := mgr.UpdateFeature(feat); err == nil {
+		t.Fatalf("expected update to fail without a signing key")
+	}
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected only the first create to be audited, got %d entries (%v)", len(entries), err)
+	}
+
+	opts.DryRun = true
+	if _, _, err := NewManager(opts).MoveFeature(feat.FrontMatter.ID, "in-progress", "tester"); err != nil {
+		t.Fatalf("dry-run writes no audit entries and should not need a key: %v", err)
+	}
 }
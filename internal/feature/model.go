@@ -2,14 +2,16 @@ package feature
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/virtualboard/vb-cli/internal/fields"
 )
 
 var frontmatterPattern = regexp.MustCompile(`(?s)^---\n(.*?)\n---\n(.*)$`)
@@ -28,6 +30,23 @@ type FrontMatter struct {
 	Dependencies []string `yaml:"dependencies" json:"dependencies"`
 	Epic         string   `yaml:"epic,omitempty" json:"epic,omitempty"`
 	RiskNotes    string   `yaml:"risk_notes,omitempty" json:"risk_notes,omitempty"`
+	// Custom holds every other key: strings, ints, floats, bools or string lists.
+	Custom map[string]interface{} `yaml:"-" json:"custom,omitempty"`
+}
+
+// Map flattens the frontmatter, including custom fields, into a single document
+// as it appears in the file; used for JSON schema validation.
+func (fm FrontMatter) Map() map[string]interface{} {
+	out := map[string]interface{}{}
+	data, err := json.Marshal(fm)
+	if err == nil {
+		_ = json.Unmarshal(data, &out)
+	}
+	delete(out, "custom")
+	for key, value := range fm.Custom {
+		out[key] = value
+	}
+	return out
 }
 
 // Feature wraps a feature spec file with parsed components.
@@ -35,6 +54,16 @@ type Feature struct {
 	Path        string
 	FrontMatter FrontMatter
 	Body        string
+
+	// source is the parsed frontmatter document; nil for features built in code.
+	source *frontMatterSource
+	// fields are the workspace's custom field definitions, attached by the Manager.
+	fields fields.Set
+}
+
+// Key normalises a feature ID or dependency reference for comparison.
+func Key(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
 }
 
 // Parse converts raw markdown into a Feature structure.
@@ -48,17 +77,34 @@ func Parse(path string, data []byte) (*Feature, error) {
 	if err := yaml.Unmarshal(matches[1], &fm); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	source, err := newFrontMatterSource(matches[1], fm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if source != nil {
+		fm.Custom = customValues(source.doc.Content[0])
+		source.original = cloneFrontMatter(fm)
+	}
 
 	return &Feature{
 		Path:        path,
 		FrontMatter: fm,
 		Body:        string(bytes.TrimPrefix(matches[2], []byte("\n"))),
+		source:      source,
 	}, nil
 }
 
 // Encode serialises the feature back into markdown format.
+// Features read with Parse keep unknown keys, comments, key order and quoting;
+// if the frontmatter is unchanged it is written back byte for byte.
 func (f *Feature) Encode() ([]byte, error) {
-	fmBytes, err := yaml.Marshal(f.FrontMatter)
+	var fmBytes []byte
+	var err error
+	if f.source != nil {
+		fmBytes, err = f.source.encode(f.FrontMatter)
+	} else {
+		fmBytes, err = marshalFrontMatter(f.FrontMatter)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
 	}
@@ -83,15 +129,6 @@ func (f *Feature) UpdateTimestamp() {
 	f.FrontMatter.Updated = time.Now().Format("2006-01-02")
 }
 
-// StatusDirectory returns the current status directory based on status.
-func (f *Feature) StatusDirectory(root string) string {
-	dir := DirectoryForStatus(f.FrontMatter.Status)
-	if dir == "" {
-		return ""
-	}
-	return filepath.Join(root, dir)
-}
-
 // SetSection replaces a body section identified by an H2 heading (##).
 func (f *Feature) SetSection(section, content string) error {
 	sections := parseSections(f.Body)
@@ -124,7 +161,8 @@ func (f *Feature) AddMissingSections(order []string, defaults map[string]string)
 	}
 }
 
-// SetField updates a frontmatter property by key.
+// SetField updates a frontmatter property by key. Custom fields are accepted when the
+// feature was loaded through a Manager; an empty value removes a custom field.
 func (f *Feature) SetField(key, value string) error {
 	key = strings.ToLower(strings.TrimSpace(key))
 	switch key {
@@ -153,11 +191,56 @@ func (f *Feature) SetField(key, value string) error {
 	case "dependencies":
 		f.FrontMatter.Dependencies = splitList(value)
 	default:
-		return fmt.Errorf("unknown field %s", key)
+		def, ok := f.fields[key]
+		if !ok {
+			return fmt.Errorf("unknown field %s", key)
+		}
+		if strings.TrimSpace(value) == "" {
+			delete(f.FrontMatter.Custom, key)
+			return nil
+		}
+		parsed, err := def.Parse(value)
+		if err != nil {
+			return err
+		}
+		if f.FrontMatter.Custom == nil {
+			f.FrontMatter.Custom = map[string]interface{}{}
+		}
+		f.FrontMatter.Custom[key] = parsed
 	}
 	return nil
 }
 
+// AddLabels appends the labels that are not already present, ignoring case.
+func (f *Feature) AddLabels(labels ...string) {
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || f.HasLabel(label) {
+			continue
+		}
+		f.FrontMatter.Labels = append(f.FrontMatter.Labels, label)
+	}
+}
+
+// HasLabel reports whether the feature carries label, ignoring case.
+func (f *Feature) HasLabel(label string) bool {
+	return containsFold(f.FrontMatter.Labels, strings.TrimSpace(label))
+}
+
+// RemoveLabels drops the given labels, ignoring case.
+func (f *Feature) RemoveLabels(labels ...string) {
+	if len(labels) == 0 {
+		return
+	}
+	kept := make([]string, 0, len(f.FrontMatter.Labels))
+	for _, label := range f.FrontMatter.Labels {
+		if !containsFold(labels, strings.TrimSpace(label)) {
+			kept = append(kept, label)
+		}
+	}
+	f.FrontMatter.Labels = kept
+}
+
 // LabelsAsYAML converts labels to YAML sequence notation used in logs.
 func (f *Feature) LabelsAsYAML() string {
 	if len(f.FrontMatter.Labels) == 0 {
@@ -55,14 +55,6 @@ func TestParseAndEncode(t *testing.T) {
 	if feat.FrontMatter.Updated == "" {
 		t.Fatalf("update timestamp not applied")
 	}
-
-	if feat.StatusDirectory("/root") != "/root/features/backlog" {
-		t.Fatalf("unexpected directory")
-	}
-	feat.FrontMatter.Status = "unknown"
-	if feat.StatusDirectory("/root") != "" {
-		t.Fatalf("expected empty directory for unknown status")
-	}
 }
 
 func TestParseErrors(t *testing.T) {
@@ -199,3 +191,19 @@ Minimal feature without optional fields.
 		t.Fatalf("expected title field in encoded output")
 	}
 }
+
+func TestAddAndRemoveLabels(t *testing.T) {
+	feat := &Feature{FrontMatter: FrontMatter{Labels: []string{"Backend", "q2"}}}
+	feat.AddLabels("backend", " q3 ", "")
+	if strings.Join(feat.FrontMatter.Labels, ",") != "Backend,q2,q3" {
+		t.Fatalf("unexpected labels after add: %v", feat.FrontMatter.Labels)
+	}
+	if !feat.HasLabel("Q3") || feat.HasLabel("frontend") {
+		t.Fatalf("unexpected HasLabel result")
+	}
+	feat.RemoveLabels()
+	feat.RemoveLabels("BACKEND", "missing")
+	if strings.Join(feat.FrontMatter.Labels, ",") != "q2,q3" {
+		t.Fatalf("unexpected labels after remove: %v", feat.FrontMatter.Labels)
+	}
+}
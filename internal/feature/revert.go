@@ -0,0 +1,301 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// revertible lists the audited actions that can be reverted.
+var revertible = map[string]bool{"create": true, "update": true, "move": true, "delete": true, "revert": true}
+
+// minHashPrefix is the shortest entry_hash prefix accepted by Revert.
+const minHashPrefix = 4
+
+// RevertResult describes a reverted operation.
+type RevertResult struct {
+	// Reverted is the audit entry that was undone.
+	Reverted audit.Entry `json:"reverted"`
+	ID       string      `json:"id"`
+	// Path is the restored feature file; empty when the revert removed the feature.
+	Path    string `json:"path,omitempty"`
+	Removed bool   `json:"removed"`
+}
+
+// Revert undoes the audited operation whose entry_hash starts with hash: a
+// created feature is removed, and an updated, moved or deleted feature is
+// restored from the snapshot its entry recorded. Reverting a revert redoes the
+// operation. The feature must not have changed since, through vb or by hand,
+// unless force is set. The revert is audited itself.
+func (m *Manager) Revert(hash string, force bool) (*RevertResult, error) {
+	entries, err := m.readAudit()
+	if err != nil {
+		return nil, err
+	}
+	index, err := findEntry(entries, hash)
+	if err != nil {
+		return nil, err
+	}
+	return m.revert(entries, index, force)
+}
+
+// readAudit reads the audit log, logging the malformed lines it skipped.
+func (m *Manager) readAudit() ([]audit.Entry, error) {
+	entries, skipped, err := audit.ReadEntries(m.AuditPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if len(skipped) > 0 {
+		m.log.WithField("lines", skipped).Warn("Skipping malformed audit entries; run vb audit verify for details")
+	}
+	return entries, nil
+}
+
+// Undo reverts the newest create, update, move or delete by the current user
+// that has not been reverted yet, so repeated calls step further back.
+func (m *Manager) Undo() (*RevertResult, error) {
+	entries, err := m.readAudit()
+	if err != nil {
+		return nil, err
+	}
+	actor := currentUser()
+	reverted := revertedEntries(entries)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Actor == actor && e.Action != "revert" && revertible[e.Action] && !reverted[e.EntryHash] {
+			return m.revert(entries, i, false)
+		}
+	}
+	return nil, fmt.Errorf("%w: no operation by %s to undo", ErrNotRevertible, actor)
+}
+
+func (m *Manager) revert(entries []audit.Entry, index int, force bool) (*RevertResult, error) {
+	e := entries[index]
+	if !revertible[e.Action] || e.FeatureID == "" {
+		return nil, fmt.Errorf("%w: %s entries cannot be reverted", ErrNotRevertible, e.Action)
+	}
+	if revertedEntries(entries)[e.EntryHash] {
+		return nil, fmt.Errorf("%w: %s %s was already reverted", ErrNotRevertible, e.Action, shortHash(e.EntryHash))
+	}
+	if later := laterChanges(entries, index); len(later) > 0 && !force {
+		descriptions := make([]string, len(later))
+		for i, l := range later {
+			descriptions[i] = fmt.Sprintf("%s by %s (%s)", l.Action, l.Actor, shortHash(l.EntryHash))
+		}
+		return nil, fmt.Errorf("%w %s %s of %s: %s", ErrRevertConflict, e.Action, shortHash(e.EntryHash), e.FeatureID, strings.Join(descriptions, ", "))
+	}
+
+	// An operation that created the file is reverted by removing it; any other
+	// is reverted by restoring the file it replaced.
+	remove := e.Action == "create" || (e.Changes != nil && e.Changes.Path != nil && e.Changes.Path.From == "")
+	var data []byte
+	var restored *Feature
+	if !remove {
+		if e.Changes == nil || e.Changes.Snapshot == "" {
+			return nil, fmt.Errorf("%w: %s %s has no snapshot; it was recorded before vb kept them", ErrNotRevertible, e.Action, shortHash(e.EntryHash))
+		}
+		var err error
+		if data, err = m.Snapshot(e.Changes.Snapshot); err != nil {
+			return nil, err
+		}
+		if restored, err = Parse("", data); err != nil {
+			return nil, fmt.Errorf("%w: snapshot %s: %v", ErrNotRevertible, shortHash(e.Changes.Snapshot), err)
+		}
+		m.attachFields(restored)
+	}
+
+	result := &RevertResult{Reverted: e, ID: e.FeatureID, Removed: remove}
+	var changes *audit.Changes
+	err := m.withLock(featureLockID(e.FeatureID), func() error {
+		current, err := m.findByID(e.FeatureID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if !force {
+			if err := m.checkUnchanged(e, current); err != nil {
+				return err
+			}
+		}
+		tx := m.Begin()
+		if remove {
+			if current == "" {
+				return fmt.Errorf("%w: %s no longer exists", ErrNotRevertible, e.FeatureID)
+			}
+			tx.Delete(current)
+		} else {
+			target, err := m.restorePath(e, current, restored)
+			if err != nil {
+				return err
+			}
+			restored.Path = target
+			result.Path = target
+			tx.restore(target, current, data)
+		}
+		var before *featureState
+		var snapshot string
+		if current != "" {
+			before = m.loadState(current)
+			if snapshot, err = m.stash(current); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		if remove {
+			changes = m.changeSet(before, nil)
+		} else {
+			changes = m.changeSet(before, restored)
+			changes.Result = contentHash(data)
+		}
+		changes.Snapshot = snapshot
+		changes.Reverts = e.EntryHash
+		m.log.WithFields(logrus.Fields{
+			"action":   "revert",
+			"id":       e.FeatureID,
+			"reverted": e.EntryHash,
+			"path":     result.Path,
+		}).Info("Feature reverted")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.auditEvent("revert", e.FeatureID, fmt.Sprintf("reverts %s %s", e.Action, shortHash(e.EntryHash)), changes)
+	return result, nil
+}
+
+// checkUnchanged returns a conflict when the feature file at current is not what
+// the entry's operation left: edited by hand, removed, or recreated after a
+// removal. Entries recorded without a result hash are not checked.
+func (m *Manager) checkUnchanged(e audit.Entry, current string) error {
+	c := e.Changes
+	if c == nil {
+		return nil
+	}
+	var problem string
+	switch {
+	case c.Path != nil && c.Path.From != "" && c.Path.To == "":
+		if current != "" {
+			problem = fmt.Sprintf("%s was recreated", m.projectPath(current))
+		}
+	case c.Result != "":
+		if current == "" {
+			problem = "the file was removed outside vb"
+			break
+		}
+		// #nosec G304 -- feature paths are derived from repository structure during discovery
+		data, err := os.ReadFile(current)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", current, err)
+		}
+		if contentHash(data) != c.Result {
+			problem = fmt.Sprintf("%s was edited outside vb", m.projectPath(current))
+		}
+	}
+	if problem == "" {
+		return nil
+	}
+	return fmt.Errorf("%w %s %s of %s: %s", ErrRevertConflict, e.Action, shortHash(e.EntryHash), e.FeatureID, problem)
+}
+
+// restorePath decides where a reverted feature is written: where the reverted
+// operation found it, else where it is now, else the directory for its status.
+func (m *Manager) restorePath(e audit.Entry, current string, restored *Feature) (string, error) {
+	var target string
+	switch {
+	case e.Changes.Path != nil && e.Changes.Path.From != "":
+		target = filepath.Join(filepath.Dir(m.opts.RootDir), filepath.FromSlash(e.Changes.Path.From))
+	case current != "":
+		target = current
+	default:
+		wf := m.Workflow()
+		name := fmt.Sprintf("%s-%s.md", restored.FrontMatter.ID, util.Slugify(restored.FrontMatter.Title))
+		target = filepath.Join(m.opts.RootDir, wf.DirectoryForStatus(strings.ToLower(restored.FrontMatter.Status)), name)
+	}
+	// The path comes from the audit log, so keep it inside the features directory.
+	if rel, err := filepath.Rel(m.FeaturesDir(), target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is outside the features directory", ErrNotRevertible, target)
+	}
+	return target, nil
+}
+
+// findEntry returns the index of the entry whose entry_hash starts with prefix.
+func findEntry(entries []audit.Entry, prefix string) (int, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if len(prefix) < minHashPrefix {
+		return 0, fmt.Errorf("%w: entry hash %q is too short; give at least %d characters", ErrNotRevertible, prefix, minHashPrefix)
+	}
+	found := -1
+	for i, e := range entries {
+		if strings.HasPrefix(e.EntryHash, prefix) {
+			if found >= 0 {
+				return 0, fmt.Errorf("%w: entry hash %s is ambiguous", ErrNotRevertible, prefix)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return 0, fmt.Errorf("%w: no audit entry %s", ErrNotRevertible, prefix)
+	}
+	return found, nil
+}
+
+// revertedEntries returns the entry hashes whose operation is currently undone.
+// Reverting a revert redoes its operation, so each revert flips its target and,
+// in turn, everything that target had reverted.
+func revertedEntries(entries []audit.Entry) map[string]bool {
+	targets := map[string]string{}
+	reverted := map[string]bool{}
+	var mark func(hash string, undone bool)
+	mark = func(hash string, undone bool) {
+		reverted[hash] = undone
+		if target, ok := targets[hash]; ok {
+			mark(target, !undone)
+		}
+	}
+	for _, e := range entries {
+		if e.Action != "revert" || e.Changes == nil || e.Changes.Reverts == "" {
+			continue
+		}
+		targets[e.EntryHash] = e.Changes.Reverts
+		mark(e.Changes.Reverts, true)
+	}
+	return reverted
+}
+
+// laterChanges returns the operations on the entry's feature logged after it.
+// An operation reverted later on cancels out with its revert and is left out.
+func laterChanges(entries []audit.Entry, index int) []audit.Entry {
+	id := entries[index].FeatureID
+	var later []audit.Entry
+	for _, e := range entries[index+1:] {
+		if revertible[e.Action] && strings.EqualFold(e.FeatureID, id) {
+			later = append(later, e)
+		}
+	}
+	seen := map[string]bool{}
+	cancelled := map[string]bool{}
+	for _, e := range later {
+		if e.Action == "revert" && e.Changes != nil && seen[e.Changes.Reverts] && !cancelled[e.Changes.Reverts] {
+			cancelled[e.Changes.Reverts] = true
+			cancelled[e.EntryHash] = true
+		}
+		seen[e.EntryHash] = true
+	}
+	kept := later[:0]
+	for _, e := range later {
+		if !cancelled[e.EntryHash] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
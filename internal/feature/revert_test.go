@@ -0,0 +1,380 @@
+package feature
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/audit"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+// featureOps returns the audit entries of feature operations, leaving out locks.
+func featureOps(t *testing.T, mgr *Manager) []audit.Entry {
+	t.Helper()
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return audit.Filter{Actions: []string{"create", "update", "move", "delete", "revert"}}.Apply(entries)
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUndoStepsBack(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	feat, err := mgr.CreateFeature("Undoable", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	id, created := feat.FrontMatter.ID, feat.Path
+	original := readFile(t, created)
+
+	feat.FrontMatter.Priority = "high"
+	feat.Body = strings.Replace(feat.Body, "<Feature Title>", "Undoable", 1) + "\n## Notes\n\nAdded later.\n"
+	if err := mgr.UpdateFeature(feat); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	updated := readFile(t, created)
+	moved, _, err := mgr.MoveFeature(id, "in-progress", "alice")
+	if err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	inProgress := readFile(t, moved.Path)
+	if _, err := mgr.DeleteFeature(id); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	result, err := mgr.Undo()
+	if err != nil || result.Reverted.Action != "delete" || result.Path != moved.Path || result.Removed {
+		t.Fatalf("expected the delete to be undone, got %+v (%v)", result, err)
+	}
+	if got := readFile(t, moved.Path); got != inProgress {
+		t.Fatalf("expected the deleted file back, got\n%s", got)
+	}
+
+	if result, err = mgr.Undo(); err != nil || result.Reverted.Action != "move" || result.Path != created {
+		t.Fatalf("expected the move to be undone, got %+v (%v)", result, err)
+	}
+	if _, err := os.Stat(moved.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected the moved file to be gone, got %v", err)
+	}
+	if got := readFile(t, created); got != updated {
+		t.Fatalf("expected the file before the move, got\n%s", got)
+	}
+
+	if result, err = mgr.Undo(); err != nil || result.Reverted.Action != "update" {
+		t.Fatalf("expected the update to be undone, got %+v (%v)", result, err)
+	}
+	if got := readFile(t, created); got != original {
+		t.Fatalf("expected the created file, got\n%s", got)
+	}
+
+	if result, err = mgr.Undo(); err != nil || result.Reverted.Action != "create" || !result.Removed || result.Path != "" {
+		t.Fatalf("expected the create to be undone, got %+v (%v)", result, err)
+	}
+	if _, err := mgr.LoadByID(id); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the feature to be removed, got %v", err)
+	}
+	if _, err := mgr.Undo(); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), "no operation by tester") {
+		t.Fatalf("expected nothing left to undo, got %v", err)
+	}
+
+	ops := featureOps(t, mgr)
+	if len(ops) != 8 {
+		t.Fatalf("expected four operations and four reverts, got %d", len(ops))
+	}
+	undoUpdate := ops[6]
+	if undoUpdate.Details != "reverts update "+shortHash(ops[1].EntryHash) || undoUpdate.Changes.Reverts != ops[1].EntryHash {
+		t.Fatalf("unexpected revert entry %+v", undoUpdate)
+	}
+	if field, ok := undoUpdate.Changes.Field("priority"); !ok || field.Old != "high" || field.New != "medium" {
+		t.Fatalf("expected the revert's change set, got %+v", undoUpdate.Changes)
+	}
+	if verification, err := audit.Verify(mgr.AuditPath()); err != nil || !verification.OK() {
+		t.Fatalf("expected an intact audit log, got %+v (%v)", verification, err)
+	}
+}
+
+func TestRevertByHash(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	feat, err := mgr.CreateFeature("Revertible", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	original := readFile(t, feat.Path)
+	for _, priority := range []string{"high", "low"} {
+		feat.FrontMatter.Priority = priority
+		if err := mgr.UpdateFeature(feat); err != nil {
+			t.Fatalf("update failed: %v", err)
+		}
+	}
+	ops := featureOps(t, mgr)
+	first, second := ops[1].EntryHash, ops[2].EntryHash
+
+	if _, err := mgr.Revert(first[:8], false); !errors.Is(err, ErrRevertConflict) ||
+		!strings.Contains(err.Error(), "update by tester ("+shortHash(second)+")") {
+		t.Fatalf("expected a conflict with the later update, got %v", err)
+	}
+	if _, err := mgr.Revert(second, false); err != nil {
+		t.Fatalf("revert of the newest update failed: %v", err)
+	}
+	// The second update and its revert cancel out, so the first one can go.
+	if _, err := mgr.Revert(first, false); err != nil {
+		t.Fatalf("revert of the first update failed: %v", err)
+	}
+	if got := readFile(t, feat.Path); got != original {
+		t.Fatalf("expected the created file, got\n%s", got)
+	}
+	if _, err := mgr.Revert(first, false); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), "already reverted") {
+		t.Fatalf("expected an already reverted error, got %v", err)
+	}
+
+	// Reverting the revert redoes the update.
+	ops = featureOps(t, mgr)
+	redo, err := mgr.Revert(ops[len(ops)-1].EntryHash, false)
+	if err != nil || redo.Reverted.Action != "revert" {
+		t.Fatalf("redo failed: %+v (%v)", redo, err)
+	}
+	if loaded, err := mgr.LoadByID(feat.FrontMatter.ID); err != nil || loaded.FrontMatter.Priority != "high" {
+		t.Fatalf("expected the first update to be redone, got %+v (%v)", loaded, err)
+	}
+	if _, err := mgr.Revert(first, false); err != nil {
+		t.Fatalf("a redone update should be revertible again: %v", err)
+	}
+
+	// Forcing a revert past later changes.
+	if _, err := mgr.Revert(ops[0].EntryHash, false); !errors.Is(err, ErrRevertConflict) {
+		t.Fatalf("expected a conflict reverting the create, got %v", err)
+	}
+	if result, err := mgr.Revert(ops[0].EntryHash, true); err != nil || !result.Removed {
+		t.Fatalf("forced revert failed: %+v (%v)", result, err)
+	}
+	if _, err := mgr.Revert(ops[0].EntryHash, true); !errors.Is(err, ErrNotRevertible) {
+		t.Fatalf("expected the create to be reverted already, got %v", err)
+	}
+}
+
+func TestRevertErrors(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	if _, err := mgr.Undo(); !errors.Is(err, ErrNotRevertible) {
+		t.Fatalf("expected nothing to undo in an empty log, got %v", err)
+	}
+	feat, err := mgr.CreateFeature("Broken", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	feat.FrontMatter.Priority = "high"
+	if err := mgr.UpdateFeature(feat); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	entries, _, err := audit.ReadEntries(mgr.AuditPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := featureOps(t, mgr)[1]
+
+	cases := []struct {
+		hash string
+		want string
+	}{
+		{"ab", "too short"},
+		{"zzzzzzzz", "no audit entry zzzzzzzz"},
+		{entries[0].EntryHash, "lock entries cannot be reverted"},
+	}
+	for _, tc := range cases {
+		if _, err := mgr.Revert(tc.hash, false); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected %q, got %v", tc.hash, tc.want, err)
+		}
+	}
+
+	snapshot := mgr.snapshotPath(update.Changes.Snapshot)
+	if err := os.WriteFile(snapshot, []byte("tampered"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Revert(update.EntryHash, false); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), "was modified") {
+		t.Fatalf("expected a modified snapshot, got %v", err)
+	}
+	if err := os.Remove(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Revert(update.EntryHash, false); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), "is not in .trash") {
+		t.Fatalf("expected a missing snapshot, got %v", err)
+	}
+
+	// Entries from before snapshots were kept, and entries pointing outside the
+	// features directory, are refused.
+	logger, err := audit.NewLogger(mgr.AuditPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log("update", "tester", feat.FrontMatter.ID, "legacy"); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := mgr.stash(feat.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escape := &audit.Changes{Path: &audit.PathChange{From: "../outside.md", To: "x.md"}, Snapshot: sum}
+	if err := logger.LogChanges("move", "tester", feat.FrontMatter.ID, "", escape); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(mgr.snapshotPath("0000"), []byte("no frontmatter"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ops := featureOps(t, mgr)
+	if _, err := mgr.Revert(ops[2].EntryHash, true); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), "has no snapshot") {
+		t.Fatalf("expected a missing snapshot, got %v", err)
+	}
+	if _, err := mgr.Revert(ops[3].EntryHash, true); !errors.Is(err, ErrNotRevertible) || !strings.Contains(err.Error(), "outside the features directory") {
+		t.Fatalf("expected the path to be refused, got %v", err)
+	}
+
+	if err := os.WriteFile(mgr.AuditPath(), []byte("{broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Revert("abcd", false); !errors.Is(err, ErrNotRevertible) {
+		t.Fatalf("expected the malformed line to be skipped, got %v", err)
+	}
+	if err := os.Remove(mgr.AuditPath()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(mgr.AuditPath(), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Revert("abcd", false); err == nil || !strings.Contains(err.Error(), "failed to read audit log") {
+		t.Fatalf("expected an audit log error, got %v", err)
+	}
+	if _, err := mgr.Undo(); err == nil || !strings.Contains(err.Error(), "failed to read audit log") {
+		t.Fatalf("expected an audit log error, got %v", err)
+	}
+}
+
+func TestRevertRefusesHandEdits(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	feat, err := mgr.CreateFeature("Edited By Hand", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	feat.FrontMatter.Priority = "high"
+	if err := mgr.UpdateFeature(feat); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	update := featureOps(t, mgr)[1]
+	if update.Changes == nil || update.Changes.Result != contentHash([]byte(readFile(t, feat.Path))) {
+		t.Fatalf("expected the update to record the file it wrote, got %+v", update.Changes)
+	}
+
+	edited := readFile(t, feat.Path) + "\nEdited by hand.\n"
+	if err := os.WriteFile(feat.Path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Undo(); !errors.Is(err, ErrRevertConflict) || !strings.Contains(err.Error(), "was edited outside vb") {
+		t.Fatalf("expected the hand edit to block the revert, got %v", err)
+	}
+	if readFile(t, feat.Path) != edited {
+		t.Fatalf("the hand edit should be kept")
+	}
+	if _, err := mgr.Revert(update.EntryHash, true); err != nil {
+		t.Fatalf("forced revert failed: %v", err)
+	}
+	if loaded, err := mgr.LoadByID(feat.FrontMatter.ID); err != nil || loaded.FrontMatter.Priority == "high" {
+		t.Fatalf("expected the forced revert to restore the feature, got %+v (%v)", loaded, err)
+	}
+
+	// A deleted feature recreated by hand is not overwritten either.
+	path, err := mgr.DeleteFeature(feat.FrontMatter.ID)
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Undo(); !errors.Is(err, ErrRevertConflict) || !strings.Contains(err.Error(), "was recreated") {
+		t.Fatalf("expected the recreated file to block the revert, got %v", err)
+	}
+}
+
+func TestRevertRestoresByStatus(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	feat := newTestFeature(fix, "FTR-0007", "review", "Elsewhere", nil)
+	mustWriteFeature(t, fix, feat)
+	feat.FrontMatter.Owner = "bob"
+	if err := mgr.UpdateFeature(feat); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := os.Remove(feat.Path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file was removed by hand, which is a conflict unless forced. The update
+	// recorded no path and the file is gone, so the feature is restored into the
+	// directory for its status.
+	update := featureOps(t, mgr)[0]
+	if _, err := mgr.Revert(update.EntryHash, false); !errors.Is(err, ErrRevertConflict) || !strings.Contains(err.Error(), "removed outside vb") {
+		t.Fatalf("expected a conflict for the removed file, got %v", err)
+	}
+	result, err := mgr.Revert(update.EntryHash, true)
+	want := fix.Path("features", "review", "FTR-0007-elsewhere.md")
+	if err != nil || result.Path != want {
+		t.Fatalf("expected the feature restored to %s, got %+v (%v)", want, result, err)
+	}
+	if loaded, err := mgr.LoadByID("FTR-0007"); err != nil || loaded.FrontMatter.Owner != "owner" {
+		t.Fatalf("expected the previous owner, got %+v (%v)", loaded, err)
+	}
+}
+
+func TestRevertDryRun(t *testing.T) {
+	t.Setenv("USER", "tester")
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := NewManager(opts)
+
+	feat, err := mgr.CreateFeature("Dry", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, _, err := mgr.MoveFeature(feat.FrontMatter.ID, "in-progress", ""); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	before := len(featureOps(t, mgr))
+
+	opts.DryRun = true
+	dry := NewManager(opts)
+	result, err := dry.Undo()
+	if err != nil || result.Path != feat.Path {
+		t.Fatalf("dry-run undo failed: %+v (%v)", result, err)
+	}
+	if patches := dry.Patches(); len(patches) != 1 || patches[0].Op != "rename" {
+		t.Fatalf("expected a planned rename, got %+v", patches)
+	}
+	if _, err := os.Stat(feat.Path); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not restore the file, got %v", err)
+	}
+	if after := len(featureOps(t, mgr)); after != before {
+		t.Fatalf("dry run must not audit, got %d entries instead of %d", after, before)
+	}
+}
@@ -0,0 +1,55 @@
+package feature
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var commentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// Violation is a status rule a feature breaks.
+type Violation struct {
+	Message string
+	// Field is the frontmatter key the violation concerns, if any.
+	Field string
+	// Section is the body section the violation concerns, if any.
+	Section string
+}
+
+// RuleViolations returns every status rule from the settings that the feature
+// breaks for the given status. An empty result means the feature may be in it.
+func (m *Manager) RuleViolations(feat *Feature, status string) []Violation {
+	status = strings.ToLower(strings.TrimSpace(status))
+	rules, ok := m.opts.Settings.Rules[status]
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	if rules.RequireOwner {
+		owner := strings.TrimSpace(feat.FrontMatter.Owner)
+		if owner == "" || strings.EqualFold(owner, "unassigned") {
+			violations = append(violations, Violation{Message: fmt.Sprintf("status %s requires an owner", status), Field: "owner"})
+		}
+	}
+
+	sections := parseSections(feat.Body)
+	for _, required := range rules.RequiredSections {
+		required = strings.TrimSpace(required)
+		name, found := "", false
+		for _, existing := range sections.Order {
+			if strings.EqualFold(existing, required) {
+				name, found = existing, true
+				break
+			}
+		}
+		switch {
+		case !found:
+			violations = append(violations, Violation{Message: fmt.Sprintf("status %s requires a '## %s' section", status, required), Field: "status"})
+		case strings.TrimSpace(commentPattern.ReplaceAllString(sections.Data[name], "")) == "":
+			violations = append(violations, Violation{Message: fmt.Sprintf("status %s requires a non-empty '## %s' section", status, name), Section: name})
+		}
+	}
+	return violations
+}
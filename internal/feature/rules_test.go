@@ -0,0 +1,64 @@
+package feature
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestRuleViolations(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	opts.Settings.Rules = map[string]config.StatusRules{
+		"review": {RequiredSections: []string{"acceptance criteria", "Test Plan"}, RequireOwner: true},
+	}
+	mgr := NewManager(opts)
+
+	feat := &Feature{
+		FrontMatter: FrontMatter{Owner: "unassigned"},
+		Body:        "## Acceptance Criteria\n<!-- list the criteria -->\n\n## Notes\nx\n",
+	}
+	want := []Violation{
+		{Message: "status review requires an owner", Field: "owner"},
+		{Message: "status review requires a non-empty '## Acceptance Criteria' section", Section: "Acceptance Criteria"},
+		{Message: "status review requires a '## Test Plan' section", Field: "status"},
+	}
+	if got := mgr.RuleViolations(feat, " Review "); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := mgr.RuleViolations(feat, "backlog"); len(got) != 0 {
+		t.Fatalf("expected no rules for backlog, got %v", got)
+	}
+
+	feat.FrontMatter.Owner = "alice"
+	feat.Body = "## Acceptance Criteria\n- [ ] works\n\n## Test Plan\nManual\n"
+	if got := mgr.RuleViolations(feat, "review"); len(got) != 0 {
+		t.Fatalf("expected no violations, got %v", got)
+	}
+}
+
+func TestMoveFeatureStatusRules(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	opts.Settings.Rules = map[string]config.StatusRules{"in-progress": {RequireOwner: true}}
+	mgr := NewManager(opts)
+
+	feat, err := mgr.CreateFeature("Ruled", nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	_, _, err = mgr.MoveFeature(feat.FrontMatter.ID, "in-progress", "")
+	if !errors.Is(err, ErrRuleViolation) || !errors.Is(err, ErrInvalidTransition) || !strings.Contains(err.Error(), "status in-progress requires an owner") {
+		t.Fatalf("expected rule violation, got %v", err)
+	}
+	if reloaded, _ := mgr.LoadByID(feat.FrontMatter.ID); reloaded.FrontMatter.Status != "backlog" {
+		t.Fatalf("expected the feature to stay in backlog, got %s", reloaded.FrontMatter.Status)
+	}
+	if _, _, err := mgr.MoveFeature(feat.FrontMatter.ID, "in-progress", "alice"); err != nil {
+		t.Fatalf("expected the owner given with the move to satisfy the rule, got %v", err)
+	}
+}
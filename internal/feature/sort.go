@@ -0,0 +1,103 @@
+package feature
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKeys lists the columns accepted by SortFeatures.
+var SortKeys = []string{"id", "title", "status", "owner", "priority", "complexity", "epic", "created", "updated"}
+
+var priorityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+var complexityRank = map[string]int{"XS": 0, "S": 1, "M": 2, "L": 3, "XL": 4}
+
+// SortFeatures orders features by the given keys. A key prefixed with "-" sorts
+// descending. Ties fall back to the feature ID so output is deterministic.
+func SortFeatures(features []*Feature, keys []string) error {
+	type sortKey struct {
+		name string
+		desc bool
+	}
+	parsed := make([]sortKey, 0, len(keys))
+	for _, raw := range keys {
+		raw = strings.ToLower(strings.TrimSpace(raw))
+		if raw == "" {
+			continue
+		}
+		key := sortKey{name: strings.TrimPrefix(raw, "-"), desc: strings.HasPrefix(raw, "-")}
+		valid := false
+		for _, allowed := range SortKeys {
+			if key.name == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown sort key %q (allowed: %s)", key.name, strings.Join(SortKeys, ", "))
+		}
+		parsed = append(parsed, key)
+	}
+
+	sort.SliceStable(features, func(i, j int) bool {
+		for _, key := range parsed {
+			cmp := compareField(features[i], features[j], key.name)
+			if cmp == 0 {
+				continue
+			}
+			if key.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return features[i].FrontMatter.ID < features[j].FrontMatter.ID
+	})
+	return nil
+}
+
+func compareField(a, b *Feature, key string) int {
+	switch key {
+	case "priority":
+		return compareRanked(priorityRank, strings.ToLower(a.FrontMatter.Priority), strings.ToLower(b.FrontMatter.Priority))
+	case "complexity":
+		return compareRanked(complexityRank, strings.ToUpper(a.FrontMatter.Complexity), strings.ToUpper(b.FrontMatter.Complexity))
+	}
+	return strings.Compare(strings.ToLower(sortValue(a, key)), strings.ToLower(sortValue(b, key)))
+}
+
+// compareRanked orders known values by rank and unknown values after them alphabetically.
+func compareRanked(ranks map[string]int, a, b string) int {
+	ra, okA := ranks[a]
+	rb, okB := ranks[b]
+	switch {
+	case okA && okB:
+		return ra - rb
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sortValue(feat *Feature, key string) string {
+	fm := feat.FrontMatter
+	switch key {
+	case "id":
+		return fm.ID
+	case "title":
+		return fm.Title
+	case "status":
+		return fm.Status
+	case "owner":
+		return fm.Owner
+	case "epic":
+		return fm.Epic
+	case "created":
+		return fm.Created
+	case "updated":
+		return fm.Updated
+	}
+	return ""
+}
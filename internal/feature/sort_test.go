@@ -0,0 +1,81 @@
+package feature
+
+import (
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func sortFixtures(fix *testutil.Fixture) []*Feature {
+	a := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", []string{"backend"})
+	a.FrontMatter.Priority = "low"
+	a.FrontMatter.Complexity = "XL"
+	a.FrontMatter.Updated = "2026-01-10"
+	a.FrontMatter.Epic = "payments"
+
+	b := newTestFeature(fix, "FTR-0002", "review", "Bravo", []string{"frontend", "ux"})
+	b.FrontMatter.Owner = "alice"
+	b.FrontMatter.Priority = "critical"
+	b.FrontMatter.Complexity = "S"
+	b.FrontMatter.Updated = "2026-03-01"
+
+	c := newTestFeature(fix, "FTR-0003", "review", "Charlie", nil)
+	c.FrontMatter.Owner = ""
+	c.FrontMatter.Priority = "high"
+	c.FrontMatter.Complexity = "M"
+	c.FrontMatter.Updated = "2025-12-31"
+	return []*Feature{a, b, c}
+}
+
+func TestSortFeatures(t *testing.T) {
+	fix := testutil.NewFixture(t)
+
+	ids := func(features []*Feature) []string {
+		out := make([]string, len(features))
+		for i, f := range features {
+			out[i] = f.FrontMatter.ID
+		}
+		return out
+	}
+
+	cases := []struct {
+		keys []string
+		want []string
+	}{
+		{nil, []string{"FTR-0001", "FTR-0002", "FTR-0003"}},
+		{[]string{"priority"}, []string{"FTR-0002", "FTR-0003", "FTR-0001"}},
+		{[]string{"-complexity"}, []string{"FTR-0001", "FTR-0003", "FTR-0002"}},
+		{[]string{"-updated"}, []string{"FTR-0002", "FTR-0001", "FTR-0003"}},
+		{[]string{"status", "-title"}, []string{"FTR-0001", "FTR-0003", "FTR-0002"}},
+		{[]string{"owner", " "}, []string{"FTR-0003", "FTR-0002", "FTR-0001"}},
+		{[]string{"epic", "created"}, []string{"FTR-0002", "FTR-0003", "FTR-0001"}},
+	}
+	for _, tc := range cases {
+		features := sortFixtures(fix)
+		if err := SortFeatures(features, tc.keys); err != nil {
+			t.Fatalf("sort %v failed: %v", tc.keys, err)
+		}
+		got := ids(features)
+		for i := range tc.want {
+			if got[i] != tc.want[i] {
+				t.Fatalf("sort %v: expected %v, got %v", tc.keys, tc.want, got)
+			}
+		}
+	}
+
+	if err := SortFeatures(sortFixtures(fix), []string{"bogus"}); err == nil {
+		t.Fatalf("expected error for unknown sort key")
+	}
+}
+
+func TestCompareRankedUnknownValues(t *testing.T) {
+	if compareRanked(priorityRank, "high", "someday") >= 0 {
+		t.Fatalf("known priority should sort before unknown")
+	}
+	if compareRanked(priorityRank, "someday", "low") <= 0 {
+		t.Fatalf("unknown priority should sort after known")
+	}
+	if compareRanked(priorityRank, "a", "b") >= 0 {
+		t.Fatalf("unknown priorities should sort alphabetically")
+	}
+}
@@ -0,0 +1,253 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// File operations used when committing; replaced in tests to inject failures.
+var (
+	txWriteFile  = util.WriteFileAtomic
+	txRemoveFile = os.Remove
+)
+
+type txOpKind int
+
+const (
+	txWrite txOpKind = iota
+	txDelete
+)
+
+// txOp is a single staged file operation.
+type txOp struct {
+	kind txOpKind
+	path string
+	data []byte
+	// from is the previous path of a file written by Move.
+	from string
+}
+
+// txBackup records the state of a path before the transaction touched it.
+type txBackup struct {
+	path   string
+	exists bool
+	data   []byte
+	mode   fs.FileMode
+}
+
+// Transaction stages feature writes, renames and deletes so they can be applied
+// together. If any operation fails, every file already touched is restored.
+type Transaction struct {
+	mgr *Manager
+	ops []txOp
+}
+
+// Begin starts a transaction. Nothing touches the disk until Commit.
+func (m *Manager) Begin() *Transaction {
+	return &Transaction{mgr: m}
+}
+
+// Len reports the number of staged operations.
+func (tx *Transaction) Len() int {
+	return len(tx.ops)
+}
+
+// Save stages writing the feature to its current path.
+func (tx *Transaction) Save(feat *Feature) error {
+	data, err := feat.Encode()
+	if err != nil {
+		return err
+	}
+	tx.ops = append(tx.ops, txOp{kind: txWrite, path: feat.Path, data: data})
+	return nil
+}
+
+// Move stages writing the feature to newPath and removing its previous file.
+// The feature's Path is updated immediately.
+func (tx *Transaction) Move(feat *Feature, newPath string) error {
+	data, err := feat.Encode()
+	if err != nil {
+		return err
+	}
+	oldPath := feat.Path
+	feat.Path = newPath
+	tx.ops = append(tx.ops, txOp{kind: txWrite, path: newPath, data: data, from: oldPath})
+	if oldPath != newPath {
+		tx.Delete(oldPath)
+	}
+	return nil
+}
+
+// RenameToMatchTitle stages a rename of the feature file so its name matches the
+// current title. It reports whether a rename was staged.
+func (tx *Transaction) RenameToMatchTitle(feat *Feature) (bool, error) {
+	expectedName := fmt.Sprintf("%s-%s.md", feat.FrontMatter.ID, util.Slugify(feat.FrontMatter.Title))
+	if strings.EqualFold(filepath.Base(feat.Path), expectedName) {
+		return false, nil
+	}
+	if err := tx.Move(feat, filepath.Join(filepath.Dir(feat.Path), expectedName)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// restore stages writing data to path as is and, when the feature currently
+// lives at another path from, removing that file.
+func (tx *Transaction) restore(path, from string, data []byte) {
+	tx.ops = append(tx.ops, txOp{kind: txWrite, path: path, data: data, from: from})
+	if from != "" && from != path {
+		tx.Delete(from)
+	}
+}
+
+// Delete stages removing the file at path.
+func (tx *Transaction) Delete(path string) {
+	tx.ops = append(tx.ops, txOp{kind: txDelete, path: path})
+}
+
+// Commit applies the staged operations in order. On failure the files already
+// changed are restored and the returned error describes both the failure and
+// any problem encountered while rolling back.
+func (tx *Transaction) Commit() error {
+	m := tx.mgr
+	ops := tx.ops
+	tx.ops = nil
+
+	if m.opts.DryRun {
+		for _, op := range ops {
+			m.log.WithFields(logrus.Fields{
+				"action": op.kind.String(),
+				"path":   op.path,
+				"dryRun": true,
+			}).Info("Skipping transaction operation in dry-run mode")
+		}
+		return tx.plan(ops)
+	}
+
+	backups := []txBackup{}
+	seen := map[string]struct{}{}
+	for _, op := range ops {
+		if _, ok := seen[op.path]; ok {
+			continue
+		}
+		seen[op.path] = struct{}{}
+		backup, err := snapshot(op.path)
+		if err != nil {
+			return err
+		}
+		backups = append(backups, backup)
+	}
+
+	for i, op := range ops {
+		var err error
+		switch op.kind {
+		case txWrite:
+			err = txWriteFile(op.path, op.data, 0o644)
+		case txDelete:
+			err = txRemoveFile(op.path)
+			if errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to %s %s: %w", op.kind, op.path, err)
+			if rbErr := tx.rollback(backups); rbErr != nil {
+				return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+			m.log.WithFields(logrus.Fields{
+				"action":  "rollback",
+				"applied": i,
+				"staged":  len(ops),
+			}).Warn("Transaction rolled back")
+			return err
+		}
+	}
+	return nil
+}
+
+// plan records the operations as patches on the manager. A write from Move and
+// the delete of its previous path are one rename.
+func (tx *Transaction) plan(ops []txOp) error {
+	m := tx.mgr
+	root := filepath.Dir(m.opts.RootDir)
+	renamed := map[string]bool{}
+	for _, op := range ops {
+		if op.kind == txWrite && op.from != "" && op.from != op.path {
+			renamed[op.from] = true
+		}
+	}
+	for _, op := range ops {
+		var p *patch.Patch
+		var err error
+		switch {
+		case op.kind == txDelete && renamed[op.path]:
+			continue
+		case op.kind == txDelete:
+			p, err = patch.Remove(root, op.path)
+		case op.from != "":
+			p, err = patch.Write(root, op.from, op.path, op.data)
+		default:
+			p, err = patch.Write(root, op.path, op.path, op.data)
+		}
+		if err != nil {
+			return err
+		}
+		m.planned.Add(p)
+	}
+	return nil
+}
+
+// rollback restores every backed-up path to its original state.
+func (tx *Transaction) rollback(backups []txBackup) error {
+	var failed []string
+	for i := len(backups) - 1; i >= 0; i-- {
+		b := backups[i]
+		var err error
+		if b.exists {
+			err = txWriteFile(b.path, b.data, b.mode)
+		} else if rmErr := txRemoveFile(b.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			err = rmErr
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", b.path, err))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func snapshot(path string) (txBackup, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return txBackup{path: path}, nil
+	}
+	if err != nil {
+		return txBackup{}, fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return txBackup{}, fmt.Errorf("cannot replace directory %s", path)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a staged feature file
+	if err != nil {
+		return txBackup{}, fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return txBackup{path: path, exists: true, data: data, mode: info.Mode().Perm()}, nil
+}
+
+func (k txOpKind) String() string {
+	if k == txDelete {
+		return "delete"
+	}
+	return "write"
+}
@@ -0,0 +1,252 @@
+package feature
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+// failWritesTo makes committed writes to path fail for the duration of the test.
+func failWritesTo(t *testing.T, path string) {
+	t.Helper()
+	orig := txWriteFile
+	t.Cleanup(func() { txWriteFile = orig })
+	txWriteFile = func(p string, data []byte, perm fs.FileMode) error {
+		if p == path {
+			return errors.New("disk full")
+		}
+		return orig(p, data, perm)
+	}
+}
+
+func TestTransactionCommitAppliesAllOperations(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	a := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", nil)
+	b := newTestFeature(fix, "FTR-0002", "backlog", "Beta", nil)
+	c := newTestFeature(fix, "FTR-0003", "backlog", "Gamma", nil)
+	for _, feat := range []*Feature{a, b, c} {
+		mustWriteFeature(t, fix, feat)
+	}
+	oldA := a.Path
+
+	tx := mgr.Begin()
+	a.FrontMatter.Status = "in-progress"
+	if err := tx.Move(a, filepath.Join(fix.Root, ".virtualboard", mgr.Workflow().DirectoryForStatus("in-progress"), filepath.Base(a.Path))); err != nil {
+		t.Fatalf("stage move failed: %v", err)
+	}
+	b.FrontMatter.Owner = "bob"
+	if err := tx.Save(b); err != nil {
+		t.Fatalf("stage save failed: %v", err)
+	}
+	tx.Delete(c.Path)
+	if tx.Len() != 4 {
+		t.Fatalf("expected 4 staged operations, got %d", tx.Len())
+	}
+	if _, err := os.Stat(oldA); err != nil {
+		t.Fatalf("nothing should be written before commit: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if tx.Len() != 0 {
+		t.Fatalf("expected staged operations to be cleared after commit")
+	}
+	if _, err := os.Stat(oldA); !os.IsNotExist(err) {
+		t.Fatalf("expected old file to be removed")
+	}
+	if _, err := os.Stat(a.Path); err != nil {
+		t.Fatalf("expected moved file: %v", err)
+	}
+	if _, err := os.Stat(c.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected deleted file to be gone")
+	}
+	reloaded, err := mgr.LoadByID("FTR-0002")
+	if err != nil || reloaded.FrontMatter.Owner != "bob" {
+		t.Fatalf("expected saved owner, got %v %+v", err, reloaded)
+	}
+}
+
+func TestTransactionRollsBackOnFailure(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	a := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", nil)
+	b := newTestFeature(fix, "FTR-0002", "backlog", "Beta", nil)
+	mustWriteFeature(t, fix, a)
+	mustWriteFeature(t, fix, b)
+	originalA, _ := os.ReadFile(a.Path)
+	originalB, _ := os.ReadFile(b.Path)
+	oldA := a.Path
+
+	tx := mgr.Begin()
+	a.FrontMatter.Title = "Alpha Renamed"
+	if renamed, err := tx.RenameToMatchTitle(a); err != nil || !renamed {
+		t.Fatalf("expected rename to be staged: %v %v", renamed, err)
+	}
+	b.FrontMatter.Owner = "bob"
+	if err := tx.Save(b); err != nil {
+		t.Fatalf("stage save failed: %v", err)
+	}
+	failWritesTo(t, b.Path)
+
+	err := tx.Commit()
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected commit failure, got %v", err)
+	}
+	if _, statErr := os.Stat(a.Path); !os.IsNotExist(statErr) {
+		t.Fatalf("expected renamed file to be rolled back")
+	}
+	if data, _ := os.ReadFile(oldA); string(data) != string(originalA) {
+		t.Fatalf("expected original file to be restored, got:\n%s", data)
+	}
+	if data, _ := os.ReadFile(b.Path); string(data) != string(originalB) {
+		t.Fatalf("expected untouched file to keep its content")
+	}
+}
+
+func TestTransactionReportsRollbackFailure(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	a := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", nil)
+	mustWriteFeature(t, fix, a)
+
+	tx := mgr.Begin()
+	if err := tx.Save(a); err != nil {
+		t.Fatalf("stage save failed: %v", err)
+	}
+	tx.Delete(filepath.Join(fix.Root, "missing.md"))
+	orig := txRemoveFile
+	t.Cleanup(func() { txRemoveFile = orig })
+	txRemoveFile = func(string) error { return errors.New("busy") }
+	failWritesTo(t, a.Path)
+
+	err := tx.Commit()
+	if err == nil || !strings.Contains(err.Error(), "rollback failed") {
+		t.Fatalf("expected rollback failure to be reported, got %v", err)
+	}
+}
+
+func TestTransactionCommitErrors(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	tx := mgr.Begin()
+	tx.Delete(fix.Root)
+	if err := tx.Commit(); err == nil || !strings.Contains(err.Error(), "cannot replace directory") {
+		t.Fatalf("expected directory error, got %v", err)
+	}
+
+	missing := filepath.Join(fix.Root, "gone.md")
+	tx.Delete(missing)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("deleting a missing file should succeed: %v", err)
+	}
+}
+
+func TestTransactionDryRun(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, true))
+
+	a := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", nil)
+	mustWriteFeature(t, fix, a)
+	oldA := a.Path
+	b := newTestFeature(fix, "FTR-0002", "backlog", "Beta", nil)
+	mustWriteFeature(t, fix, b)
+	c := newTestFeature(fix, "FTR-0003", "backlog", "Gamma", nil)
+	mustWriteFeature(t, fix, c)
+
+	tx := mgr.Begin()
+	if err := tx.Move(a, filepath.Join(filepath.Dir(a.Path), "FTR-0001-other.md")); err != nil {
+		t.Fatalf("stage move failed: %v", err)
+	}
+	if err := tx.Save(b); err != nil {
+		t.Fatalf("stage save failed: %v", err)
+	}
+	c.FrontMatter.Owner = "someone-else"
+	if err := tx.Save(c); err != nil {
+		t.Fatalf("stage save failed: %v", err)
+	}
+	tx.Delete(fix.Path("features", "missing.md"))
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("dry-run commit failed: %v", err)
+	}
+	if _, err := os.Stat(oldA); err != nil {
+		t.Fatalf("dry-run should not touch disk: %v", err)
+	}
+	if _, err := os.Stat(a.Path); !os.IsNotExist(err) {
+		t.Fatalf("dry-run should not write the new file")
+	}
+
+	// The unchanged save and the delete of a missing file plan nothing.
+	planned := mgr.Patches()
+	if len(planned) != 2 || planned[0].Op != patch.Rename || planned[1].Op != patch.Modify {
+		t.Fatalf("expected a rename and a modify patch, got %+v", planned)
+	}
+	if want := "diff --git a/.virtualboard/features/backlog/FTR-0001-alpha.md b/.virtualboard/features/backlog/FTR-0001-other.md\n" +
+		"rename from .virtualboard/features/backlog/FTR-0001-alpha.md\nrename to .virtualboard/features/backlog/FTR-0001-other.md\n"; planned[0].Diff != want {
+		t.Fatalf("expected a pure rename, got:\n%s", planned[0].Diff)
+	}
+	if !strings.Contains(planned[1].Diff, "+owner: someone-else\n") {
+		t.Fatalf("expected the owner change in the diff:\n%s", planned[1].Diff)
+	}
+}
+
+func TestMoveFeatureRollsBackOnFailure(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", nil)
+	mustWriteFeature(t, fix, feat)
+	oldPath := feat.Path
+	original, _ := os.ReadFile(oldPath)
+
+	orig := txRemoveFile
+	t.Cleanup(func() { txRemoveFile = orig })
+	txRemoveFile = func(path string) error {
+		if path == oldPath {
+			return errors.New("device busy")
+		}
+		return orig(path)
+	}
+
+	if _, _, err := mgr.MoveFeature("FTR-0001", "in-progress", ""); err == nil {
+		t.Fatalf("expected move to fail")
+	}
+	txRemoveFile = orig
+
+	matches, _ := filepath.Glob(filepath.Join(mgr.FeaturesDir(), "*", "FTR-0001-*.md"))
+	if len(matches) != 1 || matches[0] != oldPath {
+		t.Fatalf("expected only the original file to remain, got %v", matches)
+	}
+	if data, _ := os.ReadFile(oldPath); string(data) != string(original) {
+		t.Fatalf("expected original content to be restored")
+	}
+}
+
+func TestRenameToMatchTitleRollsBackOnFailure(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Alpha", nil)
+	mustWriteFeature(t, fix, feat)
+	oldPath := feat.Path
+
+	feat.FrontMatter.Title = "Beta"
+	failWritesTo(t, filepath.Join(filepath.Dir(oldPath), "FTR-0001-beta.md"))
+	if renamed, err := mgr.RenameToMatchTitle(feat); err == nil || renamed {
+		t.Fatalf("expected rename failure, got %v %v", renamed, err)
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("expected original file to remain: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package feature
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// TrashDirName is the workspace directory keeping feature files as they were
+// before an update, move, delete or revert, named by the SHA-256 of their content.
+const TrashDirName = ".trash"
+
+// TrashDir returns the directory of stashed feature snapshots.
+func (m *Manager) TrashDir() string {
+	return filepath.Join(m.opts.RootDir, TrashDirName)
+}
+
+func (m *Manager) snapshotPath(sum string) string {
+	return filepath.Join(m.TrashDir(), sum+".md")
+}
+
+// stash copies the file at path into the trash and returns the SHA-256 of its
+// content, which the audit change set records as the snapshot. Identical content
+// is stored once. Callers stash only once the operation has passed its checks, so
+// rejected operations leave nothing behind. Nothing is written in dry-run mode.
+func (m *Manager) stash(path string) (string, error) {
+	// #nosec G304 -- feature paths are derived from repository structure during discovery
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stash %s: %w", path, err)
+	}
+	sum := contentHash(data)
+	if m.opts.DryRun {
+		return sum, nil
+	}
+	target := m.snapshotPath(sum)
+	if _, err := os.Stat(target); err == nil {
+		return sum, nil
+	}
+	if err := m.ignoreTrash(); err != nil {
+		return "", fmt.Errorf("failed to stash %s: %w", path, err)
+	}
+	if err := util.WriteFileAtomic(target, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to stash %s: %w", path, err)
+	}
+	return sum, nil
+}
+
+// ignoreTrash creates the trash directory with a .gitignore so snapshots, which
+// are local undo history, are never committed.
+func (m *Manager) ignoreTrash() error {
+	dir := m.TrashDir()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, os.ErrNotExist) {
+		if err := util.WriteFileAtomic(ignore, []byte("*\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to write trash .gitignore: %w", err)
+		}
+	}
+	return nil
+}
+
+// Snapshot returns the feature file content stashed under sum. Content that no
+// longer hashes to sum is rejected.
+func (m *Manager) Snapshot(sum string) ([]byte, error) {
+	// #nosec G304 -- the snapshot name is a hex digest within the trash directory
+	data, err := os.ReadFile(m.snapshotPath(filepath.Base(sum)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: snapshot %s is not in %s", ErrNotRevertible, shortHash(sum), TrashDirName)
+		}
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", shortHash(sum), err)
+	}
+	if contentHash(data) != sum {
+		return nil, fmt.Errorf("%w: snapshot %s was modified", ErrNotRevertible, shortHash(sum))
+	}
+	return data, nil
+}
+
+// contentHash returns the hex SHA-256 of a feature file's content, the name of its
+// snapshot and the result recorded in change sets.
+func contentHash(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// shortHash abbreviates a hash for messages, like git's short commit IDs.
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,93 @@
+package feature
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func TestStash(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := NewManager(opts)
+	if mgr.TrashDir() != filepath.Join(opts.RootDir, ".trash") {
+		t.Fatalf("unexpected trash dir %s", mgr.TrashDir())
+	}
+
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Stashed", nil)
+	mustWriteFeature(t, fix, feat)
+	sum, err := mgr.stash(feat.Path)
+	if err != nil || len(sum) != 64 {
+		t.Fatalf("stash failed: %s (%v)", sum, err)
+	}
+	if ignore := readFile(t, filepath.Join(mgr.TrashDir(), ".gitignore")); ignore != "*\n" {
+		t.Fatalf("expected the trash to be git-ignored, got %q", ignore)
+	}
+	if again, err := mgr.stash(feat.Path); err != nil || again != sum {
+		t.Fatalf("expected identical content to share a snapshot, got %s (%v)", again, err)
+	}
+	data, err := mgr.Snapshot(sum)
+	if err != nil || string(data) != readFile(t, feat.Path) {
+		t.Fatalf("unexpected snapshot %q (%v)", data, err)
+	}
+	if _, err := mgr.stash(fix.Path("features", "missing.md")); err == nil || !strings.Contains(err.Error(), "failed to stash") {
+		t.Fatalf("expected a stash error, got %v", err)
+	}
+	if err := os.Remove(mgr.snapshotPath(sum)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(mgr.snapshotPath(sum), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Snapshot(sum); err == nil || !strings.Contains(err.Error(), "failed to read snapshot") {
+		t.Fatalf("expected a read error, got %v", err)
+	}
+
+	opts.DryRun = true
+	feat.FrontMatter.Title = "Changed"
+	mustWriteFeature(t, fix, feat)
+	dry, err := NewManager(opts).stash(feat.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(mgr.snapshotPath(dry)); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not write the snapshot, got %v", err)
+	}
+}
+
+func TestDeleteKeepsFeatureWhenStashFails(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Kept", nil)
+	mustWriteFeature(t, fix, feat)
+
+	if err := os.WriteFile(mgr.TrashDir(), []byte("not a directory"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.DeleteFeature("FTR-0001"); err == nil || !strings.Contains(err.Error(), "failed to stash") {
+		t.Fatalf("expected the delete to fail, got %v", err)
+	}
+	if _, err := os.Stat(feat.Path); err != nil {
+		t.Fatalf("the feature must survive a failed stash: %v", err)
+	}
+}
+
+func TestRejectedMoveLeavesNoSnapshot(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := NewManager(fix.Options(t, false, false, false))
+	feat := newTestFeature(fix, "FTR-0001", "backlog", "Rejected", nil)
+	mustWriteFeature(t, fix, feat)
+
+	if _, _, err := mgr.MoveFeature("FTR-0001", "done", ""); err == nil {
+		t.Fatalf("expected the transition to be rejected")
+	}
+	if results, err := mgr.MoveFeatures([]string{"FTR-0001"}, "done", ""); err == nil && results[0].Success {
+		t.Fatalf("expected the bulk transition to be rejected")
+	}
+	if _, err := os.Stat(mgr.TrashDir()); !os.IsNotExist(err) {
+		t.Fatalf("a rejected move must not stash the feature, got %v", err)
+	}
+}
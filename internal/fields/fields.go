@@ -0,0 +1,261 @@
+// Package fields describes user-defined frontmatter fields and their types.
+package fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Type is the declared type of a custom field.
+type Type string
+
+// Supported field types.
+const (
+	String     Type = "string"
+	Int        Type = "int"
+	Number     Type = "number"
+	Bool       Type = "bool"
+	Date       Type = "date"
+	Enum       Type = "enum"
+	StringList Type = "string[]"
+)
+
+var knownTypes = []Type{String, Int, Number, Bool, Date, Enum, StringList}
+
+// Definition declares a custom frontmatter field.
+type Definition struct {
+	Name   string   `yaml:"-" json:"name"`
+	Type   Type     `yaml:"type" json:"type"`
+	Values []string `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+// UnmarshalYAML accepts both the short form (`due: date`) and the long form
+// (`team: {type: enum, values: [core, platform]}`).
+func (d *Definition) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		d.Type = Type(node.Value)
+		return nil
+	}
+	type plain Definition
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*d = Definition(p)
+	return nil
+}
+
+// Validate checks that the definition is well formed.
+func (d Definition) Validate() error {
+	known := false
+	for _, t := range knownTypes {
+		if d.Type == t {
+			known = true
+			break
+		}
+	}
+	if !known {
+		names := make([]string, len(knownTypes))
+		for i, t := range knownTypes {
+			names[i] = string(t)
+		}
+		return fmt.Errorf("field %s has unknown type %q (allowed: %s)", d.Name, d.Type, strings.Join(names, ", "))
+	}
+	if d.Type == Enum && len(d.Values) == 0 {
+		return fmt.Errorf("enum field %s must list its values", d.Name)
+	}
+	return nil
+}
+
+// Parse converts a command-line string into a typed value for the field.
+func (d Definition) Parse(raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	switch d.Type {
+	case Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("field %s must be an integer", d.Name)
+		}
+		return n, nil
+	case Number:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s must be a number", d.Name)
+		}
+		return f, nil
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("field %s must be true or false", d.Name)
+		}
+		return b, nil
+	case StringList:
+		return SplitList(raw), nil
+	default:
+		if err := d.Check(raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+}
+
+// Check validates a value read from frontmatter against the field type.
+func (d Definition) Check(value interface{}) error {
+	switch d.Type {
+	case Int:
+		if _, ok := value.(int); !ok {
+			return fmt.Errorf("field %s must be an integer", d.Name)
+		}
+	case Number:
+		switch value.(type) {
+		case int, float64:
+		default:
+			return fmt.Errorf("field %s must be a number", d.Name)
+		}
+	case Bool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("field %s must be true or false", d.Name)
+		}
+	case StringList:
+		if _, ok := value.([]string); !ok {
+			return fmt.Errorf("field %s must be a list of strings", d.Name)
+		}
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("field %s must be a string", d.Name)
+		}
+		if d.Type == Date {
+			if _, err := time.Parse("2006-01-02", s); err != nil {
+				return fmt.Errorf("field %s must be a date (YYYY-MM-DD)", d.Name)
+			}
+		}
+		if d.Type == Enum && !contains(d.Values, s) {
+			return fmt.Errorf("field %s must be one of: %s", d.Name, strings.Join(d.Values, ", "))
+		}
+	}
+	return nil
+}
+
+// Format renders a value for tables and indexes.
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// SplitList splits a comma or newline separated list, dropping blanks.
+func SplitList(value string) []string {
+	out := []string{}
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// Set maps field names to their definitions.
+type Set map[string]Definition
+
+// Names returns the declared field names in sorted order.
+func (s Set) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Normalize fills in definition names and validates every definition.
+func (s Set) Normalize() error {
+	for _, name := range s.Names() {
+		def := s[name]
+		def.Name = name
+		if err := def.Validate(); err != nil {
+			return err
+		}
+		s[name] = def
+	}
+	return nil
+}
+
+// Merge returns a new set with other's definitions taking precedence.
+func (s Set) Merge(other Set) Set {
+	merged := Set{}
+	for name, def := range s {
+		merged[name] = def
+	}
+	for name, def := range other {
+		merged[name] = def
+	}
+	return merged
+}
+
+type schemaProperty struct {
+	Type   string   `json:"type"`
+	Format string   `json:"format"`
+	Enum   []string `json:"enum"`
+	Items  *struct {
+		Type string `json:"type"`
+	} `json:"items"`
+}
+
+// FromSchema derives definitions from the properties of a frontmatter JSON schema,
+// skipping the names reported by builtin and properties whose type has no equivalent.
+func FromSchema(data []byte, builtin func(string) bool) (Set, error) {
+	var schema struct {
+		Properties map[string]schemaProperty `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	set := Set{}
+	for name, prop := range schema.Properties {
+		if builtin(name) {
+			continue
+		}
+		def := Definition{Name: name}
+		switch {
+		case prop.Type == "string" && len(prop.Enum) > 0:
+			def.Type, def.Values = Enum, prop.Enum
+		case prop.Type == "string" && prop.Format == "date":
+			def.Type = Date
+		case prop.Type == "string":
+			def.Type = String
+		case prop.Type == "integer":
+			def.Type = Int
+		case prop.Type == "number":
+			def.Type = Number
+		case prop.Type == "boolean":
+			def.Type = Bool
+		case prop.Type == "array" && prop.Items != nil && prop.Items.Type == "string":
+			def.Type = StringList
+		default:
+			continue
+		}
+		set[name] = def
+	}
+	return set, nil
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package fields
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDefinitionUnmarshalYAML(t *testing.T) {
+	var set Set
+	data := "due: date\nteam:\n  type: enum\n  values: [core, platform]\n"
+	if err := yaml.Unmarshal([]byte(data), &set); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if err := set.Normalize(); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if set["due"].Type != Date || set["due"].Name != "due" {
+		t.Fatalf("unexpected short form: %+v", set["due"])
+	}
+	if set["team"].Type != Enum || !reflect.DeepEqual(set["team"].Values, []string{"core", "platform"}) {
+		t.Fatalf("unexpected long form: %+v", set["team"])
+	}
+
+	if err := yaml.Unmarshal([]byte("team:\n  type: [x]\n"), &set); err == nil {
+		t.Fatalf("expected error for malformed long form")
+	}
+}
+
+func TestSetNormalizeErrors(t *testing.T) {
+	if err := (Set{"size": {Type: "huge"}}).Normalize(); err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Fatalf("expected unknown type error, got %v", err)
+	}
+	if err := (Set{"team": {Type: Enum}}).Normalize(); err == nil || !strings.Contains(err.Error(), "must list its values") {
+		t.Fatalf("expected enum values error, got %v", err)
+	}
+}
+
+func TestDefinitionParse(t *testing.T) {
+	cases := []struct {
+		def  Definition
+		raw  string
+		want interface{}
+	}{
+		{Definition{Type: String}, " hello ", "hello"},
+		{Definition{Type: Int}, "3", 3},
+		{Definition{Type: Number}, "1.5", 1.5},
+		{Definition{Type: Bool}, "true", true},
+		{Definition{Type: Date}, "2026-03-01", "2026-03-01"},
+		{Definition{Type: Enum, Values: []string{"core"}}, "core", "core"},
+		{Definition{Type: StringList}, "acme, globex,", []string{"acme", "globex"}},
+	}
+	for _, tc := range cases {
+		got, err := tc.def.Parse(tc.raw)
+		if err != nil {
+			t.Fatalf("parse %s %q failed: %v", tc.def.Type, tc.raw, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("parse %s %q: expected %#v, got %#v", tc.def.Type, tc.raw, tc.want, got)
+		}
+	}
+
+	for _, def := range []Definition{{Type: Int}, {Type: Number}, {Type: Bool}, {Type: Date}, {Type: Enum, Values: []string{"core"}}} {
+		if _, err := def.Parse("nope"); err == nil {
+			t.Fatalf("expected parse error for %s", def.Type)
+		}
+	}
+}
+
+func TestDefinitionCheck(t *testing.T) {
+	valid := []struct {
+		def   Definition
+		value interface{}
+	}{
+		{Definition{Type: String}, "x"},
+		{Definition{Type: Int}, 2},
+		{Definition{Type: Number}, 2},
+		{Definition{Type: Number}, 2.5},
+		{Definition{Type: Bool}, false},
+		{Definition{Type: Date}, "2026-01-01"},
+		{Definition{Type: Enum, Values: []string{"a"}}, "a"},
+		{Definition{Type: StringList}, []string{"a"}},
+	}
+	for _, tc := range valid {
+		if err := tc.def.Check(tc.value); err != nil {
+			t.Fatalf("expected %v to be a valid %s: %v", tc.value, tc.def.Type, err)
+		}
+	}
+
+	invalid := []struct {
+		def   Definition
+		value interface{}
+	}{
+		{Definition{Type: String}, 3},
+		{Definition{Type: Int}, "3"},
+		{Definition{Type: Number}, "3"},
+		{Definition{Type: Bool}, "yes"},
+		{Definition{Type: Date}, "March"},
+		{Definition{Type: Enum, Values: []string{"a"}}, "b"},
+		{Definition{Type: StringList}, "a"},
+	}
+	for _, tc := range invalid {
+		if err := tc.def.Check(tc.value); err == nil {
+			t.Fatalf("expected %v to be an invalid %s", tc.value, tc.def.Type)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if Format(nil) != "" || Format([]string{"a", "b"}) != "a, b" || Format(3) != "3" || Format("x") != "x" {
+		t.Fatalf("unexpected formatting")
+	}
+}
+
+func TestSetMergeAndNames(t *testing.T) {
+	base := Set{"due": {Type: String}, "team": {Type: String}}
+	merged := base.Merge(Set{"due": {Type: Date}})
+	if merged["due"].Type != Date || merged["team"].Type != String || base["due"].Type != String {
+		t.Fatalf("unexpected merge: %+v", merged)
+	}
+	if names := merged.Names(); !reflect.DeepEqual(names, []string{"due", "team"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+}
+
+func TestFromSchema(t *testing.T) {
+	schema := `{"properties": {
+  "id": {"type": "string"},
+  "due": {"type": "string", "format": "date"},
+  "team": {"type": "string", "enum": ["core", "platform"]},
+  "jira": {"type": "string"},
+  "estimate": {"type": "integer"},
+  "cost": {"type": "number"},
+  "billable": {"type": "boolean"},
+  "customers": {"type": "array", "items": {"type": "string"}},
+  "meta": {"type": "object"}
+}}`
+	set, err := FromSchema([]byte(schema), func(name string) bool { return name == "id" })
+	if err != nil {
+		t.Fatalf("from schema failed: %v", err)
+	}
+	want := map[string]Type{"due": Date, "team": Enum, "jira": String, "estimate": Int, "cost": Number, "billable": Bool, "customers": StringList}
+	if len(set) != len(want) {
+		t.Fatalf("unexpected fields: %v", set.Names())
+	}
+	for name, typ := range want {
+		if set[name].Type != typ || set[name].Name != name {
+			t.Fatalf("field %s: expected %s, got %+v", name, typ, set[name])
+		}
+	}
+
+	if _, err := FromSchema([]byte("{"), func(string) bool { return false }); err == nil {
+		t.Fatalf("expected error for invalid schema")
+	}
+}
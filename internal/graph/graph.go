@@ -0,0 +1,349 @@
+// Package graph models feature dependencies as a directed graph and answers
+// planning questions about it: cycles, topological order, the critical path to a
+// feature and which items are ready to start.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+// CycleError reports dependency cycles that prevent an ordering.
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		parts[i] = strings.Join(cycle, " -> ")
+	}
+	return "circular dependency detected: " + strings.Join(parts, "; ")
+}
+
+// Graph holds every feature of a workspace and a view over a subset of them. Edges
+// point from a feature to the features it depends on. Dependency facts such as
+// whether a dependency is done always consult the full set, so filtering the view
+// never makes a blocked feature look ready.
+type Graph struct {
+	wf       *workflow.Workflow
+	all      map[string]*feature.Feature
+	deps     map[string][]string
+	inView   map[string]bool
+	viewIDs  []string
+	children map[string][]string
+}
+
+// New builds a graph over features, all of which are in view. When two features
+// share an ID the first one wins.
+func New(features []*feature.Feature, wf *workflow.Workflow) *Graph {
+	all := make(map[string]*feature.Feature, len(features))
+	deps := make(map[string][]string, len(features))
+	for _, feat := range features {
+		id := feat.FrontMatter.ID
+		if _, dup := all[id]; dup {
+			continue
+		}
+		all[id] = feat
+		seen := map[string]bool{}
+		list := []string{}
+		for _, dep := range feat.FrontMatter.Dependencies {
+			dep = strings.TrimSpace(dep)
+			if dep != "" && !seen[dep] {
+				seen[dep] = true
+				list = append(list, dep)
+			}
+		}
+		sort.Strings(list)
+		deps[id] = list
+	}
+	children := map[string][]string{}
+	for id, list := range deps {
+		for _, dep := range list {
+			children[dep] = append(children[dep], id)
+		}
+	}
+	for dep := range children {
+		sort.Strings(children[dep])
+	}
+	g := &Graph{wf: wf, all: all, deps: deps, children: children}
+	return g.Filter(nil)
+}
+
+// Filter returns a graph whose view holds the features for which keep returns true
+// (every feature when keep is nil).
+func (g *Graph) Filter(keep func(*feature.Feature) bool) *Graph {
+	view := &Graph{wf: g.wf, all: g.all, deps: g.deps, children: g.children, inView: map[string]bool{}}
+	for id, feat := range g.all {
+		if keep == nil || keep(feat) {
+			view.inView[id] = true
+			view.viewIDs = append(view.viewIDs, id)
+		}
+	}
+	sort.Strings(view.viewIDs)
+	return view
+}
+
+// IDs returns the IDs in view, sorted.
+func (g *Graph) IDs() []string {
+	return append([]string{}, g.viewIDs...)
+}
+
+// Feature returns a feature by ID, whether or not it is in view.
+func (g *Graph) Feature(id string) (*feature.Feature, bool) {
+	feat, ok := g.all[id]
+	return feat, ok
+}
+
+// InView reports whether id is part of the current view.
+func (g *Graph) InView(id string) bool {
+	return g.inView[id]
+}
+
+// Dependencies returns the sorted, de-duplicated dependencies of id, including
+// ones that are missing or outside the view.
+func (g *Graph) Dependencies(id string) []string {
+	return append([]string{}, g.deps[id]...)
+}
+
+// Dependents returns the sorted IDs of features that depend directly on id.
+func (g *Graph) Dependents(id string) []string {
+	return append([]string{}, g.children[id]...)
+}
+
+// Impact describes a feature that depends on another, directly or transitively.
+type Impact struct {
+	ID string
+	// Depth is 1 for direct dependents, 2 for their dependents, and so on.
+	Depth int
+	// Via is the dependency through which the feature is first reached.
+	Via string
+}
+
+// Downstream walks reverse dependency edges from id and returns every feature that
+// depends on it, each at its shortest depth, ordered by depth and then ID. A
+// maxDepth of zero or less walks the whole graph.
+func (g *Graph) Downstream(id string, maxDepth int) []Impact {
+	impacts := []Impact{}
+	seen := map[string]bool{id: true}
+	frontier := []string{id}
+	for depth := 1; len(frontier) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		next := []string{}
+		for _, parent := range frontier {
+			for _, child := range g.children[parent] {
+				if seen[child] {
+					continue
+				}
+				seen[child] = true
+				impacts = append(impacts, Impact{ID: child, Depth: depth, Via: parent})
+				next = append(next, child)
+			}
+		}
+		sort.Strings(next)
+		frontier = next
+	}
+	sort.SliceStable(impacts, func(i, j int) bool {
+		if impacts[i].Depth != impacts[j].Depth {
+			return impacts[i].Depth < impacts[j].Depth
+		}
+		return impacts[i].ID < impacts[j].ID
+	})
+	return impacts
+}
+
+// RequiresDone reports whether id sits in a status that requires its dependencies
+// to be done, so it would become invalid if one of them left done.
+func (g *Graph) RequiresDone(id string) bool {
+	feat, ok := g.all[id]
+	return ok && g.wf.RequiresDoneDependencies(feat.FrontMatter.Status)
+}
+
+// Finished reports whether id exists and is in the workflow's done status.
+func (g *Graph) Finished(id string) bool {
+	feat, ok := g.all[id]
+	return ok && g.wf.IsDone(feat.FrontMatter.Status)
+}
+
+// Cycles returns every dependency cycle among the features in view, each listed
+// as a path that ends where it starts. The result is deterministic.
+func (g *Graph) Cycles() [][]string {
+	visited := map[string]bool{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	cycles := [][]string{}
+	seen := map[string]bool{}
+
+	var dfs func(string)
+	dfs = func(node string) {
+		visited[node] = true
+		onStack[node] = true
+		stack = append(stack, node)
+		for _, dep := range g.deps[node] {
+			if !g.inView[dep] {
+				continue
+			}
+			if !visited[dep] {
+				dfs(dep)
+			} else if onStack[dep] {
+				cycle := extractCycle(stack, dep)
+				if key := strings.Join(cycle, "->"); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		onStack[node] = false
+		stack = stack[:len(stack)-1]
+	}
+
+	for _, node := range g.viewIDs {
+		if !visited[node] {
+			dfs(node)
+		}
+	}
+	return cycles
+}
+
+func extractCycle(stack []string, start string) []string {
+	idx := len(stack) - 1
+	for idx > 0 && stack[idx] != start {
+		idx--
+	}
+	cycle := append([]string{}, stack[idx:]...)
+	return append(cycle, start)
+}
+
+// TopoOrder returns the features in view with every dependency before the
+// features that need it; ties are broken by ID. Features caught in or behind a
+// cycle are left out and a *CycleError is returned alongside the partial order.
+func (g *Graph) TopoOrder() ([]string, error) {
+	pending := map[string]int{}
+	for _, id := range g.viewIDs {
+		for _, dep := range g.deps[id] {
+			if g.inView[dep] {
+				pending[id]++
+			}
+		}
+	}
+	ready := []string{}
+	for _, id := range g.viewIDs {
+		if pending[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]string, 0, len(g.viewIDs))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		for _, child := range g.children[id] {
+			if !g.inView[child] {
+				continue
+			}
+			pending[child]--
+			if pending[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	if len(order) < len(g.viewIDs) {
+		return order, &CycleError{Cycles: g.Cycles()}
+	}
+	return order, nil
+}
+
+// Ready returns the features in view that sit in the workflow's initial status and
+// whose dependencies all exist and are done, sorted by ID.
+func (g *Graph) Ready() []string {
+	ready := []string{}
+	for _, id := range g.viewIDs {
+		if g.isReady(id) {
+			ready = append(ready, id)
+		}
+	}
+	return ready
+}
+
+func (g *Graph) isReady(id string) bool {
+	if !strings.EqualFold(g.all[id].FrontMatter.Status, g.wf.Initial) {
+		return false
+	}
+	for _, dep := range g.deps[id] {
+		if !g.Finished(dep) {
+			return false
+		}
+	}
+	return true
+}
+
+// CriticalPath returns the longest chain of unfinished work that ends at target,
+// ordered from the first item to start to target itself. Missing dependencies count
+// as unfinished. With an empty target it returns the longest such chain ending
+// anywhere in the view. A finished target yields an empty path.
+func (g *Graph) CriticalPath(target string) ([]string, error) {
+	if target != "" {
+		if _, ok := g.all[target]; !ok {
+			return nil, fmt.Errorf("%w: %s", feature.ErrNotFound, target)
+		}
+	}
+
+	length := map[string]int{}
+	next := map[string]string{}
+	onStack := map[string]bool{}
+	var walk func(string, []string) error
+	walk = func(id string, path []string) error {
+		if _, done := length[id]; done {
+			return nil
+		}
+		if onStack[id] {
+			return &CycleError{Cycles: [][]string{extractCycle(path, id)}}
+		}
+		onStack[id] = true
+		path = append(path, id)
+		best, bestDep := 0, ""
+		for _, dep := range g.deps[id] {
+			if g.Finished(dep) {
+				continue
+			}
+			if err := walk(dep, path); err != nil {
+				return err
+			}
+			if length[dep] > best {
+				best, bestDep = length[dep], dep
+			}
+		}
+		onStack[id] = false
+		length[id] = best + 1
+		next[id] = bestDep
+		return nil
+	}
+
+	candidates := []string{target}
+	if target == "" {
+		candidates = g.viewIDs
+	}
+	end, longest := "", 0
+	for _, id := range candidates {
+		if g.Finished(id) {
+			continue
+		}
+		if err := walk(id, nil); err != nil {
+			return nil, err
+		}
+		if length[id] > longest {
+			end, longest = id, length[id]
+		}
+	}
+
+	path := []string{}
+	for id := end; id != ""; id = next[id] {
+		path = append([]string{id}, path...)
+	}
+	return path, nil
+}
@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+func feat(id, status string, deps ...string) *feature.Feature {
+	return &feature.Feature{FrontMatter: feature.FrontMatter{ID: id, Title: "Title " + id, Status: status, Dependencies: deps}}
+}
+
+// testGraph: A(done) <- B(backlog) <- D(backlog) <- E(in-progress)
+//
+//	C(backlog, needs missing X)   F(backlog, needs A)
+func testGraph() *Graph {
+	return New([]*feature.Feature{
+		feat("A", "done"),
+		feat("B", "backlog", "A"),
+		feat("C", "backlog", "X", " "),
+		feat("D", "backlog", "B", "B"),
+		feat("E", "in-progress", "D", "A"),
+		feat("F", "backlog", "A"),
+		feat("A", "backlog"),
+	}, workflow.Default())
+}
+
+func TestGraphBasics(t *testing.T) {
+	g := testGraph()
+	if !reflect.DeepEqual(g.IDs(), []string{"A", "B", "C", "D", "E", "F"}) {
+		t.Fatalf("unexpected ids %v", g.IDs())
+	}
+	if got := g.Dependencies("D"); !reflect.DeepEqual(got, []string{"B"}) {
+		t.Fatalf("expected de-duplicated dependencies, got %v", got)
+	}
+	if got := g.Dependencies("C"); !reflect.DeepEqual(got, []string{"X"}) {
+		t.Fatalf("expected blank dependencies to be dropped, got %v", got)
+	}
+	if got := g.Dependents("A"); !reflect.DeepEqual(got, []string{"B", "E", "F"}) {
+		t.Fatalf("unexpected dependents %v", got)
+	}
+	if f, ok := g.Feature("A"); !ok || f.FrontMatter.Status != "done" {
+		t.Fatalf("expected first feature with an ID to win")
+	}
+	if !g.Finished("A") || g.Finished("B") || g.Finished("X") {
+		t.Fatalf("unexpected finished state")
+	}
+	if !reflect.DeepEqual(g.Ready(), []string{"B", "F"}) {
+		t.Fatalf("unexpected ready %v", g.Ready())
+	}
+}
+
+func TestTopoOrder(t *testing.T) {
+	order, err := testGraph().TopoOrder()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"A", "B", "C", "D", "E", "F"}) {
+		t.Fatalf("unexpected order %v", order)
+	}
+
+	g := New([]*feature.Feature{
+		feat("A", "backlog", "B"),
+		feat("B", "backlog", "A"),
+		feat("C", "backlog"),
+		feat("D", "backlog", "A"),
+	}, workflow.Default())
+	order, err = g.TopoOrder()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) || !reflect.DeepEqual(order, []string{"C"}) {
+		t.Fatalf("expected partial order and cycle error, got %v %v", order, err)
+	}
+	if !reflect.DeepEqual(cycleErr.Cycles, [][]string{{"A", "B", "A"}}) {
+		t.Fatalf("unexpected cycles %v", cycleErr.Cycles)
+	}
+	if err.Error() != "circular dependency detected: A -> B -> A" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
+
+func TestCycles(t *testing.T) {
+	g := New([]*feature.Feature{
+		feat("A", "backlog", "B"),
+		feat("B", "backlog", "C"),
+		feat("C", "backlog", "A"),
+		feat("D", "backlog", "D"),
+	}, workflow.Default())
+	want := [][]string{{"A", "B", "C", "A"}, {"D", "D"}}
+	if got := g.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	filtered := g.Filter(func(f *feature.Feature) bool { return f.FrontMatter.ID != "C" })
+	if got := filtered.Cycles(); !reflect.DeepEqual(got, [][]string{{"D", "D"}}) {
+		t.Fatalf("expected cycles through hidden nodes to be ignored, got %v", got)
+	}
+}
+
+func TestFilterKeepsDependencyFacts(t *testing.T) {
+	g := testGraph().Filter(func(f *feature.Feature) bool { return f.FrontMatter.ID != "A" })
+	if g.InView("A") || !g.InView("B") {
+		t.Fatalf("unexpected view")
+	}
+	if !reflect.DeepEqual(g.Ready(), []string{"B", "F"}) {
+		t.Fatalf("expected readiness to consult hidden dependencies, got %v", g.Ready())
+	}
+	order, err := g.TopoOrder()
+	if err != nil || !reflect.DeepEqual(order, []string{"B", "C", "D", "E", "F"}) {
+		t.Fatalf("unexpected filtered order %v %v", order, err)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := testGraph()
+	path, err := g.CriticalPath("E")
+	if err != nil || !reflect.DeepEqual(path, []string{"B", "D", "E"}) {
+		t.Fatalf("unexpected path to E: %v %v", path, err)
+	}
+	if path, err = g.CriticalPath("C"); err != nil || !reflect.DeepEqual(path, []string{"X", "C"}) {
+		t.Fatalf("expected missing dependency on the path: %v %v", path, err)
+	}
+	if path, err = g.CriticalPath("A"); err != nil || len(path) != 0 {
+		t.Fatalf("expected empty path for a finished target: %v %v", path, err)
+	}
+	if path, err = g.CriticalPath(""); err != nil || !reflect.DeepEqual(path, []string{"B", "D", "E"}) {
+		t.Fatalf("unexpected overall critical path: %v %v", path, err)
+	}
+	if _, err = g.CriticalPath("Z"); !errors.Is(err, feature.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	cyclic := New([]*feature.Feature{
+		feat("A", "backlog", "B"),
+		feat("B", "backlog", "C"),
+		feat("C", "backlog", "B"),
+	}, workflow.Default())
+	var cycleErr *CycleError
+	if _, err = cyclic.CriticalPath("A"); !errors.As(err, &cycleErr) || !reflect.DeepEqual(cycleErr.Cycles, [][]string{{"B", "C", "B"}}) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestDownstream(t *testing.T) {
+	g := testGraph()
+	want := []Impact{
+		{ID: "B", Depth: 1, Via: "A"},
+		{ID: "E", Depth: 1, Via: "A"},
+		{ID: "F", Depth: 1, Via: "A"},
+		{ID: "D", Depth: 2, Via: "B"},
+	}
+	if got := g.Downstream("A", 0); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := g.Downstream("A", 1); len(got) != 3 {
+		t.Fatalf("expected depth limit to apply, got %v", got)
+	}
+	if got := g.Downstream("X", 0); !reflect.DeepEqual(got, []Impact{{ID: "C", Depth: 1, Via: "X"}}) {
+		t.Fatalf("expected dependents of a missing feature, got %v", got)
+	}
+
+	cyclic := New([]*feature.Feature{feat("A", "backlog", "B"), feat("B", "backlog", "A")}, workflow.Default())
+	if got := cyclic.Downstream("A", 0); !reflect.DeepEqual(got, []Impact{{ID: "B", Depth: 1, Via: "A"}}) {
+		t.Fatalf("expected cycles to terminate, got %v", got)
+	}
+
+	if !g.RequiresDone("E") || g.RequiresDone("B") || g.RequiresDone("X") {
+		t.Fatalf("unexpected RequiresDone")
+	}
+}
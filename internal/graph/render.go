@@ -0,0 +1,202 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Node kinds used to style rendered graphs.
+const (
+	KindDone     = "done"
+	KindReady    = "ready"
+	KindOpen     = "open"
+	KindExternal = "external"
+	KindMissing  = "missing"
+)
+
+// Edge points from a dependency to the feature that needs it, i.e. in the
+// direction work flows.
+type Edge struct {
+	From string
+	To   string
+}
+
+// Kind classifies id for rendering: missing, outside the view, done, ready to start,
+// or any other open item.
+func (g *Graph) Kind(id string) string {
+	switch {
+	case g.all[id] == nil:
+		return KindMissing
+	case !g.inView[id]:
+		return KindExternal
+	case g.Finished(id):
+		return KindDone
+	case g.isReady(id):
+		return KindReady
+	}
+	return KindOpen
+}
+
+// Edges returns every dependency edge into a feature in view, sorted. The
+// dependency end may be missing or outside the view.
+func (g *Graph) Edges() []Edge {
+	edges := []Edge{}
+	for _, id := range g.viewIDs {
+		for _, dep := range g.deps[id] {
+			edges = append(edges, Edge{From: dep, To: id})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// renderNodes returns the view plus every node referenced by an edge.
+func (g *Graph) renderNodes(edges []Edge) []string {
+	seen := map[string]bool{}
+	nodes := []string{}
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			nodes = append(nodes, id)
+		}
+	}
+	for _, id := range g.viewIDs {
+		add(id)
+	}
+	for _, e := range edges {
+		add(e.From)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func (g *Graph) label(id string) (title, status string) {
+	if feat, ok := g.all[id]; ok {
+		return feat.FrontMatter.Title, feat.FrontMatter.Status
+	}
+	return "", KindMissing
+}
+
+func onPath(path []string) (map[string]bool, map[Edge]bool) {
+	nodes := map[string]bool{}
+	edges := map[Edge]bool{}
+	for i, id := range path {
+		nodes[id] = true
+		if i > 0 {
+			edges[Edge{From: path[i-1], To: id}] = true
+		}
+	}
+	return nodes, edges
+}
+
+var dotStyles = map[string]string{
+	KindDone:     `style="rounded,filled", fillcolor="#d4edda"`,
+	KindReady:    `style="rounded,filled", fillcolor="#cce5ff"`,
+	KindOpen:     `style="rounded"`,
+	KindExternal: `style="rounded,dashed"`,
+	KindMissing:  `style="dashed", color="#d9534f"`,
+}
+
+// WriteDOT renders the graph in Graphviz DOT. Nodes and edges on path, typically a
+// critical path, are drawn in red.
+func (g *Graph) WriteDOT(w io.Writer, path []string) error {
+	pathNodes, pathEdges := onPath(path)
+	edges := g.Edges()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph features {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	fmt.Fprintln(bw, `  node [shape=box, fontname="Helvetica"];`)
+	for _, id := range g.renderNodes(edges) {
+		title, status := g.label(id)
+		label := id
+		if title != "" {
+			label += `\n` + title
+		}
+		label += `\n(` + status + `)`
+		attrs := fmt.Sprintf("label=%s, %s", dotQuote(label), dotStyles[g.Kind(id)])
+		if pathNodes[id] {
+			attrs += `, penwidth=2, color="#d9534f"`
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", dotQuote(id), attrs)
+	}
+	for _, e := range edges {
+		attrs := ""
+		if pathEdges[e] {
+			attrs = ` [color="#d9534f", penwidth=2]`
+		}
+		fmt.Fprintf(bw, "  %s -> %s%s;\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotQuote quotes s as a DOT string, keeping \n escapes used for line breaks.
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart. Nodes and edges on path
+// are highlighted.
+func (g *Graph) WriteMermaid(w io.Writer, path []string) error {
+	pathNodes, pathEdges := onPath(path)
+	edges := g.Edges()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart LR")
+
+	classes := map[string][]string{}
+	for _, id := range g.renderNodes(edges) {
+		title, status := g.label(id)
+		label := id
+		if title != "" {
+			label += ": " + title
+		}
+		label += "<br/>" + status
+		fmt.Fprintf(bw, "  %s[\"%s\"]\n", mermaidID(id), strings.ReplaceAll(label, `"`, "#quot;"))
+		kind := g.Kind(id)
+		classes[kind] = append(classes[kind], mermaidID(id))
+		if pathNodes[id] {
+			classes["critical"] = append(classes["critical"], mermaidID(id))
+		}
+	}
+
+	highlighted := []string{}
+	for i, e := range edges {
+		fmt.Fprintf(bw, "  %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+		if pathEdges[e] {
+			highlighted = append(highlighted, fmt.Sprint(i))
+		}
+	}
+
+	fmt.Fprintln(bw, "  classDef done fill:#d4edda,stroke:#28a745")
+	fmt.Fprintln(bw, "  classDef ready fill:#cce5ff,stroke:#004085")
+	fmt.Fprintln(bw, "  classDef external stroke-dasharray:5 5")
+	fmt.Fprintln(bw, "  classDef missing stroke:#d9534f,stroke-dasharray:5 5")
+	fmt.Fprintln(bw, "  classDef critical stroke:#d9534f,stroke-width:3px")
+	for _, kind := range []string{KindDone, KindReady, KindExternal, KindMissing, "critical"} {
+		if ids := classes[kind]; len(ids) > 0 {
+			fmt.Fprintf(bw, "  class %s %s\n", strings.Join(ids, ","), kind)
+		}
+	}
+	if len(highlighted) > 0 {
+		fmt.Fprintf(bw, "  linkStyle %s stroke:#d9534f,stroke-width:3px\n", strings.Join(highlighted, ","))
+	}
+	return bw.Flush()
+}
+
+// mermaidID turns a feature ID into a Mermaid-safe node identifier.
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("boom") }
+
+func TestKindAndEdges(t *testing.T) {
+	g := testGraph().Filter(func(f *feature.Feature) bool { return f.FrontMatter.ID != "B" })
+	kinds := map[string]string{"A": KindDone, "B": KindExternal, "C": KindOpen, "E": KindOpen, "F": KindReady, "X": KindMissing}
+	for id, want := range kinds {
+		if got := g.Kind(id); got != want {
+			t.Fatalf("%s: expected %s, got %s", id, want, got)
+		}
+	}
+	want := []Edge{{"A", "E"}, {"A", "F"}, {"B", "D"}, {"D", "E"}, {"X", "C"}}
+	if got := g.Edges(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := New([]*feature.Feature{
+		feat("FTR-1", "done"),
+		{FrontMatter: feature.FrontMatter{ID: "FTR-2", Title: `Say "hi"`, Status: "backlog", Dependencies: []string{"FTR-1", "FTR-9"}}},
+	}, testGraph().wf)
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf, []string{"FTR-1", "FTR-2"}); err != nil {
+		t.Fatalf("dot failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"digraph features {",
+		`"FTR-1" [label="FTR-1\nTitle FTR-1\n(done)", style="rounded,filled", fillcolor="#d4edda", penwidth=2, color="#d9534f"];`,
+		`"FTR-2" [label="FTR-2\nSay \"hi\"\n(backlog)", style="rounded", penwidth=2`,
+		`"FTR-9" [label="FTR-9\n(missing)", style="dashed", color="#d9534f"];`,
+		`"FTR-1" -> "FTR-2" [color="#d9534f", penwidth=2];`,
+		`"FTR-9" -> "FTR-2";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+	if err := g.WriteDOT(failingWriter{}, nil); err == nil {
+		t.Fatalf("expected write error")
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	g := testGraph().Filter(func(f *feature.Feature) bool { return f.FrontMatter.ID != "A" })
+	var buf bytes.Buffer
+	if err := g.WriteMermaid(&buf, []string{"B", "D", "E"}); err != nil {
+		t.Fatalf("mermaid failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`  A["A: Title A<br/>done"]`,
+		`  X["X<br/>missing"]`,
+		"  A --> E\n",
+		"  class B,F ready\n",
+		"  class A external\n",
+		"  class X missing\n",
+		"  class B,D,E critical\n",
+		"  linkStyle 3,4 stroke:#d9534f,stroke-width:3px\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "linkStyle") && strings.Count(out, "linkStyle") != 1 {
+		t.Fatalf("expected a single linkStyle line")
+	}
+	if got := mermaidID("FTR-0001.a"); got != "FTR_0001_a" {
+		t.Fatalf("unexpected mermaid id %s", got)
+	}
+
+	buf.Reset()
+	if err := New(nil, g.wf).WriteMermaid(&buf, nil); err != nil || strings.Contains(buf.String(), "class ") || strings.Contains(buf.String(), "linkStyle") {
+		t.Fatalf("expected bare flowchart for an empty graph: %v\n%s", err, buf.String())
+	}
+}
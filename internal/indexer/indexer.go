@@ -10,7 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/virtualboard/vb-cli/internal/epic"
 	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/fields"
 )
 
 // Entry represents a single feature within the index.
@@ -22,20 +24,45 @@ type Entry struct {
 	Priority   string   `json:"priority"`
 	Complexity string   `json:"complexity"`
 	Labels     []string `json:"labels"`
+	Epic       string   `json:"epic,omitempty"`
 	Updated    string   `json:"updated"`
 	Path       string   `json:"path"`
+	// Checklist is the progress of the feature's task list items, if it has any.
+	Checklist *feature.Checklist `json:"checklist,omitempty"`
+	// Custom holds the formatted values of the generator's extra columns.
+	Custom map[string]string `json:"custom,omitempty"`
 }
 
 // Data is the structured representation of the index.
 type Data struct {
 	Generated string         `json:"generated"`
+	Columns   []string       `json:"columns,omitempty"`
 	Features  []Entry        `json:"features"`
+	Epics     []EpicGroup    `json:"epics,omitempty"`
 	Summary   map[string]int `json:"summary"`
 }
 
+// EpicGroup rolls up the indexed features that belong to one epic.
+type EpicGroup struct {
+	ID       string        `json:"id"`
+	Title    string        `json:"title"`
+	Status   string        `json:"status,omitempty"`
+	Defined  bool          `json:"defined"`
+	Progress epic.Progress `json:"progress"`
+	Features []string      `json:"features"`
+}
+
 // Generator produces indexes in multiple formats.
 type Generator struct {
 	mgr *feature.Manager
+	// Columns are custom frontmatter fields appended to each entry after the built-in columns.
+	Columns []string
+	// Select, when set, narrows the features that are indexed.
+	Select func([]*feature.Feature) []*feature.Feature
+	// GroupByEpic groups entries under their epic. Defined epics come first, in
+	// the order of Epics, followed by epics that features reference but nobody defined.
+	GroupByEpic bool
+	Epics       []*epic.Epic
 }
 
 // NewGenerator constructs a new generator.
@@ -49,6 +76,9 @@ func (g *Generator) Build() (*Data, error) {
 	if err != nil {
 		return nil, err
 	}
+	if g.Select != nil {
+		features = g.Select(features)
+	}
 
 	entries := make([]Entry, 0, len(features))
 	summary := map[string]int{}
@@ -67,9 +97,19 @@ func (g *Generator) Build() (*Data, error) {
 			Priority:   feat.FrontMatter.Priority,
 			Complexity: feat.FrontMatter.Complexity,
 			Labels:     feat.FrontMatter.Labels,
+			Epic:       strings.TrimSpace(feat.FrontMatter.Epic),
 			Updated:    feat.FrontMatter.Updated,
 			Path:       filepath.ToSlash(rel),
 		}
+		if checklist := feat.ChecklistTotal(); checklist.Total > 0 {
+			entry.Checklist = &checklist
+		}
+		if len(g.Columns) > 0 {
+			entry.Custom = make(map[string]string, len(g.Columns))
+			for _, column := range g.Columns {
+				entry.Custom[column] = fields.Format(feat.FrontMatter.Custom[column])
+			}
+		}
 		entries = append(entries, entry)
 		summary[strings.ToLower(entry.Status)]++
 	}
@@ -78,11 +118,110 @@ func (g *Generator) Build() (*Data, error) {
 		return entries[i].ID < entries[j].ID
 	})
 
-	return &Data{
+	data := &Data{
 		Generated: time.Now().Format("2006-01-02"),
+		Columns:   g.Columns,
 		Features:  entries,
 		Summary:   summary,
-	}, nil
+	}
+	if g.GroupByEpic {
+		data.Epics = g.groupByEpic(features, entries)
+	}
+	return data, nil
+}
+
+func (g *Generator) groupByEpic(features []*feature.Feature, entries []Entry) []EpicGroup {
+	rollup := epic.Rollup(features, g.mgr.Workflow())
+	groups := []EpicGroup{}
+	index := map[string]int{}
+	for _, e := range g.Epics {
+		index[epic.Key(e.FrontMatter.ID)] = len(groups)
+		groups = append(groups, EpicGroup{
+			ID:      e.FrontMatter.ID,
+			Title:   e.FrontMatter.Title,
+			Status:  e.FrontMatter.Status,
+			Defined: true,
+		})
+	}
+	var undefined []EpicGroup
+	for _, entry := range entries {
+		key := epic.Key(entry.Epic)
+		if key == "" {
+			continue
+		}
+		if _, ok := index[key]; !ok {
+			index[key] = -1 - len(undefined)
+			undefined = append(undefined, EpicGroup{ID: entry.Epic, Title: entry.Epic})
+		}
+	}
+	sort.Slice(undefined, func(i, j int) bool {
+		return epic.Key(undefined[i].ID) < epic.Key(undefined[j].ID)
+	})
+	groups = append(groups, undefined...)
+
+	for i := range groups {
+		key := epic.Key(groups[i].ID)
+		groups[i].Progress = epic.Progress{ByStatus: map[string]int{}}
+		if p, ok := rollup[key]; ok {
+			groups[i].Progress = *p
+		}
+		groups[i].Features = []string{}
+		for _, entry := range entries {
+			if epic.Key(entry.Epic) == key {
+				groups[i].Features = append(groups[i].Features, entry.ID)
+			}
+		}
+	}
+	return groups
+}
+
+// Tasks renders the entry's checklist progress, or "" when it has no task items.
+func (e Entry) Tasks() string {
+	if e.Checklist == nil {
+		return ""
+	}
+	return e.Checklist.String()
+}
+
+// Ungrouped returns the entries that belong to none of the data's epic groups.
+func (d *Data) Ungrouped() []Entry {
+	grouped := map[string]bool{}
+	for _, group := range d.Epics {
+		for _, id := range group.Features {
+			grouped[id] = true
+		}
+	}
+	out := []Entry{}
+	for _, entry := range d.Features {
+		if !grouped[entry.ID] {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// entriesFor returns the entries whose IDs are listed, in index order.
+func (d *Data) entriesFor(ids []string) []Entry {
+	want := map[string]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
+	out := []Entry{}
+	for _, entry := range d.Features {
+		if want[entry.ID] {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// Heading describes the group for index section titles.
+func (g EpicGroup) Heading() string {
+	label := fmt.Sprintf("%s (%s)", g.Title, g.ID)
+	if !g.Defined {
+		label = fmt.Sprintf("%s (undefined)", g.ID)
+	}
+	return fmt.Sprintf("Epic: %s — %d/%d done (%d%%)", label, g.Progress.Done, g.Progress.Total, g.Progress.Percent)
 }
 
 // Markdown renders the index as a Markdown table.
@@ -90,14 +229,56 @@ func (g *Generator) Markdown(data *Data) (string, error) {
 	var b strings.Builder
 	b.WriteString("# Features Index\n\n")
 	b.WriteString(fmt.Sprintf("> Auto-generated on %s - Do not edit manually\n\n", data.Generated))
-	b.WriteString("| ID | Title | Status | Owner | P | C | Labels | Updated | File |\n")
-	b.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+	if len(data.Epics) == 0 {
+		writeMarkdownTable(&b, data.Columns, data.Features)
+	} else {
+		for _, group := range data.Epics {
+			b.WriteString(fmt.Sprintf("## %s\n\n", group.Heading()))
+			if group.Status != "" {
+				b.WriteString(fmt.Sprintf("Status: %s\n\n", group.Status))
+			}
+			if entries := data.entriesFor(group.Features); len(entries) > 0 {
+				writeMarkdownTable(&b, data.Columns, entries)
+			} else {
+				b.WriteString("_No features._\n")
+			}
+			b.WriteString("\n")
+		}
+		if ungrouped := data.Ungrouped(); len(ungrouped) > 0 {
+			b.WriteString("## No epic\n\n")
+			writeMarkdownTable(&b, data.Columns, ungrouped)
+		}
+	}
 
-	for _, entry := range data.Features {
+	b.WriteString("\n## Summary\n\n")
+	keys := make([]string, 0, len(data.Summary))
+	for status := range data.Summary {
+		keys = append(keys, status)
+	}
+	sort.Strings(keys)
+	for _, status := range keys {
+		b.WriteString(fmt.Sprintf("- **%s**: %d\n", status, data.Summary[status]))
+	}
+	b.WriteString(fmt.Sprintf("\n**Total**: %d features\n", len(data.Features)))
+
+	return b.String(), nil
+}
+
+// writeMarkdownTable renders entries as a Markdown table.
+func writeMarkdownTable(b *strings.Builder, columns []string, entries []Entry) {
+	b.WriteString("| ID | Title | Status | Owner | P | C | Labels | Updated | File | Tasks |")
+	for _, column := range columns {
+		b.WriteString(fmt.Sprintf(" %s |", column))
+	}
+	b.WriteString("\n|---|---|---|---|---|---|---|---|---|---|")
+	b.WriteString(strings.Repeat("---|", len(columns)))
+	b.WriteString("\n")
+
+	for _, entry := range entries {
 		labels := strings.Join(entry.Labels, ", ")
 		relPath := filepath.ToSlash(entry.Path)
 		link := fmt.Sprintf("[%s](../features/%s)", relPath, relPath)
-		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s | %s |",
 			entry.ID,
 			entry.Title,
 			entry.Status,
@@ -107,21 +288,13 @@ func (g *Generator) Markdown(data *Data) (string, error) {
 			labels,
 			entry.Updated,
 			link,
+			entry.Tasks(),
 		))
+		for _, column := range columns {
+			b.WriteString(fmt.Sprintf(" %s |", entry.Custom[column]))
+		}
+		b.WriteString("\n")
 	}
-
-	b.WriteString("\n## Summary\n\n")
-	keys := make([]string, 0, len(data.Summary))
-	for status := range data.Summary {
-		keys = append(keys, status)
-	}
-	sort.Strings(keys)
-	for _, status := range keys {
-		b.WriteString(fmt.Sprintf("- **%s**: %d\n", status, data.Summary[status]))
-	}
-	b.WriteString(fmt.Sprintf("\n**Total**: %d features\n", len(data.Features)))
-
-	return b.String(), nil
 }
 
 // JSON renders the index as JSON.
@@ -149,11 +322,39 @@ caption { caption-side: top; font-weight: bold; margin-bottom: 1rem; }
 </style>
 </head>
 <body>
+{{ if .Epics }}
+<h1>Features Index (generated {{ .Generated }})</h1>
+{{ range .Epics }}
+<h2>{{ .Heading }}</h2>
+{{ if .Status }}<p>Status: {{ .Status }}</p>{{ end }}
+{{ $entries := entriesFor $ .Features }}{{ if $entries }}{{ template "table" (table "" $.Columns $entries) }}{{ else }}<p><em>No features.</em></p>{{ end }}
+{{ end }}
+{{ with .Ungrouped }}
+<h2>No epic</h2>
+{{ template "table" (table "" $.Columns .) }}
+{{ end }}
+{{ else }}
+{{ template "table" (table (printf "Features Index (generated %s)" .Generated) .Columns .Features) }}
+{{ end }}
+<section>
+<h2>Summary</h2>
+<ul>
+{{ range $status, $count := .Summary }}
+<li><strong>{{ $status }}</strong>: {{ $count }}</li>
+{{ end }}
+</ul>
+<p><strong>Total:</strong> {{ len .Features }} features</p>
+</section>
+</body>
+</html>
+{{ define "table" }}
 <table>
-<caption>Features Index (generated {{ .Generated }})</caption>
-<thead><tr><th>ID</th><th>Title</th><th>Status</th><th>Owner</th><th>Priority</th><th>Complexity</th><th>Labels</th><th>Updated</th><th>File</th></tr></thead>
+{{- with .Caption }}
+<caption>{{ . }}</caption>
+{{- end }}
+<thead><tr><th>ID</th><th>Title</th><th>Status</th><th>Owner</th><th>Priority</th><th>Complexity</th><th>Labels</th><th>Updated</th><th>File</th><th>Tasks</th>{{ range .Columns }}<th>{{ . }}</th>{{ end }}</tr></thead>
 <tbody>
-{{ range .Features }}
+{{ range .Entries }}
 <tr>
 <td>{{ .ID }}</td>
 <td>{{ .Title }}</td>
@@ -164,24 +365,24 @@ caption { caption-side: top; font-weight: bold; margin-bottom: 1rem; }
 <td>{{ join .Labels ", " }}</td>
 <td>{{ .Updated }}</td>
 <td><a href="../features/{{ .Path }}">{{ .Path }}</a></td>
+<td>{{ .Tasks }}</td>
+{{- $entry := . }}{{ range $.Columns }}
+<td>{{ index $entry.Custom . }}</td>
+{{- end }}
 </tr>
 {{ end }}
 </tbody>
 </table>
-<section>
-<h2>Summary</h2>
-<ul>
-{{ range $status, $count := .Summary }}
-<li><strong>{{ $status }}</strong>: {{ $count }}</li>
-{{ end }}
-</ul>
-<p><strong>Total:</strong> {{ len .Features }} features</p>
-</section>
-</body>
-</html>`
+{{ end }}`
 
 	funcMap := template.FuncMap{
 		"join": strings.Join,
+		"entriesFor": func(d *Data, ids []string) []Entry {
+			return d.entriesFor(ids)
+		},
+		"table": func(caption string, columns []string, entries []Entry) htmlTable {
+			return htmlTable{Caption: caption, Columns: columns, Entries: entries}
+		},
 	}
 
 	t, err := template.New("index").Funcs(funcMap).Parse(tpl)
@@ -196,6 +397,13 @@ caption { caption-side: top; font-weight: bold; margin-bottom: 1rem; }
 	return buf.String(), nil
 }
 
+// htmlTable is the data passed to the HTML table template.
+type htmlTable struct {
+	Caption string
+	Columns []string
+	Entries []Entry
+}
+
 func fallback(value, defaultValue string) string {
 	if strings.TrimSpace(value) == "" {
 		return defaultValue
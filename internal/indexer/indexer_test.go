@@ -3,9 +3,11 @@ package indexer
 import (
 	"encoding/json"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/virtualboard/vb-cli/internal/epic"
 	"github.com/virtualboard/vb-cli/internal/feature"
 	"github.com/virtualboard/vb-cli/internal/testutil"
 )
@@ -16,7 +18,7 @@ func TestGeneratorBuildAndRender(t *testing.T) {
 	mgr := feature.NewManager(opts)
 
 	feat := feature.Feature{
-		Path: filepath.Join(opts.RootDir, feature.DirectoryForStatus("backlog"), "FTR-1000-indexed.md"),
+		Path: filepath.Join(opts.RootDir, mgr.Workflow().DirectoryForStatus("backlog"), "FTR-1000-indexed.md"),
 		FrontMatter: feature.FrontMatter{
 			ID:         "FTR-1000",
 			Title:      "Indexed",
@@ -28,7 +30,7 @@ func TestGeneratorBuildAndRender(t *testing.T) {
 			Updated:    "2023-01-01",
 			Labels:     []string{"alpha"},
 		},
-		Body: "## Summary\n\nSummary\n\n## Details\n\nDetails\n",
+		Body: "## Summary\n\nSummary\n\n## Details\n\n- [x] Done\n- [ ] Open\n",
 	}
 	encoded, err := feat.Encode()
 	if err != nil {
@@ -49,9 +51,12 @@ func TestGeneratorBuildAndRender(t *testing.T) {
 	if len(data.Features) != 1 {
 		t.Fatalf("expected one feature, got %d", len(data.Features))
 	}
+	if data.Features[0].Checklist == nil || data.Features[0].Tasks() != "1/2 (50%)" {
+		t.Fatalf("unexpected checklist %+v", data.Features[0].Checklist)
+	}
 
 	md, err := gen.Markdown(data)
-	if err != nil || !strings.Contains(md, "Features Index") {
+	if err != nil || !strings.Contains(md, "Features Index") || !strings.Contains(md, ") | 1/2 (50%) |\n") {
 		t.Fatalf("markdown generation failed: %v\n%s", err, md)
 	}
 
@@ -63,9 +68,134 @@ func TestGeneratorBuildAndRender(t *testing.T) {
 	if err := json.Unmarshal([]byte(jsonOutput), &decoded); err != nil {
 		t.Fatalf("json parse failed: %v", err)
 	}
+	if decoded.Features[0].Checklist.Done != 1 {
+		t.Fatalf("expected checklist in json: %s", jsonOutput)
+	}
 
 	html, err := gen.HTML(data)
-	if err != nil || !strings.Contains(html, "<table>") {
+	if err != nil || !strings.Contains(html, "<table>") || !strings.Contains(html, "<td>1/2 (50%)</td>") {
 		t.Fatalf("html generation failed: %v", err)
 	}
 }
+
+func TestGeneratorCustomColumns(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	fix.WriteFile(t, "features/backlog/FTR-0001-custom.md", []byte("---\nid: FTR-0001\ntitle: Custom\nstatus: backlog\nupdated: 2026-01-01\nteam: core\ncustomers: [acme, globex]\n---\n## Summary\n"))
+
+	gen := NewGenerator(mgr)
+	gen.Columns = []string{"team", "customers", "missing"}
+	data, err := gen.Build()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	entry := data.Features[0]
+	if entry.Custom["team"] != "core" || entry.Custom["customers"] != "acme, globex" || entry.Custom["missing"] != "" {
+		t.Fatalf("unexpected custom columns: %#v", entry.Custom)
+	}
+
+	md, err := gen.Markdown(data)
+	if err != nil {
+		t.Fatalf("markdown failed: %v", err)
+	}
+	if !strings.Contains(md, "| File | Tasks | team | customers | missing |\n|---|---|---|---|---|---|---|---|---|---|---|---|---|\n") ||
+		!strings.Contains(md, "| core | acme, globex |  |\n") {
+		t.Fatalf("unexpected markdown:\n%s", md)
+	}
+	parsed, err := ParseMarkdown(md)
+	if err != nil || len(parsed.Features) != 1 || parsed.Features[0].ID != "FTR-0001" {
+		t.Fatalf("markdown with extra columns should still parse: %v %+v", err, parsed)
+	}
+
+	html, err := gen.HTML(data)
+	if err != nil || !strings.Contains(html, "<th>team</th>") || !strings.Contains(html, "<td>acme, globex</td>") {
+		t.Fatalf("unexpected html: %v\n%s", err, html)
+	}
+}
+
+func TestGeneratorSelect(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	mgr := feature.NewManager(fix.Options(t, false, false, false))
+	fix.WriteFile(t, "features/backlog/FTR-0001-one.md", []byte("---\nid: FTR-0001\ntitle: One\nstatus: backlog\n---\n"))
+	fix.WriteFile(t, "features/review/FTR-0002-two.md", []byte("---\nid: FTR-0002\ntitle: Two\nstatus: review\n---\n"))
+
+	gen := NewGenerator(mgr)
+	gen.Select = func(features []*feature.Feature) []*feature.Feature {
+		return features[len(features)-1:]
+	}
+	data, err := gen.Build()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(data.Features) != 1 || data.Summary["backlog"]+data.Summary["review"] != 1 {
+		t.Fatalf("expected one selected feature, got %+v", data)
+	}
+}
+
+func TestGeneratorGroupByEpic(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+	fix.WriteFile(t, "features/done/FTR-0001-one.md", []byte("---\nid: FTR-0001\ntitle: One\nstatus: done\nepic: checkout\n---\n"))
+	fix.WriteFile(t, "features/backlog/FTR-0002-two.md", []byte("---\nid: FTR-0002\ntitle: Two\nstatus: backlog\nepic: Checkout\n---\n"))
+	fix.WriteFile(t, "features/backlog/FTR-0003-three.md", []byte("---\nid: FTR-0003\ntitle: Three\nstatus: backlog\nepic: search\n---\n"))
+	fix.WriteFile(t, "features/backlog/FTR-0004-four.md", []byte("---\nid: FTR-0004\ntitle: Four\nstatus: backlog\n---\n"))
+
+	gen := NewGenerator(mgr)
+	gen.GroupByEpic = true
+	gen.Epics = []*epic.Epic{
+		{FrontMatter: epic.FrontMatter{ID: "checkout", Title: "Checkout revamp", Status: "active"}},
+		{FrontMatter: epic.FrontMatter{ID: "billing", Title: "Billing", Status: "planned"}},
+	}
+	data, err := gen.Build()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(data.Epics) != 3 {
+		t.Fatalf("expected three groups, got %+v", data.Epics)
+	}
+	checkout := data.Epics[0]
+	if !checkout.Defined || checkout.Progress.Total != 2 || checkout.Progress.Done != 1 || checkout.Progress.Percent != 50 ||
+		!reflect.DeepEqual(checkout.Features, []string{"FTR-0001", "FTR-0002"}) {
+		t.Fatalf("unexpected checkout group %+v", checkout)
+	}
+	if data.Epics[1].Progress.Total != 0 || len(data.Epics[1].Features) != 0 || data.Epics[2].Defined || data.Epics[2].ID != "search" {
+		t.Fatalf("unexpected groups %+v", data.Epics[1:])
+	}
+	if ungrouped := data.Ungrouped(); len(ungrouped) != 1 || ungrouped[0].ID != "FTR-0004" {
+		t.Fatalf("unexpected ungrouped %+v", ungrouped)
+	}
+
+	md, err := gen.Markdown(data)
+	if err != nil {
+		t.Fatalf("markdown failed: %v", err)
+	}
+	for _, want := range []string{
+		"## Epic: Checkout revamp (checkout) — 1/2 done (50%)\n\nStatus: active\n\n| ID |",
+		"## Epic: Billing (billing) — 0/0 done (0%)\n\nStatus: planned\n\n_No features._\n",
+		"## Epic: search (undefined) — 0/1 done (0%)\n\n| ID |",
+		"## No epic\n\n| ID |",
+		"**Total**: 4 features",
+	} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("expected %q in:\n%s", want, md)
+		}
+	}
+	parsed, err := ParseMarkdown(md)
+	if err != nil || len(parsed.Features) != 4 {
+		t.Fatalf("grouped markdown should still parse: %v %+v", err, parsed)
+	}
+
+	html, err := gen.HTML(data)
+	if err != nil || strings.Count(html, "<table>") != 3 || !strings.Contains(html, "<h2>No epic</h2>") || strings.Contains(html, "<caption>") {
+		t.Fatalf("unexpected grouped html: %v\n%s", err, html)
+	}
+	gen.GroupByEpic = false
+	data, _ = gen.Build()
+	html, _ = gen.HTML(data)
+	if !strings.Contains(html, "<caption>Features Index (generated ") || strings.Count(html, "<table>") != 1 {
+		t.Fatalf("unexpected flat html:\n%s", html)
+	}
+}
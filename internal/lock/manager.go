@@ -47,16 +47,19 @@ type Manager struct {
 	opts     *config.Options
 	log      *logrus.Entry
 	auditLog *audit.Logger
+	// auditErr records why the audit log is unavailable; lock changes are refused
+	// while it is set.
+	auditErr error
 }
 
 // NewManager constructs a lock manager.
 func NewManager(opts *config.Options) *Manager {
-	auditPath := filepath.Join(opts.RootDir, "audit.jsonl")
-	auditLog, _ := audit.NewLogger(auditPath) // best-effort
+	auditLog, auditErr := opts.AuditLogger()
 	return &Manager{
 		opts:     opts,
 		log:      opts.Logger().WithField("component", "lock"),
 		auditLog: auditLog,
+		auditErr: auditErr,
 	}
 }
 
@@ -123,6 +126,9 @@ func (m *Manager) Acquire(id, owner string, ttl int, force bool) (*Info, error)
 		}).Info("Skipping lock write in dry-run mode")
 		return info, nil
 	}
+	if m.auditErr != nil {
+		return nil, m.auditErr
+	}
 
 	payload, err := json.MarshalIndent(info, "", "  ")
 	if err != nil {
@@ -214,6 +220,9 @@ func (m *Manager) Release(id string) error {
 		}).Info("Skipping lock removal in dry-run mode")
 		return nil
 	}
+	if m.auditErr != nil {
+		return m.auditErr
+	}
 	if err := os.Remove(path); err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil
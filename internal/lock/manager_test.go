@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/virtualboard/vb-cli/internal/config"
 	"github.com/virtualboard/vb-cli/internal/testutil"
 )
 
@@ -176,3 +177,21 @@ func TestAcquireForceOverwrite(t *testing.T) {
 		t.Fatalf("expected owner2, got %s", info.Owner)
 	}
 }
+
+func TestManagerRefusesLocksWithoutAuditSigner(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	t.Setenv(config.AuditKeyEnv, "")
+	opts.Settings.Audit.Signing = config.SigningSettings{Method: config.SigningEd25519}
+	mgr := NewManager(opts)
+
+	if _, err := mgr.Acquire("FTR-0001", "alice", 5, false); err == nil {
+		t.Fatalf("expected lock to fail without a signing key")
+	}
+	if _, err := os.Stat(mgr.Path("FTR-0001")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no lock file, got %v", err)
+	}
+	if err := mgr.Release("FTR-0001"); err == nil {
+		t.Fatalf("expected unlock to fail without a signing key")
+	}
+}
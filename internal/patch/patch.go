@@ -0,0 +1,157 @@
+// Package patch describes planned file changes as git-style unified diffs, so
+// dry runs can show exactly what a command would write, rename or delete.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// Op is the kind of change a patch makes.
+type Op string
+
+// Supported operations.
+const (
+	Create Op = "create"
+	Modify Op = "modify"
+	Rename Op = "rename"
+	Delete Op = "delete"
+)
+
+// Patch is a planned change to one file.
+type Patch struct {
+	Op Op `json:"op"`
+	// Path is the file's path after the change, slash-separated and relative to
+	// the project root.
+	Path string `json:"path"`
+	// From is the previous path of a renamed file.
+	From string `json:"from,omitempty"`
+	// Diff is the git-style unified diff of the change.
+	Diff string `json:"diff"`
+}
+
+// Write plans writing data to the file at to, which previously lived at from
+// (the same path unless the file is renamed). Paths are shown relative to root.
+// It returns nil when the write changes nothing.
+func Write(root, from, to string, data []byte) (*Patch, error) {
+	before, exists, err := read(from)
+	if err != nil {
+		return nil, err
+	}
+	oldName, newName := util.RelativePath(root, from), util.RelativePath(root, to)
+	p := &Patch{Op: Modify, Path: newName}
+	var header strings.Builder
+	fromFile := "a/" + oldName
+	switch {
+	case !exists:
+		p.Op = Create
+		oldName = newName
+		fromFile = "/dev/null"
+		fmt.Fprintf(&header, "diff --git a/%s b/%s\nnew file mode 100644\n", newName, newName)
+	case from != to:
+		p.Op = Rename
+		p.From = oldName
+		fmt.Fprintf(&header, "diff --git a/%s b/%s\nrename from %s\nrename to %s\n", oldName, newName, oldName, newName)
+	default:
+		fmt.Fprintf(&header, "diff --git a/%s b/%s\n", oldName, newName)
+	}
+
+	if exists && string(before) == string(data) {
+		if p.Op == Modify {
+			return nil, nil
+		}
+		p.Diff = header.String()
+		return p, nil
+	}
+	body, err := unified(before, data, fromFile, "b/"+newName)
+	if err != nil {
+		return nil, err
+	}
+	p.Diff = header.String() + body
+	return p, nil
+}
+
+// Remove plans deleting the file at path. It returns nil when there is no file.
+func Remove(root, path string) (*Patch, error) {
+	before, exists, err := read(path)
+	if err != nil || !exists {
+		return nil, err
+	}
+	name := util.RelativePath(root, path)
+	body, err := unified(before, nil, "a/"+name, "/dev/null")
+	if err != nil {
+		return nil, err
+	}
+	return &Patch{
+		Op:   Delete,
+		Path: name,
+		Diff: fmt.Sprintf("diff --git a/%s b/%s\ndeleted file mode 100644\n", name, name) + body,
+	}, nil
+}
+
+// Join concatenates the diffs of patches into one unified diff.
+func Join(patches []Patch) string {
+	var b strings.Builder
+	for _, p := range patches {
+		b.WriteString(p.Diff)
+	}
+	return b.String()
+}
+
+// Recorder collects the patches planned during a dry run.
+type Recorder struct {
+	patches []Patch
+}
+
+// Add records p; nil patches are ignored.
+func (r *Recorder) Add(p *Patch) {
+	if p != nil {
+		r.patches = append(r.patches, *p)
+	}
+}
+
+// Patches returns the recorded patches in the order they were planned.
+func (r *Recorder) Patches() []Patch {
+	return append([]Patch{}, r.patches...)
+}
+
+func read(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- paths are workspace files a command is about to change
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, true, nil
+}
+
+func unified(before, after []byte, fromFile, toFile string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(before),
+		B:        splitLines(after),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
+
+// splitLines splits content into newline-terminated lines; empty content has none.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
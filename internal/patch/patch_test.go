@@ -0,0 +1,119 @@
+package patch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "docs", "a.md")
+	writeFile(t, path, "one\ntwo\nthree\n")
+
+	p, err := Write(root, path, path, []byte("one\n2\nthree\n"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	want := "diff --git a/docs/a.md b/docs/a.md\n--- a/docs/a.md\n+++ b/docs/a.md\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n"
+	if p.Op != Modify || p.Path != "docs/a.md" || p.From != "" || p.Diff != want {
+		t.Fatalf("unexpected modify patch %+v", p)
+	}
+
+	if p, err := Write(root, path, path, []byte("one\ntwo\nthree\n")); err != nil || p != nil {
+		t.Fatalf("expected no patch for an unchanged file, got %+v (%v)", p, err)
+	}
+
+	created := filepath.Join(root, "b.md")
+	p, err = Write(root, created, created, []byte("new"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	want = "diff --git a/b.md b/b.md\nnew file mode 100644\n--- /dev/null\n+++ b/b.md\n@@ -0,0 +1 @@\n+new\n"
+	if p.Op != Create || p.Path != "b.md" || p.Diff != want {
+		t.Fatalf("unexpected create patch %+v", p)
+	}
+}
+
+func TestWriteRename(t *testing.T) {
+	root := t.TempDir()
+	from := filepath.Join(root, "old.md")
+	to := filepath.Join(root, "sub", "new.md")
+	writeFile(t, from, "same\n")
+
+	p, err := Write(root, from, to, []byte("same\n"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if p.Op != Rename || p.From != "old.md" || p.Path != "sub/new.md" ||
+		p.Diff != "diff --git a/old.md b/sub/new.md\nrename from old.md\nrename to sub/new.md\n" {
+		t.Fatalf("unexpected pure rename %+v", p)
+	}
+
+	p, err = Write(root, from, to, []byte("changed\n"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.Contains(p.Diff, "rename to sub/new.md\n--- a/old.md\n+++ b/sub/new.md\n") || !strings.Contains(p.Diff, "-same\n+changed\n") {
+		t.Fatalf("expected a rename with content changes, got:\n%s", p.Diff)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	writeFile(t, path, "line\nlast without newline")
+
+	p, err := Remove(root, path)
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	want := "diff --git a/a.md b/a.md\ndeleted file mode 100644\n--- a/a.md\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-line\n-last without newline\n"
+	if p.Op != Delete || p.Path != "a.md" || p.Diff != want {
+		t.Fatalf("unexpected delete patch %+v", p)
+	}
+
+	if p, err := Remove(root, filepath.Join(root, "missing.md")); err != nil || p != nil {
+		t.Fatalf("expected no patch for a missing file, got %+v (%v)", p, err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	root := t.TempDir()
+	if _, err := Write(root, root, root, []byte("x")); err == nil || !strings.Contains(err.Error(), "failed to read") {
+		t.Fatalf("expected a read error for a directory, got %v", err)
+	}
+	if _, err := Remove(root, root); err == nil {
+		t.Fatal("expected a read error for a directory")
+	}
+}
+
+func TestRecorderAndJoin(t *testing.T) {
+	var r Recorder
+	if got := r.Patches(); got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty, non-nil slice, got %#v", got)
+	}
+	r.Add(nil)
+	r.Add(&Patch{Op: Create, Path: "a", Diff: "first\n"})
+	r.Add(&Patch{Op: Delete, Path: "b", Diff: "second\n"})
+
+	patches := r.Patches()
+	if len(patches) != 2 || Join(patches) != "first\nsecond\n" {
+		t.Fatalf("unexpected patches %+v", patches)
+	}
+	patches[0].Path = "changed"
+	if r.Patches()[0].Path != "a" {
+		t.Fatal("Patches should return a copy")
+	}
+}
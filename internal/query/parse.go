@@ -0,0 +1,330 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokColon
+	tokOp
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// SyntaxError reports a problem at a byte offset in the query text.
+type SyntaxError struct {
+	Query  string
+	Offset int
+	Msg    string
+}
+
+// Error renders the message with the 1-based column and a caret under it.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s\n  %s\n  %s^", e.Offset+1, e.Msg, e.Query, strings.Repeat(" ", e.Offset))
+}
+
+const wordStops = " \t\r\n()\",':=!<>~"
+
+func lex(src string) ([]token, error) {
+	tokens := []token{}
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+		case c == ':':
+			tokens = append(tokens, token{tokColon, ":", i})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, &SyntaxError{Query: src, Offset: i, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+		case strings.IndexByte("=!<>~", c) >= 0:
+			op := string(c)
+			if i+1 < len(src) && src[i+1] == '=' && c != '=' && c != '~' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &SyntaxError{Query: src, Offset: i, Msg: "expected '=' after '!'"}
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		default:
+			start := i
+			for i < len(src) && strings.IndexByte(wordStops, src[i]) < 0 {
+				i++
+			}
+			tokens = append(tokens, token{tokWord, src[start:i], start})
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+	known  func(string) bool
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Query: p.src, Offset: t.start, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+// parseOr := and ("or" and)*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd := unary (["and"] unary)*; juxtaposed terms are AND'ed.
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || t.kind == tokRParen || p.keyword("or") {
+			return left, nil
+		}
+		if p.keyword("and") {
+			p.next()
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+// parseUnary := "not" unary | primary
+func (p *parser) parseUnary() (node, error) {
+	if p.keyword("not") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary := "(" or ")" | word ":" values | field op value | field ["not"] "in" "(" values ")"
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected ')' to close '(' at column %d, found %s", t.start+1, closing.describe())
+		}
+		return inner, nil
+	case tokWord:
+	default:
+		return nil, p.errorf(t, "expected a field or '(', found %s", t.describe())
+	}
+
+	name := strings.ToLower(t.text)
+	switch p.peek().kind {
+	case tokColon:
+		p.next()
+		switch name {
+		case "is", "has", "empty":
+			return p.parseFact(name)
+		}
+		field, err := p.field(t)
+		if err != nil {
+			return nil, err
+		}
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		return matchNode{field: field, values: values}, nil
+	case tokOp:
+		op := p.next()
+		field, err := p.field(t)
+		if err != nil {
+			return nil, err
+		}
+		if op.text == "=" || op.text == "!=" {
+			values, err := p.parseValues()
+			if err != nil {
+				return nil, err
+			}
+			var n node = matchNode{field: field, values: values}
+			if op.text == "!=" {
+				n = notNode{n}
+			}
+			return n, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if op.text == "~" {
+			return containsNode{field: field, value: value}, nil
+		}
+		return compareNode{field: field, op: op.text, value: value}, nil
+	}
+
+	negate := false
+	if p.keyword("not") {
+		p.next()
+		negate = true
+	}
+	if !p.keyword("in") {
+		return nil, p.errorf(p.peek(), "expected ':', an operator or 'in' after %q, found %s", t.text, p.peek().describe())
+	}
+	p.next()
+	field, err := p.field(t)
+	if err != nil {
+		return nil, err
+	}
+	if open := p.next(); open.kind != tokLParen {
+		return nil, p.errorf(open, "expected '(' after 'in', found %s", open.describe())
+	}
+	values, err := p.parseValues()
+	if err != nil {
+		return nil, err
+	}
+	if closing := p.next(); closing.kind != tokRParen {
+		return nil, p.errorf(closing, "expected ',' or ')', found %s", closing.describe())
+	}
+	var n node = matchNode{field: field, values: values}
+	if negate {
+		n = notNode{n}
+	}
+	return n, nil
+}
+
+func (p *parser) parseFact(kind string) (node, error) {
+	t := p.peek()
+	arg, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "is":
+		fact := strings.ToLower(arg)
+		for _, known := range Facts {
+			if fact == known {
+				return factNode{fact: fact}, nil
+			}
+		}
+		return nil, p.errorf(t, "unknown fact is:%s (allowed: %s)", arg, strings.Join(Facts, ", "))
+	case "has":
+		field, err := p.field(t)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{emptyFieldNode{field: field}}, nil
+	default:
+		return emptySectionNode{section: arg}, nil
+	}
+}
+
+func (p *parser) field(t token) (string, error) {
+	name := strings.ToLower(t.text)
+	if alias, ok := fieldAliases[name]; ok {
+		name = alias
+	}
+	if builtinFields[name] {
+		return name, nil
+	}
+	if p.known(t.text) {
+		return t.text, nil
+	}
+	return "", p.errorf(t, "unknown field %q", t.text)
+}
+
+func (p *parser) parseValues() ([]string, error) {
+	values := []string{}
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek().kind != tokComma {
+			return values, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseValue() (string, error) {
+	t := p.next()
+	if t.kind != tokWord && t.kind != tokString {
+		return "", p.errorf(t, "expected a value, found %s", t.describe())
+	}
+	return t.text, nil
+}
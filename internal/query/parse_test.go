@@ -0,0 +1,70 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorsCarryPositions(t *testing.T) {
+	cases := []struct {
+		src    string
+		column int
+		msg    string
+	}{
+		{"", 1, "empty query"},
+		{"status in (review", 18, "expected ',' or ')'"},
+		{"status in review", 11, "expected '(' after 'in'"},
+		{"(status:done", 13, "expected ')' to close '(' at column 1"},
+		{"status:done)", 12, "unexpected ')'"},
+		{"statsu:done", 1, "unknown field \"statsu\""},
+		{"status ! done", 8, "expected '=' after '!'"},
+		{"title:'open", 7, "unterminated string"},
+		{"status", 7, "expected ':', an operator or 'in' after \"status\""},
+		{"is:", 4, "expected a value"},
+		{"is:sleepy", 4, "unknown fact is:sleepy"},
+		{"has:colour", 5, "unknown field \"colour\""},
+		{"status:", 8, "expected a value, found end of query"},
+		{"and", 4, "after \"and\", found end of query"},
+		{"not )", 5, "expected a field or '('"},
+		{"status:done or", 15, "expected a field"},
+		{"status:done and (", 18, "expected a field"},
+		{"status > ,", 10, "expected a value, found ','"},
+		{"status in (a,)", 14, "expected a value"},
+		{"bogus = x", 1, "unknown field"},
+		{"bogus in (x)", 1, "unknown field"},
+		{"status = ", 10, "expected a value"},
+		{"status ~ (", 10, "expected a value"},
+		{"status: done or owner >", 24, "expected a value"},
+	}
+	for _, tc := range cases {
+		_, err := Parse(tc.src, nil)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: expected syntax error, got %v", tc.src, err)
+		}
+		if syntaxErr.Offset+1 != tc.column || !strings.Contains(syntaxErr.Msg, tc.msg) {
+			t.Fatalf("%q: expected %q at column %d, got %q at column %d", tc.src, tc.msg, tc.column, syntaxErr.Msg, syntaxErr.Offset+1)
+		}
+	}
+}
+
+func TestSyntaxErrorRendering(t *testing.T) {
+	_, err := Parse("status in review", nil)
+	want := "invalid query at column 11: expected '(' after 'in', found 'review'\n  status in review\n            ^"
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected rendering:\n%v", err)
+	}
+	_, err = Parse(`title:"open`, nil)
+	if err == nil || !strings.Contains(err.Error(), "unterminated string") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = Parse(`status:"in review" )`, nil)
+	if err == nil || !strings.Contains(err.Error(), "unexpected ')'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = Parse(`"status" in (x)`, nil)
+	if err == nil || !strings.Contains(err.Error(), `found "status"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
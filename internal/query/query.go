@@ -0,0 +1,407 @@
+// Package query implements the feature query language shared by list, index and
+// bulk commands, e.g.
+//
+//	status in (review,done) and label:backend and updated > 2026-01-01 and not owner:unassigned
+//
+// Terms are AND'ed when written next to each other; "or", "not" and parentheses
+// combine them further.
+package query
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/fields"
+	"github.com/virtualboard/vb-cli/internal/lock"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+// Facts lists the derived facts accepted by is:.
+var Facts = []string{"locked", "waiting", "ready"}
+
+var builtinFields = map[string]bool{
+	"id": true, "title": true, "status": true, "owner": true, "priority": true, "complexity": true,
+	"created": true, "updated": true, "labels": true, "dependencies": true, "epic": true, "risk_notes": true,
+}
+
+var fieldAliases = map[string]string{
+	"label": "labels",
+	"dep":   "dependencies",
+	"deps":  "dependencies",
+}
+
+// Ranked fields compare by position rather than alphabetically.
+var ranks = map[string][]string{
+	"priority":   {"low", "medium", "high", "critical"},
+	"complexity": {"xs", "s", "m", "l", "xl"},
+}
+
+// Env supplies the facts a query can ask about beyond frontmatter. A nil Env, or
+// nil functions, treat every feature as unlocked and every dependency as done.
+type Env struct {
+	// Locked reports whether a feature currently holds an unexpired lock.
+	Locked func(id string) bool
+	// Unfinished reports whether a dependency is missing or not yet done.
+	Unfinished func(id string) bool
+}
+
+// NewEnv derives facts from the full feature list, the workflow and the lock directory.
+func NewEnv(all []*feature.Feature, wf *workflow.Workflow, locks *lock.Manager) *Env {
+	statuses := make(map[string]string, len(all))
+	for _, feat := range all {
+		statuses[strings.ToUpper(feat.FrontMatter.ID)] = feat.FrontMatter.Status
+	}
+	locked := map[string]bool{}
+	return &Env{
+		Locked: func(id string) bool {
+			if v, ok := locked[id]; ok {
+				return v
+			}
+			info, err := locks.Load(id)
+			locked[id] = err == nil && info != nil && !info.Expired()
+			return locked[id]
+		},
+		Unfinished: func(id string) bool {
+			status, ok := statuses[strings.ToUpper(strings.TrimSpace(id))]
+			return !ok || !wf.IsDone(status)
+		},
+	}
+}
+
+// Query is a parsed query expression. A nil Query matches every feature.
+type Query struct {
+	root node
+}
+
+// Parse compiles src. Field names must be built-in or declared in custom; errors
+// are *SyntaxError values carrying the offending position.
+func Parse(src string, custom fields.Set) (*Query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, tokens: tokens, known: func(name string) bool {
+		_, ok := custom[name]
+		return ok
+	}}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty query")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t.describe())
+	}
+	return &Query{root: root}, nil
+}
+
+// ParseAll compiles every non-blank expression and AND's them together. It
+// returns nil when there is nothing to parse.
+func ParseAll(srcs []string, custom fields.Set) (*Query, error) {
+	queries := make([]*Query, 0, len(srcs))
+	for _, src := range srcs {
+		if strings.TrimSpace(src) == "" {
+			continue
+		}
+		q, err := Parse(src, custom)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	return And(queries...), nil
+}
+
+// And combines queries so features must match all of them. Nil queries match
+// everything and are skipped; the result is nil when every query is.
+func And(queries ...*Query) *Query {
+	var combined *Query
+	for _, q := range queries {
+		switch {
+		case q == nil:
+		case combined == nil:
+			combined = q
+		default:
+			combined = &Query{root: andNode{combined.root, q.root}}
+		}
+	}
+	return combined
+}
+
+// Any matches features whose built-in field equals one of values, like
+// "field in (a,b)": case is ignored and values containing * are globs. Blank
+// values are dropped, and nil is returned when none remain.
+func Any(field string, values []string) *Query {
+	if alias, ok := fieldAliases[field]; ok {
+		field = alias
+	}
+	kept := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return &Query{root: matchNode{field: field, values: kept}}
+}
+
+// Compare orders a built-in field against value like "field op value", where op
+// is <, <=, > or >=. It returns nil when value is blank.
+func Compare(field, op, value string) *Query {
+	if alias, ok := fieldAliases[field]; ok {
+		field = alias
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return &Query{root: compareNode{field: field, op: op, value: strings.TrimSpace(value)}}
+}
+
+// Match reports whether the feature satisfies the query.
+func (q *Query) Match(feat *feature.Feature, env *Env) bool {
+	if q == nil {
+		return true
+	}
+	if env == nil {
+		env = &Env{}
+	}
+	return q.root.eval(&subject{feat: feat, env: env})
+}
+
+// Apply returns the features matching the query, preserving order.
+func (q *Query) Apply(features []*feature.Feature, env *Env) []*feature.Feature {
+	out := make([]*feature.Feature, 0, len(features))
+	for _, feat := range features {
+		if q.Match(feat, env) {
+			out = append(out, feat)
+		}
+	}
+	return out
+}
+
+// subject is a feature under evaluation with lazily computed facts.
+type subject struct {
+	feat     *feature.Feature
+	env      *Env
+	sections map[string]string
+}
+
+func (s *subject) values(field string) []string {
+	fm := s.feat.FrontMatter
+	switch field {
+	case "id":
+		return []string{fm.ID}
+	case "title":
+		return []string{fm.Title}
+	case "status":
+		return []string{fm.Status}
+	case "owner":
+		if strings.TrimSpace(fm.Owner) == "" {
+			return []string{"unassigned"}
+		}
+		return []string{fm.Owner}
+	case "priority":
+		return []string{fm.Priority}
+	case "complexity":
+		return []string{fm.Complexity}
+	case "created":
+		return []string{fm.Created}
+	case "updated":
+		return []string{fm.Updated}
+	case "labels":
+		return fm.Labels
+	case "dependencies":
+		return fm.Dependencies
+	case "epic":
+		return []string{fm.Epic}
+	case "risk_notes":
+		return []string{fm.RiskNotes}
+	}
+	switch v := fm.Custom[field].(type) {
+	case nil:
+		return nil
+	case []string:
+		return v
+	default:
+		return []string{fields.Format(v)}
+	}
+}
+
+func (s *subject) unfinishedDeps() bool {
+	if s.env.Unfinished == nil {
+		return false
+	}
+	for _, dep := range s.feat.FrontMatter.Dependencies {
+		if strings.TrimSpace(dep) != "" && s.env.Unfinished(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+type node interface {
+	eval(s *subject) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(s *subject) bool { return n.left.eval(s) && n.right.eval(s) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(s *subject) bool { return n.left.eval(s) || n.right.eval(s) }
+
+type notNode struct{ inner node }
+
+func (n notNode) eval(s *subject) bool { return !n.inner.eval(s) }
+
+// matchNode is true when any value of the field equals any of the values; values
+// containing * are glob patterns. Comparison ignores case.
+type matchNode struct {
+	field  string
+	values []string
+}
+
+func (n matchNode) eval(s *subject) bool {
+	for _, have := range s.values(n.field) {
+		have = strings.ToLower(strings.TrimSpace(have))
+		for _, want := range n.values {
+			want = strings.ToLower(want)
+			if have == want {
+				return true
+			}
+			if strings.Contains(want, "*") {
+				if ok, _ := path.Match(want, have); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+type containsNode struct {
+	field string
+	value string
+}
+
+func (n containsNode) eval(s *subject) bool {
+	want := strings.ToLower(n.value)
+	for _, have := range s.values(n.field) {
+		if strings.Contains(strings.ToLower(have), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareNode orders ranked fields by rank, numbers numerically and everything
+// else, including YYYY-MM-DD dates, lexically. Empty values never match.
+type compareNode struct {
+	field string
+	op    string
+	value string
+}
+
+func (n compareNode) eval(s *subject) bool {
+	for _, have := range s.values(n.field) {
+		if strings.TrimSpace(have) == "" {
+			continue
+		}
+		cmp, ok := compareValues(n.field, have, n.value)
+		if !ok {
+			continue
+		}
+		switch n.op {
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		default:
+			ok = cmp <= 0
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func compareValues(field, a, b string) (int, bool) {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if order, ok := ranks[field]; ok {
+		ra, rb := indexOf(order, a), indexOf(order, b)
+		if ra < 0 || rb < 0 {
+			return 0, false
+		}
+		return ra - rb, true
+	}
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	return strings.Compare(a, b), true
+}
+
+func indexOf(values []string, target string) int {
+	for i, v := range values {
+		if v == target {
+			return i
+		}
+	}
+	return -1
+}
+
+type emptyFieldNode struct{ field string }
+
+func (n emptyFieldNode) eval(s *subject) bool {
+	for _, v := range s.values(n.field) {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// emptySectionNode is true when the body section is missing or blank.
+type emptySectionNode struct{ section string }
+
+func (n emptySectionNode) eval(s *subject) bool {
+	if s.sections == nil {
+		_, s.sections = feature.ExtractSections(s.feat.Body)
+	}
+	for name, content := range s.sections {
+		if strings.EqualFold(name, n.section) {
+			return strings.TrimSpace(content) == ""
+		}
+	}
+	return true
+}
+
+type factNode struct{ fact string }
+
+func (n factNode) eval(s *subject) bool {
+	switch n.fact {
+	case "locked":
+		return s.env.Locked != nil && s.env.Locked(s.feat.FrontMatter.ID)
+	case "waiting":
+		return s.unfinishedDeps()
+	default:
+		return !s.unfinishedDeps()
+	}
+}
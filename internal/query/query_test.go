@@ -0,0 +1,147 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/fields"
+	"github.com/virtualboard/vb-cli/internal/lock"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+	"github.com/virtualboard/vb-cli/internal/workflow"
+)
+
+func sampleFeatures() []*feature.Feature {
+	return []*feature.Feature{
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0001", Title: "Rate limiting", Status: "done", Owner: "alice", Priority: "high", Complexity: "M",
+			Updated: "2026-02-01", Labels: []string{"backend", "api"}, Custom: map[string]interface{}{"estimate": 5, "customers": []string{"acme"}}},
+			Body: "## Summary\n\nLimits.\n\n## Acceptance Criteria\n\n- works\n"},
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0002", Title: "Checkout page", Status: "review", Owner: "", Priority: "critical", Complexity: "XL",
+			Updated: "2025-12-20", Labels: []string{"frontend"}, Dependencies: []string{"FTR-0001"}, Epic: "checkout", Custom: map[string]interface{}{"estimate": 13}},
+			Body: "## Summary\n\nPage.\n\n## Acceptance Criteria\n\n"},
+		{FrontMatter: feature.FrontMatter{ID: "FTR-0003", Title: "Saved cards", Status: "backlog", Owner: "bob", Priority: "low", Complexity: "S",
+			Updated: "2026-03-05", Labels: []string{"backend"}, Dependencies: []string{"FTR-0002", " "}, Epic: "checkout"},
+			Body: "## Summary\n\nCards.\n"},
+	}
+}
+
+func matchIDs(t *testing.T, src string, env *Env) []string {
+	t.Helper()
+	q, err := Parse(src, fields.Set{"estimate": {Type: fields.Int}, "customers": {Type: fields.StringList}})
+	if err != nil {
+		t.Fatalf("parse %q failed: %v", src, err)
+	}
+	ids := []string{}
+	for _, feat := range q.Apply(sampleFeatures(), env) {
+		ids = append(ids, feat.FrontMatter.ID)
+	}
+	return ids
+}
+
+func TestQueryEvaluation(t *testing.T) {
+	statuses := map[string]string{"FTR-0001": "done", "FTR-0002": "review"}
+	env := &Env{
+		Locked:     func(id string) bool { return id == "FTR-0003" },
+		Unfinished: func(id string) bool { return statuses[id] != "done" },
+	}
+	cases := map[string]string{
+		"status in (review,done) and label:backend and updated > 2026-01-01 and not owner:unassigned": "FTR-0001",
+		"status:review,backlog":        "FTR-0002,FTR-0003",
+		"status = REVIEW or owner=bob": "FTR-0002,FTR-0003",
+		"owner != alice":               "FTR-0002,FTR-0003",
+		"status not in (done)":         "FTR-0002,FTR-0003",
+		"title ~ rate":                 "FTR-0001",
+		"title:'*card*'":               "FTR-0003",
+		"priority >= high":             "FTR-0001,FTR-0002",
+		"complexity < m":               "FTR-0003",
+		"priority > urgent":            "",
+		"estimate > 9":                 "FTR-0002",
+		"estimate <= 5":                "FTR-0001",
+		"customers:acme":               "FTR-0001",
+		"updated < 2026-01-01":         "FTR-0002",
+		"has:epic and has:deps":        "FTR-0002,FTR-0003",
+		"not has:estimate":             "FTR-0003",
+		"empty:'acceptance criteria'":  "FTR-0002,FTR-0003",
+		"is:locked":                    "FTR-0003",
+		"is:waiting":                   "FTR-0003",
+		"is:ready":                     "FTR-0001,FTR-0002",
+		"(label:frontend or label:api) epic:checkout": "FTR-0002",
+		"dep:FTR-0001": "FTR-0002",
+	}
+	for src, want := range cases {
+		got := ""
+		for i, id := range matchIDs(t, src, env) {
+			if i > 0 {
+				got += ","
+			}
+			got += id
+		}
+		if got != want {
+			t.Fatalf("%q: expected [%s], got [%s]", src, want, got)
+		}
+	}
+
+	if ids := matchIDs(t, "is:locked or is:waiting", nil); len(ids) != 0 {
+		t.Fatalf("nil env should report no facts, got %v", ids)
+	}
+}
+
+func TestNilQueryMatchesEverything(t *testing.T) {
+	var q *Query
+	if len(q.Apply(sampleFeatures(), nil)) != 3 {
+		t.Fatalf("expected nil query to match all features")
+	}
+	q, err := ParseAll([]string{" ", ""}, nil)
+	if err != nil || q != nil {
+		t.Fatalf("expected nil query for blank input, got %v %v", q, err)
+	}
+	q, err = ParseAll([]string{"status:backlog,done", "label:backend owner:bob"}, nil)
+	if err != nil || len(q.Apply(sampleFeatures(), nil)) != 1 {
+		t.Fatalf("expected combined query to match one feature: %v", err)
+	}
+	if _, err := ParseAll([]string{"status:done", "status:"}, nil); err == nil {
+		t.Fatalf("expected error from second expression")
+	}
+}
+
+func TestBuiltQueriesMatchParsedQueries(t *testing.T) {
+	cases := []struct {
+		built *Query
+		src   string
+	}{
+		{And(Any("status", []string{"review", "backlog"}), Any("label", []string{"BACKEND"})), "status in (review,backlog) and label:backend"},
+		{Any("owner", []string{"unassigned"}), "owner:unassigned"},
+		{Any("title", []string{"*card*"}), "title:'*card*'"},
+		{And(Compare("updated", ">=", "2026-01-01"), Compare("priority", "<=", "high")), "updated >= 2026-01-01 and priority <= high"},
+	}
+	for _, tc := range cases {
+		want := matchIDs(t, tc.src, nil)
+		got := []string{}
+		for _, feat := range tc.built.Apply(sampleFeatures(), nil) {
+			got = append(got, feat.FrontMatter.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("%q: built query matched %v, parsed %v", tc.src, got, want)
+		}
+	}
+
+	if Any("status", []string{" ", ""}) != nil || Compare("updated", ">", " ") != nil || And(nil, nil) != nil {
+		t.Fatalf("blank criteria should build nil queries")
+	}
+}
+
+func TestNewEnv(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	locks := lock.NewManager(opts)
+	if _, err := locks.Acquire("FTR-0003", "bob", 10, false); err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	env := NewEnv(sampleFeatures(), workflow.Default(), locks)
+	if !env.Locked("FTR-0003") || !env.Locked("FTR-0003") || env.Locked("FTR-0001") {
+		t.Fatalf("unexpected lock facts")
+	}
+	if env.Unfinished("ftr-0001") || !env.Unfinished("FTR-0002") || !env.Unfinished("FTR-0404") {
+		t.Fatalf("unexpected dependency facts")
+	}
+}
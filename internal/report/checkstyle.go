@@ -0,0 +1,52 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle lists every document as a file element with its findings.
+func writeCheckstyle(w io.Writer, docs []Document) error {
+	root := checkstyleReport{Version: "4.3"}
+	for _, doc := range docs {
+		file := checkstyleFile{Name: doc.Path}
+		for _, f := range doc.Findings {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     f.Line,
+				Severity: string(f.Severity),
+				Message:  f.Message,
+				Source:   "vb." + f.Rule,
+			})
+		}
+		root.Files = append(root.Files, file)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/rules"
+)
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// githubCommand maps a severity to a GitHub Actions workflow command.
+func githubCommand(sev rules.Severity) string {
+	switch sev {
+	case rules.Error:
+		return "error"
+	case rules.Warning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// writeGitHub emits one workflow command per finding, which GitHub Actions turns
+// into an annotation on the file and line.
+func writeGitHub(w io.Writer, docs []Document) error {
+	for _, doc := range docs {
+		for _, f := range doc.Findings {
+			props := "file=" + propertyEscaper.Replace(doc.Path)
+			if f.Line > 0 {
+				props += fmt.Sprintf(",line=%d", f.Line)
+			}
+			props += ",title=" + propertyEscaper.Replace("vb validate ["+f.Rule+"]")
+			if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(f.Severity), props, dataEscaper.Replace(f.Message)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/rules"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit reports one test case per document, grouped into a suite per kind.
+// Error findings fail the case; other findings go to its system-out.
+func writeJUnit(w io.Writer, docs []Document) error {
+	root := junitSuites{Name: "vb validate"}
+	index := map[string]int{}
+	for _, doc := range docs {
+		suiteName := doc.Kind + "s"
+		i, ok := index[suiteName]
+		if !ok {
+			i = len(root.Suites)
+			index[suiteName] = i
+			root.Suites = append(root.Suites, junitSuite{Name: suiteName})
+		}
+		suite := &root.Suites[i]
+
+		tc := junitCase{Name: doc.Name, Classname: suiteName, File: doc.Path}
+		var failures, other []string
+		for _, f := range doc.Findings {
+			line := fmt.Sprintf("%s: %s %s", location(doc.Path, f.Line), f.Severity, f)
+			if f.Severity == rules.Error {
+				failures = append(failures, line)
+			} else {
+				other = append(other, line)
+			}
+		}
+		if len(failures) > 0 {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation error(s)", len(failures)),
+				Type:    "validation",
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+			root.Failures++
+		}
+		tc.SystemOut = strings.Join(other, "\n")
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		root.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
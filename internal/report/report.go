@@ -0,0 +1,80 @@
+// Package report renders validation findings in formats read by CI systems:
+// SARIF for code scanning, JUnit XML for test reports, GitHub Actions workflow
+// commands for inline annotations, and Checkstyle XML.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/rules"
+)
+
+// Supported formats.
+const (
+	SARIF      = "sarif"
+	JUnit      = "junit"
+	GitHub     = "github"
+	Checkstyle = "checkstyle"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{SARIF, JUnit, GitHub, Checkstyle}
+
+// Document is a validated file and its findings.
+type Document struct {
+	// Kind is "feature" or "spec".
+	Kind string
+	// Name is the feature ID or spec filename.
+	Name string
+	// Path is the file path, slash-separated and relative to the project root.
+	Path     string
+	Findings []rules.Finding
+}
+
+// Errors counts the document's error-severity findings.
+func (d Document) Errors() int {
+	count := 0
+	for _, f := range d.Findings {
+		if f.Severity == rules.Error {
+			count++
+		}
+	}
+	return count
+}
+
+// Supported reports whether format is one of Formats.
+func Supported(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Write renders the documents in the given format. Rules describe every rule that
+// ran, for formats that carry rule metadata.
+func Write(w io.Writer, format string, docs []Document, ruleset []rules.Rule) error {
+	switch format {
+	case SARIF:
+		return writeSARIF(w, docs, ruleset)
+	case JUnit:
+		return writeJUnit(w, docs)
+	case GitHub:
+		return writeGitHub(w, docs)
+	case Checkstyle:
+		return writeCheckstyle(w, docs)
+	default:
+		return fmt.Errorf("unknown format %q (allowed: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// location renders "path" or "path:line".
+func location(path string, line int) string {
+	if line > 0 {
+		return fmt.Sprintf("%s:%d", path, line)
+	}
+	return path
+}
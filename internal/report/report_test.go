@@ -0,0 +1,161 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/rules"
+	"github.com/virtualboard/vb-cli/internal/version"
+)
+
+func sampleDocs() []Document {
+	return []Document{
+		{Kind: "feature", Name: "FTR-0001", Path: ".virtualboard/features/backlog/FTR-0001-login.md", Findings: []rules.Finding{
+			{Rule: "schema", Severity: rules.Error, Message: "owner: Invalid type", Line: 5},
+			{Rule: "filename", Severity: rules.Error, Message: "filename 'a.md' should be 'b.md'"},
+			{Rule: "title-length", Severity: rules.Warning, Message: "title is long, 100%\nreally", Line: 3},
+		}},
+		{Kind: "feature", Name: "FTR-0002", Path: ".virtualboard/features/backlog/FTR-0002-ok.md"},
+		{Kind: "spec", Name: "tech-stack.md", Path: ".virtualboard/specs/tech-stack.md", Findings: []rules.Finding{
+			{Rule: "spec-type", Severity: rules.Info, Message: "spec_type 'x' is not a recognized type", Line: 2},
+		}},
+	}
+}
+
+func sampleRules() []rules.Rule {
+	return []rules.Rule{
+		{ID: "schema", Description: "Schema", Severity: rules.Error},
+		{ID: "title-length", Description: "Title", Severity: rules.Warning},
+		{ID: "spec-type", Description: "Type", Severity: rules.Info},
+		{ID: "dates", Description: "Dates", Severity: rules.Off},
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, SARIF, sampleDocs(), sampleRules()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "vb" || run.Tool.Driver.Version != version.Current || len(run.Tool.Driver.Rules) != 4 {
+		t.Fatalf("unexpected driver %+v", run.Tool.Driver)
+	}
+	levels := []string{}
+	for _, r := range run.Tool.Driver.Rules {
+		levels = append(levels, r.DefaultConfiguration.Level)
+	}
+	if strings.Join(levels, ",") != "error,warning,note,none" {
+		t.Fatalf("unexpected rule levels %v", levels)
+	}
+	if len(run.Results) != 4 {
+		t.Fatalf("expected 4 results, got %+v", run.Results)
+	}
+	first := run.Results[0]
+	if first.RuleID != "schema" || first.Level != "error" || first.Locations[0].PhysicalLocation.ArtifactLocation.URI != ".virtualboard/features/backlog/FTR-0001-login.md" || first.Locations[0].PhysicalLocation.Region.StartLine != 5 {
+		t.Fatalf("unexpected first result %+v", first)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Fatalf("expected no region for a file-level finding")
+	}
+	if run.Results[3].Level != "note" {
+		t.Fatalf("expected info to map to note, got %s", run.Results[3].Level)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, JUnit, sampleDocs(), nil); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Fatalf("expected xml header:\n%s", out)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, out)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || len(suites.Suites) != 2 || suites.Suites[0].Name != "features" || suites.Suites[1].Tests != 1 {
+		t.Fatalf("unexpected suites %+v", suites)
+	}
+	login := suites.Suites[0].Cases[0]
+	if login.Failure == nil || login.Failure.Message != "2 validation error(s)" ||
+		login.Failure.Text != ".virtualboard/features/backlog/FTR-0001-login.md:5: error [schema] owner: Invalid type\n.virtualboard/features/backlog/FTR-0001-login.md: error [filename] filename 'a.md' should be 'b.md'" {
+		t.Fatalf("unexpected failure %+v", login.Failure)
+	}
+	if !strings.Contains(login.SystemOut, "FTR-0001-login.md:3: warning [title-length]") {
+		t.Fatalf("expected warning in system-out, got %q", login.SystemOut)
+	}
+	if ok := suites.Suites[0].Cases[1]; ok.Failure != nil || ok.SystemOut != "" || ok.File == "" {
+		t.Fatalf("expected a passing case, got %+v", ok)
+	}
+}
+
+func TestWriteCheckstyle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Checkstyle, sampleDocs(), nil); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<checkstyle version="4.3">`,
+		`<file name=".virtualboard/features/backlog/FTR-0001-login.md">`,
+		`<error line="5" severity="error" message="owner: Invalid type" source="vb.schema"></error>`,
+		`<error severity="error" message="filename &#39;a.md&#39; should be &#39;b.md&#39;" source="vb.filename"></error>`,
+		`<file name=".virtualboard/features/backlog/FTR-0002-ok.md"></file>`,
+		`<error line="2" severity="info"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, GitHub, sampleDocs(), nil); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	want := "::error file=.virtualboard/features/backlog/FTR-0001-login.md,line=5,title=vb validate [schema]::owner: Invalid type\n" +
+		"::error file=.virtualboard/features/backlog/FTR-0001-login.md,title=vb validate [filename]::filename 'a.md' should be 'b.md'\n" +
+		"::warning file=.virtualboard/features/backlog/FTR-0001-login.md,line=3,title=vb validate [title-length]::title is long, 100%25%0Areally\n" +
+		"::notice file=.virtualboard/specs/tech-stack.md,line=2,title=vb validate [spec-type]::spec_type 'x' is not a recognized type\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if got := propertyEscaper.Replace("a:b,c"); got != "a%3Ab%2Cc" {
+		t.Fatalf("unexpected property escaping %q", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteErrors(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "html", nil, nil); err == nil || !strings.Contains(err.Error(), "allowed: sarif, junit, github, checkstyle") {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+	for _, format := range Formats {
+		if err := Write(failingWriter{}, format, sampleDocs(), nil); err == nil {
+			t.Fatalf("%s: expected write error", format)
+		}
+	}
+	if !Supported("junit") || Supported("text") {
+		t.Fatalf("unexpected Supported result")
+	}
+	if sampleDocs()[0].Errors() != 2 || sampleDocs()[1].Errors() != 0 {
+		t.Fatalf("unexpected error counts")
+	}
+}
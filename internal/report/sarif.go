@@ -0,0 +1,129 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/virtualboard/vb-cli/internal/rules"
+	"github.com/virtualboard/vb-cli/internal/version"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/virtualboard/vb-cli"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(sev rules.Severity) string {
+	switch sev {
+	case rules.Error:
+		return "error"
+	case rules.Warning:
+		return "warning"
+	case rules.Info:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+func writeSARIF(w io.Writer, docs []Document, ruleset []rules.Rule) error {
+	driver := sarifDriver{
+		Name:           "vb",
+		Version:        version.Current,
+		InformationURI: toolURI,
+		Rules:          []sarifRule{},
+	}
+	for _, r := range ruleset {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   r.ID,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(r.Severity)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, doc := range docs {
+		for _, f := range doc.Findings {
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: doc.Path}}
+			if f.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: f.Line}
+			}
+			results = append(results, sarifResult{
+				RuleID:    f.Rule,
+				Level:     sarifLevel(f.Severity),
+				Message:   sarifMessage{Text: f.Message},
+				Locations: []sarifLocation{{PhysicalLocation: loc}},
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var (
+	keyPattern     = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*:`)
+	headingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+)
+
+// Locator maps frontmatter keys and Markdown headings to their line numbers.
+type Locator struct {
+	keys     map[string]int
+	headings map[string]int
+}
+
+// NewLocator indexes a document made of "---" delimited YAML frontmatter and a
+// Markdown body. Headings inside fenced code blocks are ignored.
+func NewLocator(content []byte) *Locator {
+	l := &Locator{keys: map[string]int{}, headings: map[string]int{}}
+	lines := strings.Split(string(content), "\n")
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+			if m := keyPattern.FindStringSubmatch(lines[i]); m != nil {
+				if _, seen := l.keys[m[1]]; !seen {
+					l.keys[m[1]] = i + 1
+				}
+			}
+		}
+	}
+	fenced := false
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(lines[i]); m != nil {
+			name := strings.ToLower(m[1])
+			if _, seen := l.headings[name]; !seen {
+				l.headings[name] = i + 1
+			}
+		}
+	}
+	return l
+}
+
+// Key returns the line of a top-level frontmatter key, or 0 when it is absent.
+func (l *Locator) Key(name string) int {
+	return l.keys[name]
+}
+
+// Heading returns the line of the first heading with the given text, ignoring
+// case, or 0 when there is none.
+func (l *Locator) Heading(name string) int {
+	return l.headings[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Line locates a problem: its heading when present, else its frontmatter key.
+func (l *Locator) Line(p Problem) int {
+	if p.Heading != "" {
+		if line := l.Heading(p.Heading); line > 0 {
+			return line
+		}
+	}
+	if p.Key != "" {
+		return l.Key(p.Key)
+	}
+	return 0
+}
+
+// SchemaKey returns the top-level frontmatter key a JSON schema error concerns,
+// or "" for errors about the document as a whole.
+func SchemaKey(desc gojsonschema.ResultError) string {
+	field := desc.Field()
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		if property, ok := desc.Details()["property"].(string); ok {
+			return property
+		}
+		return ""
+	}
+	return strings.SplitN(field, ".", 2)[0]
+}
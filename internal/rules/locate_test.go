@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestLocator(t *testing.T) {
+	doc := "---\nid: FTR-0001\ntitle: Login\nlabels:\n  - auth\nstatus: backlog\nstatus: duplicate\n---\n# Login\n\n## Acceptance Criteria\n```\n## Not a heading\n```\n## Notes ##\n"
+	l := NewLocator([]byte(doc))
+	cases := []struct {
+		problem Problem
+		want    int
+	}{
+		{Problem{Key: "id"}, 2},
+		{Problem{Key: "status"}, 6},
+		{Problem{Key: "auth"}, 0},
+		{Problem{Key: "missing"}, 0},
+		{Problem{Heading: "acceptance criteria", Key: "status"}, 11},
+		{Problem{Heading: "Notes"}, 15},
+		{Problem{Heading: "Not a heading", Key: "title"}, 3},
+		{Problem{}, 0},
+	}
+	for _, tc := range cases {
+		if got := l.Line(tc.problem); got != tc.want {
+			t.Fatalf("Line(%+v) = %d, want %d", tc.problem, got, tc.want)
+		}
+	}
+	if NewLocator([]byte("# Title\nid: x\n")).Key("id") != 0 {
+		t.Fatalf("expected keys outside frontmatter to be ignored")
+	}
+}
+
+func TestSchemaKey(t *testing.T) {
+	schema := gojsonschema.NewStringLoader(`{"type":"object","required":["id"],"properties":{"labels":{"type":"array","items":{"type":"string"}}},"not":{"required":["forbidden"]}}`)
+	doc := gojsonschema.NewGoLoader(map[string]interface{}{"labels": []interface{}{1}, "forbidden": true})
+	result, err := gojsonschema.Validate(schema, doc)
+	if err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	keys := map[string]bool{}
+	for _, desc := range result.Errors() {
+		keys[SchemaKey(desc)] = true
+	}
+	if !keys["id"] || !keys["labels"] || !keys[""] {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+}
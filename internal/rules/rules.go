@@ -0,0 +1,266 @@
+// Package rules provides the registry of named, configurable checks run by vb validate.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is how a finding affects validation.
+type Severity string
+
+// Supported severities. Only errors fail validation; Off disables a rule.
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+	Off     Severity = "off"
+)
+
+var severities = []Severity{Error, Warning, Info, Off}
+
+// ParseSeverity converts a configured severity, ignoring case.
+func ParseSeverity(value string) (Severity, error) {
+	sev := Severity(strings.ToLower(strings.TrimSpace(value)))
+	for _, known := range severities {
+		if sev == known {
+			return sev, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity %q (allowed: error, warning, info, off)", value)
+}
+
+// Rule is a named check with a default severity and options.
+type Rule struct {
+	ID          string                 `json:"id"`
+	Description string                 `json:"description"`
+	Severity    Severity               `json:"severity"`
+	Options     map[string]interface{} `json:"options,omitempty"`
+}
+
+// Int returns an integer option, or 0 when it is unset.
+func (r *Rule) Int(key string) int {
+	value, _ := r.Options[key].(int)
+	return value
+}
+
+// Finding is a problem reported by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Line is the 1-based line the finding concerns, or 0 for the whole file.
+	Line int `json:"line,omitempty"`
+}
+
+// String renders the finding as "[rule] message".
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s", f.Rule, f.Message)
+}
+
+// Problem is what a check reports, before the rule's severity is applied.
+type Problem struct {
+	Message string
+	// Key is the frontmatter key the problem concerns, if any.
+	Key string
+	// Heading is the Markdown heading the problem concerns, if any; it takes
+	// precedence over Key when the heading exists.
+	Heading string
+}
+
+// Override changes a rule's severity and options. In YAML it is either a bare
+// severity (`filename: warning`) or a block (`title-length: {severity: error, max: 60}`).
+type Override struct {
+	Severity Severity               `yaml:"severity" json:"severity,omitempty"`
+	Options  map[string]interface{} `yaml:",inline" json:"options,omitempty"`
+}
+
+// UnmarshalYAML accepts both the short and the block form.
+func (o *Override) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		o.Severity = Severity(node.Value)
+		return nil
+	}
+	type plain Override
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*o = Override(p)
+	return nil
+}
+
+// Overrides maps rule IDs to their configured overrides.
+type Overrides map[string]Override
+
+// Normalize lowercases rule IDs and validates severities.
+func (o Overrides) Normalize() error {
+	for id, override := range o {
+		if override.Severity != "" {
+			sev, err := ParseSeverity(string(override.Severity))
+			if err != nil {
+				return fmt.Errorf("rule %s: %w", id, err)
+			}
+			override.Severity = sev
+		}
+		delete(o, id)
+		o[strings.ToLower(strings.TrimSpace(id))] = override
+	}
+	return nil
+}
+
+// Set is an ordered registry of rules with their effective severities and options.
+type Set struct {
+	rules []*Rule
+	byID  map[string]*Rule
+}
+
+// NewSet registers copies of the given rules.
+func NewSet(defs []Rule) *Set {
+	s := &Set{byID: map[string]*Rule{}}
+	for _, def := range defs {
+		r := def
+		r.Options = make(map[string]interface{}, len(def.Options))
+		for key, value := range def.Options {
+			r.Options[key] = value
+		}
+		s.rules = append(s.rules, &r)
+		s.byID[r.ID] = &r
+	}
+	return s
+}
+
+// Rules returns the registered rules in registration order.
+func (s *Set) Rules() []Rule {
+	out := make([]Rule, 0, len(s.rules))
+	for _, r := range s.rules {
+		out = append(out, *r)
+	}
+	return out
+}
+
+// Lookup returns the rule with the given ID.
+func (s *Set) Lookup(id string) (*Rule, bool) {
+	r, ok := s.byID[id]
+	return r, ok
+}
+
+// Enabled reports whether the rule is registered and not turned off.
+func (s *Set) Enabled(id string) bool {
+	r, ok := s.byID[id]
+	return ok && r.Severity != Off
+}
+
+// Configure applies overrides. IDs the set does not define are ignored so that one
+// settings block can configure several sets; options must be declared by the rule.
+func (s *Set) Configure(overrides Overrides) error {
+	for id, override := range overrides {
+		r, ok := s.byID[id]
+		if !ok {
+			continue
+		}
+		if override.Severity != "" {
+			r.Severity = override.Severity
+		}
+		for key, value := range override.Options {
+			def, declared := r.Options[key]
+			if !declared {
+				return fmt.Errorf("rule %s has no option %s", id, key)
+			}
+			if _, isInt := def.(int); isInt {
+				if _, ok := value.(int); !ok {
+					return fmt.Errorf("rule %s option %s must be an integer", id, key)
+				}
+			}
+			r.Options[key] = value
+		}
+	}
+	return nil
+}
+
+// Select narrows the set for a run: with only, every other rule is turned off;
+// rules in disabled are turned off. Unknown IDs are ignored.
+func (s *Set) Select(only, disabled []string) {
+	if len(only) > 0 {
+		keep := map[string]bool{}
+		for _, id := range only {
+			keep[strings.ToLower(strings.TrimSpace(id))] = true
+		}
+		for _, r := range s.rules {
+			if !keep[r.ID] {
+				r.Severity = Off
+			}
+		}
+	}
+	for _, id := range disabled {
+		if r, ok := s.byID[strings.ToLower(strings.TrimSpace(id))]; ok {
+			r.Severity = Off
+		}
+	}
+}
+
+// Finding builds a finding for the rule at its effective severity. It reports false
+// when the rule is off or suppressed.
+func (s *Set) Finding(id, message string, suppressed map[string]bool) (Finding, bool) {
+	r, ok := s.byID[id]
+	if !ok || r.Severity == Off || suppressed[id] {
+		return Finding{}, false
+	}
+	return Finding{Rule: id, Severity: r.Severity, Message: message}, true
+}
+
+// Unknown returns the IDs, sorted, that none of the sets define.
+func Unknown(ids []string, sets ...*Set) []string {
+	var unknown []string
+	for _, id := range ids {
+		id = strings.ToLower(strings.TrimSpace(id))
+		found := false
+		for _, s := range sets {
+			if _, ok := s.byID[id]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// DisableKey is the frontmatter key listing rules suppressed for a document.
+const DisableKey = "vb-disable"
+
+var directivePattern = regexp.MustCompile(`<!--\s*vb-disable\s+([^>]*?)\s*-->`)
+
+// Suppressed collects the rule IDs a document disables, from a DisableKey
+// frontmatter value (a string or list) and `<!-- vb-disable id ... -->` comments.
+func Suppressed(frontmatter interface{}, body string) map[string]bool {
+	ids := map[string]bool{}
+	add := func(text string) {
+		for _, id := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			ids[strings.ToLower(id)] = true
+		}
+	}
+	switch value := frontmatter.(type) {
+	case string:
+		add(value)
+	case []string:
+		for _, item := range value {
+			add(item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			add(fmt.Sprint(item))
+		}
+	}
+	for _, m := range directivePattern.FindAllStringSubmatch(body, -1) {
+		add(m[1])
+	}
+	return ids
+}
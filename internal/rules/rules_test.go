@@ -0,0 +1,127 @@
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func testSet() *Set {
+	return NewSet([]Rule{
+		{ID: "schema", Description: "Schema", Severity: Error},
+		{ID: "title-length", Description: "Title", Severity: Warning, Options: map[string]interface{}{"max": 72, "style": "plain"}},
+	})
+}
+
+func TestParseSeverity(t *testing.T) {
+	if sev, err := ParseSeverity(" Warning "); err != nil || sev != Warning {
+		t.Fatalf("unexpected severity %q %v", sev, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil || !strings.Contains(err.Error(), "allowed: error, warning, info, off") {
+		t.Fatalf("expected unknown severity error, got %v", err)
+	}
+}
+
+func TestOverridesYAML(t *testing.T) {
+	var overrides Overrides
+	data := "Filename: Warning\ntitle-length:\n  severity: error\n  max: 60\n"
+	if err := yaml.Unmarshal([]byte(data), &overrides); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if err := overrides.Normalize(); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	want := Overrides{
+		"filename":     {Severity: Warning},
+		"title-length": {Severity: Error, Options: map[string]interface{}{"max": 60}},
+	}
+	if !reflect.DeepEqual(overrides, want) {
+		t.Fatalf("unexpected overrides %#v", overrides)
+	}
+
+	if err := yaml.Unmarshal([]byte("schema: [error]\n"), &overrides); err == nil {
+		t.Fatalf("expected decode error for a list")
+	}
+	if err := (Overrides{"schema": {Severity: "loud"}}).Normalize(); err == nil || !strings.Contains(err.Error(), "rule schema") {
+		t.Fatalf("expected severity error, got %v", err)
+	}
+}
+
+func TestSetConfigureAndSelect(t *testing.T) {
+	set := testSet()
+	if err := set.Configure(Overrides{
+		"title-length": {Severity: Error, Options: map[string]interface{}{"max": 40}},
+		"spec-schema":  {Severity: Off},
+	}); err != nil {
+		t.Fatalf("configure failed: %v", err)
+	}
+	r, ok := set.Lookup("title-length")
+	if !ok || r.Severity != Error || r.Int("max") != 40 || r.Int("style") != 0 {
+		t.Fatalf("unexpected rule %+v", r)
+	}
+	if fresh := testSet(); fresh.Rules()[1].Int("max") != 72 {
+		t.Fatalf("expected defaults to be copied, got %+v", fresh.Rules()[1])
+	}
+
+	if err := set.Configure(Overrides{"title-length": {Options: map[string]interface{}{"min": 1}}}); err == nil || !strings.Contains(err.Error(), "has no option min") {
+		t.Fatalf("expected unknown option error, got %v", err)
+	}
+	if err := set.Configure(Overrides{"title-length": {Options: map[string]interface{}{"max": "long"}}}); err == nil || !strings.Contains(err.Error(), "must be an integer") {
+		t.Fatalf("expected type error, got %v", err)
+	}
+
+	set.Select([]string{" Schema", "title-length"}, []string{"TITLE-LENGTH", "missing"})
+	if !set.Enabled("schema") || set.Enabled("title-length") || set.Enabled("missing") {
+		t.Fatalf("unexpected selection %+v", set.Rules())
+	}
+	set.Select([]string{"title-length"}, nil)
+	if set.Enabled("schema") {
+		t.Fatalf("expected --rule to turn other rules off")
+	}
+}
+
+func TestSetFinding(t *testing.T) {
+	set := testSet()
+	f, ok := set.Finding("title-length", "too long", nil)
+	if !ok || f.Severity != Warning || f.String() != "[title-length] too long" {
+		t.Fatalf("unexpected finding %+v %v", f, ok)
+	}
+	if _, ok := set.Finding("schema", "bad", map[string]bool{"schema": true}); ok {
+		t.Fatalf("expected suppressed finding to be dropped")
+	}
+	if _, ok := set.Finding("unknown", "bad", nil); ok {
+		t.Fatalf("expected unknown rule to be dropped")
+	}
+	set.Select(nil, []string{"schema"})
+	if _, ok := set.Finding("schema", "bad", nil); ok {
+		t.Fatalf("expected disabled rule to be dropped")
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	other := NewSet([]Rule{{ID: "spec-schema", Severity: Error}})
+	got := Unknown([]string{"schema", "Spec-Schema", "typo", "also-bad"}, testSet(), other)
+	if !reflect.DeepEqual(got, []string{"also-bad", "typo"}) {
+		t.Fatalf("unexpected unknown ids %v", got)
+	}
+}
+
+func TestSuppressed(t *testing.T) {
+	body := "Intro\n<!-- vb-disable Filename, dates -->\n\n<!--vb-disable title-length-->\n<!-- unrelated -->\n"
+	got := Suppressed([]interface{}{"schema", "epic-ref"}, body)
+	want := map[string]bool{"schema": true, "epic-ref": true, "filename": true, "dates": true, "title-length": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected suppressions %v", got)
+	}
+	if got := Suppressed("schema status-rules", ""); !got["schema"] || !got["status-rules"] || len(got) != 2 {
+		t.Fatalf("unexpected string suppressions %v", got)
+	}
+	if got := Suppressed([]string{"schema"}, ""); !got["schema"] {
+		t.Fatalf("unexpected list suppressions %v", got)
+	}
+	if got := Suppressed(42, ""); len(got) != 0 {
+		t.Fatalf("expected no suppressions, got %v", got)
+	}
+}
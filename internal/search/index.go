@@ -0,0 +1,441 @@
+// Package search keeps a persistent inverted index over feature and spec documents
+// and answers ranked full-text queries against it.
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// indexVersion is bumped whenever tokenisation or the on-disk layout changes, so
+// older caches are discarded instead of misread.
+const indexVersion = 1
+
+// Document kinds.
+const (
+	KindFeature = "feature"
+	KindSpec    = "spec"
+)
+
+// Field names that are not body sections.
+const (
+	FieldTitle       = "title"
+	FieldFrontmatter = "frontmatter"
+	FieldIntro       = "intro"
+)
+
+// Relative weights of matches by field; every body section weighs 1.
+var fieldWeights = map[string]float64{
+	FieldTitle:       4,
+	FieldFrontmatter: 2,
+}
+
+// Field is a searchable part of a document: its title, frontmatter or one body section.
+type Field struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// Document is one indexed file. Path is slash-separated and relative to the workspace.
+type Document struct {
+	Path    string  `json:"path"`
+	Kind    string  `json:"kind"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Status  string  `json:"status,omitempty"`
+	ModTime int64   `json:"mtime"`
+	Size    int64   `json:"size"`
+	Fields  []Field `json:"fields"`
+}
+
+// Posting records how often a term occurs in one field of a document.
+type Posting struct {
+	Doc   string `json:"d"`
+	Field int    `json:"f"`
+	Freq  int    `json:"n"`
+}
+
+// Index maps terms to the documents and fields containing them.
+type Index struct {
+	Version int                   `json:"version"`
+	Docs    map[string]*Document  `json:"docs"`
+	Terms   map[string][]*Posting `json:"terms"`
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{
+		Version: indexVersion,
+		Docs:    map[string]*Document{},
+		Terms:   map[string][]*Posting{},
+	}
+}
+
+// Load reads an index written by Save. A missing, unreadable-as-JSON or outdated
+// cache yields an empty index, since it can always be rebuilt from the workspace.
+func Load(path string) (*Index, error) {
+	// #nosec G304 -- cache path is derived from the workspace root
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return NewIndex(), nil
+		}
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	ix := NewIndex()
+	if err := json.Unmarshal(data, ix); err != nil || ix.Version != indexVersion || ix.Docs == nil || ix.Terms == nil {
+		return NewIndex(), nil
+	}
+	return ix, nil
+}
+
+// Save writes the index to path, creating its directory with a .gitignore so the
+// cache is never committed.
+func (ix *Index) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, os.ErrNotExist) {
+		if err := util.WriteFileAtomic(ignore, []byte("*\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to write cache .gitignore: %w", err)
+		}
+	}
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := util.WriteFileAtomic(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// Put adds doc, replacing any document previously indexed under the same path.
+func (ix *Index) Put(doc *Document) {
+	ix.Remove(doc.Path)
+	ix.Docs[doc.Path] = doc
+	for i, field := range doc.Fields {
+		counts := map[string]int{}
+		for _, term := range Tokenize(field.Text) {
+			counts[term]++
+		}
+		for term, n := range counts {
+			ix.Terms[term] = append(ix.Terms[term], &Posting{Doc: doc.Path, Field: i, Freq: n})
+		}
+	}
+}
+
+// Remove drops the document indexed under path, if any.
+func (ix *Index) Remove(path string) {
+	doc, ok := ix.Docs[path]
+	if !ok {
+		return
+	}
+	delete(ix.Docs, path)
+	seen := map[string]bool{}
+	for _, field := range doc.Fields {
+		for _, term := range Tokenize(field.Text) {
+			if seen[term] {
+				continue
+			}
+			seen[term] = true
+			kept := ix.Terms[term][:0]
+			for _, p := range ix.Terms[term] {
+				if p.Doc != path {
+					kept = append(kept, p)
+				}
+			}
+			if len(kept) == 0 {
+				delete(ix.Terms, term)
+			} else {
+				ix.Terms[term] = kept
+			}
+		}
+	}
+}
+
+// Options narrows a search.
+type Options struct {
+	// Kind restricts results to KindFeature or KindSpec; empty means both.
+	Kind string
+	// Limit caps the number of results; zero or less means no limit.
+	Limit int
+}
+
+// Result is a ranked match with the best-matching field and a snippet from it.
+type Result struct {
+	Doc     *Document
+	Score   float64
+	Section string
+	Snippet string
+}
+
+// Search returns documents containing every term of q, best first. Quoted phrases
+// must also appear verbatim (ignoring case and spacing) in one field, and a term
+// ending in * matches any term with that prefix.
+func (ix *Index) Search(q string, opts Options) []Result {
+	terms, phrases := parseQuery(q)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	total := float64(len(ix.Docs))
+	scores := map[string]float64{}
+	fieldScores := map[string]map[int]float64{}
+	for i, term := range terms {
+		postings := ix.postings(term)
+		docs := map[string]bool{}
+		for _, p := range postings {
+			docs[p.Doc] = true
+		}
+		idf := math.Log(1 + total/float64(len(docs)+1))
+		next := map[string]float64{}
+		for _, p := range postings {
+			if i > 0 {
+				if _, ok := scores[p.Doc]; !ok {
+					continue
+				}
+			}
+			doc := ix.Docs[p.Doc]
+			if opts.Kind != "" && doc.Kind != opts.Kind {
+				continue
+			}
+			s := weight(doc.Fields[p.Field].Name) * (1 + math.Log(float64(p.Freq))) * idf
+			next[p.Doc] += s
+			if fieldScores[p.Doc] == nil {
+				fieldScores[p.Doc] = map[int]float64{}
+			}
+			fieldScores[p.Doc][p.Field] += s
+		}
+		for path := range next {
+			next[path] += scores[path]
+		}
+		scores = next
+	}
+
+	results := make([]Result, 0, len(scores))
+	for path, score := range scores {
+		doc := ix.Docs[path]
+		if !containsPhrases(doc, phrases) {
+			continue
+		}
+		best := bestField(fieldScores[path], doc, phrases)
+		field := doc.Fields[best]
+		results = append(results, Result{
+			Doc:     doc,
+			Score:   math.Round(score*100) / 100,
+			Section: field.Name,
+			Snippet: Snippet(field.Text, terms, 160),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Doc.Path < results[j].Doc.Path
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results
+}
+
+// postings returns the postings for term, expanding a trailing * to every indexed
+// term with that prefix.
+func (ix *Index) postings(term string) []*Posting {
+	if !strings.HasSuffix(term, "*") {
+		return ix.Terms[term]
+	}
+	prefix := strings.TrimSuffix(term, "*")
+	var out []*Posting
+	for indexed, postings := range ix.Terms {
+		if strings.HasPrefix(indexed, prefix) {
+			out = append(out, postings...)
+		}
+	}
+	return out
+}
+
+func weight(field string) float64 {
+	if w, ok := fieldWeights[field]; ok {
+		return w
+	}
+	return 1
+}
+
+// bestField picks the highest-scoring field, preferring one that contains a phrase.
+func bestField(scores map[int]float64, doc *Document, phrases []string) int {
+	best, bestScore := -1, -1.0
+	for i, score := range scores {
+		if len(phrases) > 0 && !hasPhrase(doc.Fields[i].Text, phrases) {
+			score /= 10
+		}
+		if score > bestScore || (score == bestScore && i < best) {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// containsPhrases reports whether every phrase appears in some field of doc.
+func containsPhrases(doc *Document, phrases []string) bool {
+	for _, phrase := range phrases {
+		found := false
+		for _, field := range doc.Fields {
+			if hasPhrase(field.Text, []string{phrase}) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPhrase(text string, phrases []string) bool {
+	text = normalizeSpace(text)
+	for _, phrase := range phrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// parseQuery splits q into search terms and quoted phrases; phrase words are also
+// returned as terms so the index can narrow candidates. Outside quotes, a word
+// ending in * becomes a prefix term.
+func parseQuery(q string) (terms, phrases []string) {
+	seen := map[string]bool{}
+	for i, part := range strings.Split(q, "\"") {
+		quoted := i%2 == 1
+		if quoted {
+			if phrase := normalizeSpace(part); phrase != "" {
+				phrases = append(phrases, phrase)
+			}
+		}
+		for _, word := range strings.Fields(part) {
+			prefix := !quoted && strings.HasSuffix(word, "*")
+			words := splitWords(word)
+			for j, w := range words {
+				w = strings.ToLower(w)
+				term := stem(w)
+				if prefix && j == len(words)-1 {
+					term = w + "*"
+				} else if stopWords[w] {
+					continue
+				}
+				if !seen[term] {
+					seen[term] = true
+					terms = append(terms, term)
+				}
+			}
+		}
+	}
+	return terms, phrases
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+// Tokenize lowercases text, splits it into words, drops stop words and reduces each
+// word to a crude stem so "limits", "limited" and "limiting" all match "limit".
+func Tokenize(text string) []string {
+	words := splitWords(text)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(word)
+		if stopWords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) && !strings.HasSuffix(word, "ss") {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+// Snippet returns up to width characters of text around the first word matching
+// one of terms, with whitespace collapsed and … marking cut ends.
+func Snippet(text string, terms []string, width int) string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return ""
+	}
+	hit := 0
+	for i, word := range words {
+		if matchesAny(word, terms) {
+			hit = i
+			break
+		}
+	}
+
+	// Keep about a third of the window before the hit and fill the rest after it.
+	start, end := hit, hit+1
+	length := len(words[hit])
+	for start > 0 && length+len(words[start-1])+1 <= width/3 {
+		start--
+		length += len(words[start]) + 1
+	}
+	for end < len(words) && length+len(words[end])+1 <= width {
+		length += len(words[end]) + 1
+		end++
+	}
+	for start > 0 && length+len(words[start-1])+1 <= width {
+		start--
+		length += len(words[start]) + 1
+	}
+
+	snippet := strings.Join(words[start:end], " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(words) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+func matchesAny(word string, terms []string) bool {
+	for _, token := range Tokenize(word) {
+		for _, term := range terms {
+			if token == term || (strings.HasSuffix(term, "*") && strings.HasPrefix(token, strings.TrimSuffix(term, "*"))) {
+				return true
+			}
+		}
+	}
+	return false
+}
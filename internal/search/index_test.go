@@ -0,0 +1,179 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testIndex() *Index {
+	ix := NewIndex()
+	ix.Put(&Document{Path: "features/backlog/FTR-0001-rate-limits.md", Kind: KindFeature, ID: "FTR-0001", Title: "Rate limits", Fields: []Field{
+		{Name: FieldTitle, Text: "Rate limits"},
+		{Name: FieldFrontmatter, Text: "labels: api"},
+		{Name: "Summary", Text: "Add rate limiting to the public API so a single client cannot starve others."},
+	}})
+	ix.Put(&Document{Path: "features/done/FTR-0002-login.md", Kind: KindFeature, ID: "FTR-0002", Title: "Login", Fields: []Field{
+		{Name: FieldTitle, Text: "Login"},
+		{Name: "Technical Approach", Text: "Sessions expire; the limit on retries is applied per rate window."},
+	}})
+	ix.Put(&Document{Path: "specs/api.md", Kind: KindSpec, ID: "api.md", Title: "API", Fields: []Field{
+		{Name: FieldTitle, Text: "API"},
+		{Name: "Throttling", Text: "All endpoints enforce rate limiting with a token bucket."},
+	}})
+	return ix
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The Rate-Limiting of limits, limited & FTR-0001 process")
+	want := []string{"rate", "limit", "limit", "limit", "ftr", "0001", "process"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	terms, phrases := parseQuery(`the "Rate  Limiting" auth* api rate`)
+	if !reflect.DeepEqual(terms, []string{"rate", "limit", "auth*", "api"}) {
+		t.Fatalf("unexpected terms %v", terms)
+	}
+	if !reflect.DeepEqual(phrases, []string{"rate limiting"}) {
+		t.Fatalf("unexpected phrases %v", phrases)
+	}
+	if terms, _ := parseQuery(`the "" of`); len(terms) != 0 {
+		t.Fatalf("expected only stop words to yield no terms, got %v", terms)
+	}
+}
+
+func TestSearchRanksAndFilters(t *testing.T) {
+	ix := testIndex()
+
+	results := ix.Search("rate limiting", Options{})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Doc.ID != "FTR-0001" || results[0].Section != FieldTitle {
+		t.Fatalf("expected title match first, got %+v", results[0])
+	}
+
+	results = ix.Search(`"rate limiting"`, Options{})
+	if len(results) != 2 {
+		t.Fatalf("expected phrase to exclude scattered terms, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Section == FieldTitle {
+			t.Fatalf("expected section containing the phrase, got %+v", r)
+		}
+	}
+
+	results = ix.Search("rate limiting", Options{Kind: KindSpec})
+	if len(results) != 1 || results[0].Doc.ID != "api.md" || results[0].Section != "Throttling" {
+		t.Fatalf("unexpected spec results %+v", results)
+	}
+	if !strings.Contains(results[0].Snippet, "rate limiting") {
+		t.Fatalf("expected snippet around the match, got %q", results[0].Snippet)
+	}
+
+	if results = ix.Search("rate", Options{Limit: 1}); len(results) != 1 {
+		t.Fatalf("expected limit to apply, got %d", len(results))
+	}
+	if results = ix.Search("sess*", Options{}); len(results) != 1 || results[0].Doc.ID != "FTR-0002" {
+		t.Fatalf("expected prefix match, got %+v", results)
+	}
+	if results = ix.Search("rate bucket", Options{}); len(results) != 1 {
+		t.Fatalf("expected every term to be required, got %d", len(results))
+	}
+	if results = ix.Search("the", Options{}); results != nil {
+		t.Fatalf("expected no results for stop words, got %+v", results)
+	}
+}
+
+func TestPutReplacesAndRemove(t *testing.T) {
+	ix := testIndex()
+	ix.Put(&Document{Path: "specs/api.md", Kind: KindSpec, ID: "api.md", Fields: []Field{{Name: FieldTitle, Text: "Gateway"}}})
+	if _, ok := ix.Terms["bucket"]; ok {
+		t.Fatalf("expected replaced document terms to be dropped")
+	}
+	if len(ix.Terms["rate"]) != 3 {
+		t.Fatalf("expected other postings to remain, got %d", len(ix.Terms["rate"]))
+	}
+	ix.Remove("specs/api.md")
+	ix.Remove("specs/missing.md")
+	if _, ok := ix.Terms["gateway"]; ok || len(ix.Docs) != 2 {
+		t.Fatalf("expected document to be removed")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("filler ", 40) + "the rate limit applies " + strings.Repeat("tail ", 40)
+	got := Snippet(text, []string{"limit"}, 60)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "rate limit applies") {
+		t.Fatalf("unexpected snippet %q", got)
+	}
+	if len(got) > 70 {
+		t.Fatalf("snippet too long: %d", len(got))
+	}
+	if got := Snippet("short text", []string{"missing"}, 60); got != "short text" {
+		t.Fatalf("expected whole short text, got %q", got)
+	}
+	if got := Snippet("  ", nil, 60); got != "" {
+		t.Fatalf("expected empty snippet, got %q", got)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".cache", "search-index.json")
+
+	ix, err := Load(path)
+	if err != nil || len(ix.Docs) != 0 {
+		t.Fatalf("expected empty index for missing cache: %v", err)
+	}
+	if err := testIndex().Save(path); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, ".cache", ".gitignore")); err != nil || string(data) != "*\n" {
+		t.Fatalf("expected cache .gitignore: %v %q", err, data)
+	}
+	ix, err = Load(path)
+	if err != nil || len(ix.Docs) != 3 || len(ix.Search("bucket", Options{})) != 1 {
+		t.Fatalf("expected saved index to round-trip: %v", err)
+	}
+
+	for _, data := range []string{"{not json", `{"version": 99, "docs": {}, "terms": {}}`} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if ix, err := Load(path); err != nil || len(ix.Docs) != 0 {
+			t.Fatalf("expected stale cache to be discarded: %v", err)
+		}
+	}
+
+	if _, err := Load(dir); err == nil {
+		t.Fatalf("expected read error for a directory")
+	}
+}
+
+func TestSaveErrors(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := NewIndex().Save(filepath.Join(blocker, "index.json")); err == nil {
+		t.Fatalf("expected mkdir error")
+	}
+
+	cache := filepath.Join(dir, "cache")
+	if err := os.MkdirAll(filepath.Join(cache, ".gitignore"), 0o750); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(cache, "index.json"), 0o750); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := NewIndex().Save(filepath.Join(cache, "index.json")); err == nil {
+		t.Fatalf("expected write error when the index path is a directory")
+	}
+}
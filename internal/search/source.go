@@ -0,0 +1,211 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/fields"
+	"github.com/virtualboard/vb-cli/internal/spec"
+)
+
+// CachePath returns where the index of the workspace rooted at root is persisted.
+func CachePath(root string) string {
+	return filepath.Join(root, ".cache", "search-index.json")
+}
+
+// Stats summarises what an Update changed.
+type Stats struct {
+	Documents int      `json:"documents"`
+	Indexed   int      `json:"indexed"`
+	Removed   int      `json:"removed"`
+	Skipped   []string `json:"skipped,omitempty"`
+}
+
+// Changed reports whether the update modified the index.
+func (s Stats) Changed() bool {
+	return s.Indexed > 0 || s.Removed > 0
+}
+
+// Update brings ix in line with the feature and spec files under root. Only files
+// whose size or modification time differ from the indexed copy are read again;
+// files that fail to parse are dropped from the index and listed in Stats.Skipped.
+func Update(ix *Index, root string) (Stats, error) {
+	var stats Stats
+	present := map[string]bool{}
+
+	visit := func(kind, path string, info fs.FileInfo) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		present[rel] = true
+		if doc, ok := ix.Docs[rel]; ok && doc.ModTime == info.ModTime().UnixNano() && doc.Size == info.Size() {
+			return nil
+		}
+		// #nosec G304 -- paths come from walking the workspace directories
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := buildDocument(kind, path, data)
+		if err != nil {
+			ix.Remove(rel)
+			stats.Skipped = append(stats.Skipped, rel)
+			return nil
+		}
+		doc.Path = rel
+		doc.ModTime = info.ModTime().UnixNano()
+		doc.Size = info.Size()
+		ix.Put(doc)
+		stats.Indexed++
+		return nil
+	}
+
+	if err := walkMarkdown(filepath.Join(root, "features"), true, func(path string, info fs.FileInfo) error {
+		return visit(KindFeature, path, info)
+	}); err != nil {
+		return stats, fmt.Errorf("failed to scan features: %w", err)
+	}
+	if err := walkMarkdown(filepath.Join(root, "specs"), false, func(path string, info fs.FileInfo) error {
+		return visit(KindSpec, path, info)
+	}); err != nil {
+		return stats, fmt.Errorf("failed to scan specs: %w", err)
+	}
+
+	for path := range ix.Docs {
+		if !present[path] {
+			ix.Remove(path)
+			stats.Removed++
+		}
+	}
+	stats.Documents = len(ix.Docs)
+	return stats, nil
+}
+
+// walkMarkdown calls fn for every .md file under dir except index and readme
+// files, mirroring how the feature and spec managers discover documents.
+func walkMarkdown(dir string, recursive bool, fn func(string, fs.FileInfo) error) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if filepath.Ext(name) != ".md" || strings.EqualFold(name, "index.md") || strings.EqualFold(name, "readme.md") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, info)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func buildDocument(kind, path string, data []byte) (*Document, error) {
+	if kind == KindSpec {
+		s, err := spec.Parse(path, data)
+		if err != nil {
+			return nil, err
+		}
+		fm := s.FrontMatter
+		title := fm.Title
+		if title == "" {
+			title = filepath.Base(path)
+		}
+		doc := &Document{Kind: KindSpec, ID: filepath.Base(path), Title: title, Status: fm.Status}
+		doc.Fields = append([]Field{
+			{Name: FieldTitle, Text: title},
+			{Name: FieldFrontmatter, Text: frontmatterText(map[string]string{
+				"spec_type":           fm.SpecType,
+				"status":              fm.Status,
+				"owner":               fm.Owner,
+				"applicability":       strings.Join(fm.Applicability, ", "),
+				"related_initiatives": strings.Join(fm.RelatedInitiatives, ", "),
+			})},
+		}, splitSections(s.Body)...)
+		return doc, nil
+	}
+
+	feat, err := feature.Parse(path, data)
+	if err != nil {
+		return nil, err
+	}
+	fm := feat.FrontMatter
+	values := map[string]string{
+		"id":           fm.ID,
+		"status":       fm.Status,
+		"owner":        fm.Owner,
+		"priority":     fm.Priority,
+		"complexity":   fm.Complexity,
+		"labels":       strings.Join(fm.Labels, ", "),
+		"dependencies": strings.Join(fm.Dependencies, ", "),
+		"epic":         fm.Epic,
+		"risk_notes":   fm.RiskNotes,
+	}
+	for key, value := range fm.Custom {
+		values[key] = fields.Format(value)
+	}
+	doc := &Document{Kind: KindFeature, ID: fm.ID, Title: fm.Title, Status: fm.Status}
+	doc.Fields = append([]Field{
+		{Name: FieldTitle, Text: fm.Title},
+		{Name: FieldFrontmatter, Text: frontmatterText(values)},
+	}, splitSections(feat.Body)...)
+	return doc, nil
+}
+
+// frontmatterText renders non-empty values as sorted "key: value" lines.
+func frontmatterText(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if strings.TrimSpace(value) != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + ": " + values[key]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitSections breaks a markdown body into its H2 sections. Text before the first
+// heading becomes the intro field; empty sections are omitted.
+func splitSections(body string) []Field {
+	var out []Field
+	name := FieldIntro
+	var buf []string
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(buf, "\n")); text != "" {
+			out = append(out, Field{Name: name, Text: text})
+		}
+		buf = buf[:0]
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			name = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		}
+		buf = append(buf, line)
+	}
+	flush()
+	return out
+}
@@ -0,0 +1,152 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+const searchFeature = `---
+id: FTR-0001
+title: Rate limits
+status: backlog
+owner: alice
+priority: high
+complexity: M
+labels:
+  - api
+dependencies: []
+created: 2026-01-01
+updated: 2026-01-01
+team: platform
+---
+
+Intro paragraph.
+
+## Summary
+Throttle noisy clients with rate limiting.
+
+## Notes
+`
+
+const searchSpec = `---
+spec_type: architecture
+title: API Gateway
+status: approved
+last_updated: 2026-01-01
+applicability:
+  - backend
+---
+
+## Throttling
+Token buckets enforce rate limiting.
+`
+
+func TestUpdateIncremental(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "features/backlog/FTR-0001-rate-limits.md", []byte(searchFeature))
+	fix.WriteFile(t, "features/INDEX.md", []byte("# Index\nrate limiting\n"))
+	fix.WriteFile(t, "specs/api.md", []byte(searchSpec))
+	fix.WriteFile(t, "specs/archive/old.md", []byte(searchSpec))
+	fix.WriteFile(t, "specs/broken.md", []byte("no frontmatter"))
+	fix.WriteFile(t, "specs/notes.txt", []byte("rate limiting"))
+
+	ix := NewIndex()
+	stats, err := Update(ix, fix.Path())
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if stats.Documents != 2 || stats.Indexed != 2 || !reflect.DeepEqual(stats.Skipped, []string{"specs/broken.md"}) || !stats.Changed() {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	feat := ix.Docs["features/backlog/FTR-0001-rate-limits.md"]
+	if feat == nil || feat.ID != "FTR-0001" || feat.Status != "backlog" || feat.Kind != KindFeature {
+		t.Fatalf("unexpected feature document %+v", feat)
+	}
+	names := []string{}
+	for _, f := range feat.Fields {
+		names = append(names, f.Name)
+	}
+	if !reflect.DeepEqual(names, []string{FieldTitle, FieldFrontmatter, FieldIntro, "Summary"}) {
+		t.Fatalf("unexpected fields %v", names)
+	}
+	if results := ix.Search("platform", Options{}); len(results) != 1 || results[0].Section != FieldFrontmatter {
+		t.Fatalf("expected custom field to be searchable, got %+v", results)
+	}
+	if spec := ix.Docs["specs/api.md"]; spec == nil || spec.ID != "api.md" || spec.Title != "API Gateway" {
+		t.Fatalf("unexpected spec document %+v", spec)
+	}
+
+	stats, err = Update(ix, fix.Path())
+	if err != nil || stats.Changed() {
+		t.Fatalf("expected unchanged files to be skipped: %v %+v", err, stats)
+	}
+
+	path := fix.Path("features", "backlog", "FTR-0001-rate-limits.md")
+	if err := os.WriteFile(path, []byte(searchFeature+"Burst handling.\n"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+	if err := os.Remove(fix.Path("specs", "api.md")); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	stats, err = Update(ix, fix.Path())
+	if err != nil || stats.Indexed != 1 || stats.Removed != 1 || stats.Documents != 1 {
+		t.Fatalf("unexpected incremental stats: %v %+v", err, stats)
+	}
+	if results := ix.Search("burst", Options{}); len(results) != 1 || results[0].Section != "Notes" {
+		t.Fatalf("expected changed file to be reindexed, got %+v", results)
+	}
+}
+
+func TestUpdateDropsFilesThatStopParsing(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "specs/api.md", []byte(searchSpec))
+	ix := NewIndex()
+	if _, err := Update(ix, fix.Path()); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	fix.WriteFile(t, "specs/api.md", []byte("---\ntitle: [\n---\n"))
+	stats, err := Update(ix, fix.Path())
+	if err != nil || len(stats.Skipped) != 1 || len(ix.Docs) != 0 || len(ix.Terms) != 0 {
+		t.Fatalf("expected unparsable file to be dropped: %v %+v", err, stats)
+	}
+}
+
+func TestUpdateMissingDirectories(t *testing.T) {
+	root := t.TempDir()
+	stats, err := Update(NewIndex(), root)
+	if err != nil || stats.Documents != 0 {
+		t.Fatalf("expected empty workspace to index nothing: %v %+v", err, stats)
+	}
+}
+
+func TestUpdateReadError(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "features/backlog/FTR-0001-a.md", []byte(searchFeature))
+	path := fix.Path("features", "backlog", "FTR-0001-a.md")
+	if err := os.Chmod(path, 0); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(path, 0o600) })
+	if _, err := os.ReadFile(path); err == nil {
+		t.Skip("file permissions are not enforced for this user")
+	}
+	if _, err := Update(NewIndex(), fix.Path()); err == nil {
+		t.Fatalf("expected read error")
+	}
+}
+
+func TestCachePath(t *testing.T) {
+	if got := CachePath("/ws/.virtualboard"); got != filepath.Join("/ws/.virtualboard", ".cache", "search-index.json") {
+		t.Fatalf("unexpected cache path %s", got)
+	}
+}
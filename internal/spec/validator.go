@@ -3,6 +3,7 @@ package spec
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -11,12 +12,23 @@ import (
 	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/rules"
 )
 
 // Result represents validation outcome for a single spec.
 type Result struct {
-	Spec   *Spec
+	Spec *Spec
+	// Errors holds the messages of error-severity findings.
 	Errors []string
+	// Findings holds every reported finding, including warnings and info.
+	Findings []rules.Finding
+}
+
+func (r *Result) add(f rules.Finding) {
+	r.Findings = append(r.Findings, f)
+	if f.Severity == rules.Error {
+		r.Errors = append(r.Errors, f.Message)
+	}
 }
 
 // Summary aggregates validation results.
@@ -24,6 +36,7 @@ type Summary struct {
 	Total      int               `json:"total"`
 	Valid      int               `json:"valid"`
 	Invalid    int               `json:"invalid"`
+	Warnings   int               `json:"warnings"`
 	ErrorCount map[string]int    `json:"error_counts"`
 	Results    map[string]Result `json:"results"`
 }
@@ -33,26 +46,83 @@ type Validator struct {
 	mgr          *Manager
 	schemaLoader gojsonschema.JSONLoader
 	log          *logrus.Entry
+	rules        *rules.Set
+}
+
+// specRule is a rule checked against one spec at a time.
+type specRule struct {
+	rules.Rule
+	check func(v *Validator, spec *Spec) []rules.Problem
+}
+
+var specRules = []specRule{
+	{rules.Rule{ID: "spec-schema", Description: "Spec frontmatter matches the JSON schema", Severity: rules.Error}, (*Validator).checkSchema},
+	{rules.Rule{ID: "spec-last-updated", Description: "last_updated is YYYY-MM-DD", Severity: rules.Error}, (*Validator).checkLastUpdated},
+	{rules.Rule{ID: "spec-status", Description: "The status is draft, approved or deprecated", Severity: rules.Error}, (*Validator).checkStatus},
+	{rules.Rule{ID: "spec-type", Description: "spec_type is a recognized type", Severity: rules.Error}, (*Validator).checkSpecType},
+	{rules.Rule{ID: "spec-applicability", Description: "applicability has at least one entry", Severity: rules.Error}, (*Validator).checkApplicability},
+}
+
+// DefaultRules returns every spec rule with its default severity.
+func DefaultRules() []rules.Rule {
+	defs := make([]rules.Rule, 0, len(specRules))
+	for _, r := range specRules {
+		defs = append(defs, r.Rule)
+	}
+	return defs
 }
 
 // New creates a validator configured for the manager.
 func NewValidator(opts *config.Options, mgr *Manager) (*Validator, error) {
 	schemaPath := mgr.SchemaPath()
 	loader := gojsonschema.NewReferenceLoader("file://" + filepath.ToSlash(schemaPath))
+	set := rules.NewSet(DefaultRules())
+	if err := set.Configure(opts.Settings.Validation.Rules); err != nil {
+		return nil, fmt.Errorf("invalid config: validation.rules: %w", err)
+	}
 	return &Validator{
 		mgr:          mgr,
 		schemaLoader: loader,
 		log:          opts.Logger().WithField("component", "spec-validator"),
+		rules:        set,
 	}, nil
 }
 
+// Rules returns the validator's rule set, e.g. to narrow it with Select before a run.
+func (v *Validator) Rules() *rules.Set {
+	return v.rules
+}
+
 // ValidateAll runs validations across every spec.
 func (v *Validator) ValidateAll() (*Summary, error) {
 	specs, err := v.mgr.List()
 	if err != nil {
 		return nil, err
 	}
+	return v.validate(specs), nil
+}
+
+// ValidatePaths runs validations on the specs stored at the given paths.
+// Paths that are not spec files are ignored.
+func (v *Validator) ValidatePaths(paths []string) (*Summary, error) {
+	specs, err := v.mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	for _, path := range paths {
+		wanted[filepath.Clean(path)] = true
+	}
+	selected := []*Spec{}
+	for _, spec := range specs {
+		if wanted[filepath.Clean(spec.Path)] {
+			selected = append(selected, spec)
+		}
+	}
+	return v.validate(selected), nil
+}
 
+func (v *Validator) validate(specs []*Spec) *Summary {
 	results := make(map[string]Result)
 	errorCounts := map[string]int{}
 
@@ -65,6 +135,7 @@ func (v *Validator) ValidateAll() (*Summary, error) {
 	total := len(results)
 	valid := 0
 	invalid := 0
+	warnings := 0
 	for name, res := range results {
 		if len(res.Errors) == 0 {
 			valid++
@@ -72,6 +143,11 @@ func (v *Validator) ValidateAll() (*Summary, error) {
 			invalid++
 			errorCounts[name] = len(res.Errors)
 		}
+		for _, f := range res.Findings {
+			if f.Severity == rules.Warning {
+				warnings++
+			}
+		}
 	}
 
 	if invalid > 0 {
@@ -84,9 +160,10 @@ func (v *Validator) ValidateAll() (*Summary, error) {
 		Total:      total,
 		Valid:      valid,
 		Invalid:    invalid,
+		Warnings:   warnings,
 		ErrorCount: errorCounts,
 		Results:    results,
-	}, nil
+	}
 }
 
 // ValidateName runs validation on a specific spec by filename.
@@ -100,36 +177,73 @@ func (v *Validator) ValidateName(name string) (Result, error) {
 }
 
 func (v *Validator) validateSingle(spec *Spec) Result {
-	errors := make([]string, 0)
+	res := Result{Spec: spec, Errors: []string{}, Findings: []rules.Finding{}}
+	// Specs have a closed schema, so rules are suppressed with comments only.
+	suppressed := rules.Suppressed(nil, spec.Body)
+	var locator *rules.Locator
+	for _, r := range specRules {
+		if !v.rules.Enabled(r.ID) {
+			continue
+		}
+		for _, p := range r.check(v, spec) {
+			f, ok := v.rules.Finding(r.ID, p.Message, suppressed)
+			if !ok {
+				continue
+			}
+			if locator == nil {
+				locator = locate(spec)
+			}
+			f.Line = locator.Line(p)
+			res.add(f)
+		}
+	}
+	return res
+}
 
-	// JSON schema validation
+// locate indexes the spec file for line numbers, falling back to the encoded
+// spec when the file cannot be read.
+func locate(spec *Spec) *rules.Locator {
+	// #nosec G304 -- spec paths come from workspace discovery
+	data, err := os.ReadFile(spec.Path)
+	if err != nil {
+		data, _ = spec.Encode()
+	}
+	return rules.NewLocator(data)
+}
+
+func (v *Validator) checkSchema(spec *Spec) []rules.Problem {
 	docLoader := gojsonschema.NewGoLoader(spec.FrontMatter)
 	result, err := gojsonschema.Validate(v.schemaLoader, docLoader)
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("schema validation error: %v", err))
-	} else if !result.Valid() {
-		for _, desc := range result.Errors() {
-			errors = append(errors, desc.String())
-		}
+		return []rules.Problem{{Message: fmt.Sprintf("schema validation error: %v", err)}}
+	}
+	var problems []rules.Problem
+	for _, desc := range result.Errors() {
+		problems = append(problems, rules.Problem{Message: desc.String(), Key: rules.SchemaKey(desc)})
 	}
+	return problems
+}
 
-	// Date format validation
+func (v *Validator) checkLastUpdated(spec *Spec) []rules.Problem {
 	if _, err := time.Parse("2006-01-02", spec.FrontMatter.LastUpdated); err != nil {
-		errors = append(errors, "last_updated must be YYYY-MM-DD")
+		return []rules.Problem{{Message: "last_updated must be YYYY-MM-DD", Key: "last_updated"}}
 	}
+	return nil
+}
 
-	// Validate status is one of the allowed values
+func (v *Validator) checkStatus(spec *Spec) []rules.Problem {
 	validStatuses := map[string]bool{
 		"draft":      true,
 		"approved":   true,
 		"deprecated": true,
 	}
-	status := strings.ToLower(spec.FrontMatter.Status)
-	if !validStatuses[status] {
-		errors = append(errors, fmt.Sprintf("status '%s' must be one of: draft, approved, deprecated", spec.FrontMatter.Status))
+	if !validStatuses[strings.ToLower(spec.FrontMatter.Status)] {
+		return []rules.Problem{{Message: fmt.Sprintf("status '%s' must be one of: draft, approved, deprecated", spec.FrontMatter.Status), Key: "status"}}
 	}
+	return nil
+}
 
-	// Validate spec_type is one of the known types
+func (v *Validator) checkSpecType(spec *Spec) []rules.Problem {
 	validTypes := map[string]bool{
 		"tech-stack":                          true,
 		"local-development":                   true,
@@ -141,15 +255,16 @@ func (v *Validator) validateSingle(spec *Spec) Result {
 		"observability-and-incident-response": true,
 	}
 	if !validTypes[spec.FrontMatter.SpecType] {
-		errors = append(errors, fmt.Sprintf("spec_type '%s' is not a recognized type", spec.FrontMatter.SpecType))
+		return []rules.Problem{{Message: fmt.Sprintf("spec_type '%s' is not a recognized type", spec.FrontMatter.SpecType), Key: "spec_type"}}
 	}
+	return nil
+}
 
-	// Validate applicability has at least one entry
+func (v *Validator) checkApplicability(spec *Spec) []rules.Problem {
 	if len(spec.FrontMatter.Applicability) == 0 {
-		errors = append(errors, "applicability must have at least one entry")
+		return []rules.Problem{{Message: "applicability must have at least one entry", Key: "applicability"}}
 	}
-
-	return Result{Spec: spec, Errors: errors}
+	return nil
 }
 
 // HasErrors indicates if any validation errors were found.
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/rules"
 )
 
 func setupValidatorTest(t *testing.T) (*Manager, *Validator, string) {
@@ -474,3 +475,108 @@ func TestValidateSingleAllErrors(t *testing.T) {
 		t.Errorf("expected at least 3 errors, got %d: %v", len(result.Errors), result.Errors)
 	}
 }
+
+func TestValidateSingleRules(t *testing.T) {
+	_, validator, _ := setupValidatorTest(t)
+
+	spec := &Spec{
+		Path: "test.md",
+		FrontMatter: FrontMatter{
+			SpecType:      "unknown-type",
+			Title:         "Technology Stack",
+			Status:        "approved",
+			LastUpdated:   "2024-01-15",
+			Applicability: []string{"backend"},
+		},
+		Body: "<!-- vb-disable spec-schema -->\n",
+	}
+
+	result := validator.validateSingle(spec)
+	if len(result.Findings) != 1 || result.Findings[0].Rule != "spec-type" || len(result.Errors) != 1 {
+		t.Fatalf("expected only the spec-type finding, got %+v", result.Findings)
+	}
+
+	validator.Rules().Select(nil, []string{"spec-type"})
+	if result := validator.validateSingle(spec); len(result.Findings) != 0 {
+		t.Fatalf("expected disabled rule to be skipped, got %+v", result.Findings)
+	}
+	if len(DefaultRules()) != len(specRules) {
+		t.Fatalf("expected every spec rule to be registered")
+	}
+}
+
+func TestValidatorRuleOverrides(t *testing.T) {
+	mgr, _, vbDir := setupValidatorTest(t)
+	opts := config.New()
+	if err := opts.Init(filepath.Dir(vbDir), false, false, false, ""); err != nil {
+		t.Fatalf("failed to init options: %v", err)
+	}
+	opts.Settings.Validation.Rules = rules.Overrides{"spec-type": {Severity: rules.Warning}}
+	validator, err := NewValidator(opts, mgr)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	content := "---\nspec_type: tech-stack\ntitle: Technology Stack\nstatus: approved\nlast_updated: 2024-01-15\napplicability:\n  - backend\n---\n"
+	if err := os.WriteFile(filepath.Join(vbDir, "specs", "tech-stack.md"), []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	spec, err := mgr.LoadByName("tech-stack.md")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	spec.FrontMatter.SpecType = "custom"
+	if result := validator.validateSingle(spec); len(result.Errors) != 1 || len(result.Findings) != 2 || result.Findings[1].Severity != rules.Warning {
+		t.Fatalf("expected a schema error and a spec-type warning, got %+v", result.Findings)
+	}
+
+	opts.Settings.Validation.Rules = rules.Overrides{"spec-type": {Options: map[string]interface{}{"strict": true}}}
+	if _, err := NewValidator(opts, mgr); err == nil || !strings.Contains(err.Error(), "has no option strict") {
+		t.Fatalf("expected option error, got %v", err)
+	}
+}
+
+func TestValidatePaths(t *testing.T) {
+	_, validator, vbDir := setupValidatorTest(t)
+	specsDir := filepath.Join(vbDir, "specs")
+
+	spec := `---
+spec_type: tech-stack
+title: Technology Stack
+status: %s
+last_updated: 2024-01-15
+applicability:
+  - backend
+---
+
+## Stack
+Details.`
+	for name, status := range map[string]string{"changed.md": "invalid-status", "untouched.md": "invalid-status", "other.md": "approved"} {
+		if err := os.WriteFile(filepath.Join(specsDir, name), []byte(strings.Replace(spec, "%s", status, 1)), 0o600); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+	}
+
+	summary, err := validator.ValidatePaths([]string{
+		filepath.Join(specsDir, "changed.md"),
+		filepath.Join(specsDir, "sub", "..", "other.md"),
+		filepath.Join(vbDir, "features", "backlog", "FTR-0001-x.md"),
+	})
+	if err != nil {
+		t.Fatalf("validate paths failed: %v", err)
+	}
+	if summary.Total != 2 || summary.Invalid != 1 || summary.ErrorCount["changed.md"] != 2 {
+		t.Fatalf("expected only the changed specs, got %+v", summary)
+	}
+
+	if summary, _ = validator.ValidatePaths(nil); summary.Total != 0 {
+		t.Fatalf("expected nothing to validate, got %+v", summary)
+	}
+
+	if err := os.WriteFile(filepath.Join(specsDir, "broken.md"), []byte("no frontmatter"), 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	if _, err := validator.ValidatePaths(nil); err == nil {
+		t.Fatal("expected list error")
+	}
+}
@@ -19,7 +19,7 @@ func TestProcessorApply(t *testing.T) {
 	}
 
 	feat := feature.Feature{
-		Path: filepath.Join(opts.RootDir, feature.DirectoryForStatus("backlog"), "FTR-2000-needs-sections.md"),
+		Path: filepath.Join(opts.RootDir, mgr.Workflow().DirectoryForStatus("backlog"), "FTR-2000-needs-sections.md"),
 		FrontMatter: feature.FrontMatter{
 			ID:         "FTR-2000",
 			Title:      "Needs Sections",
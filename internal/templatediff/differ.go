@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/virtualboard/vb-cli/internal/workflow"
 )
 
 // CompareDirectories compares two template directories and returns the differences
@@ -20,6 +23,12 @@ func CompareDirectories(localDir, remoteDir string) (*TemplateDiff, error) {
 		Unchanged: []FileDiff{},
 	}
 
+	// Feature files live in the status directories of the local workflow
+	wf, err := workflow.Load(localDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build a map of all files in both directories
 	localFiles, err := collectFiles(localDir)
 	if err != nil {
@@ -48,7 +57,7 @@ func CompareDirectories(localDir, remoteDir string) (*TemplateDiff, error) {
 		localPath := filepath.Join(localDir, relPath)
 
 		// Skip feature files and other excluded files
-		if isFeatureFile(relPath) || shouldSkipFile(relPath) {
+		if isFeatureFile(wf, relPath) || shouldSkipFile(relPath) {
 			continue
 		}
 
@@ -80,7 +89,7 @@ func CompareDirectories(localDir, remoteDir string) (*TemplateDiff, error) {
 
 	// Check for removed files
 	for _, relPath := range localFiles {
-		if !remoteMap[relPath] && !isFeatureFile(relPath) && !shouldSkipFile(relPath) {
+		if !remoteMap[relPath] && !isFeatureFile(wf, relPath) && !shouldSkipFile(relPath) {
 			localPath := filepath.Join(localDir, relPath)
 			content, err := os.ReadFile(localPath) // #nosec G304 -- path is from validated local directory
 			if err != nil {
@@ -211,16 +220,15 @@ func collectFiles(root string) ([]string, error) {
 }
 
 // isFeatureFile returns true if the path is a feature specification file
-func isFeatureFile(relPath string) bool {
-	// Feature files are under features/{status}/*.md (but not INDEX.md)
-	parts := strings.Split(filepath.ToSlash(relPath), "/")
-	if len(parts) >= 3 && parts[0] == "features" {
-		// Check if it's in a status directory (backlog, in-progress, etc.)
-		statusDirs := []string{"backlog", "in-progress", "blocked", "review", "done"}
-		for _, status := range statusDirs {
-			if parts[1] == status && strings.HasSuffix(parts[len(parts)-1], ".md") {
-				return true
-			}
+func isFeatureFile(wf *workflow.Workflow, relPath string) bool {
+	// Feature files are markdown files under a workflow status directory (but not features/INDEX.md)
+	slashed := filepath.ToSlash(relPath)
+	if !strings.HasSuffix(slashed, ".md") {
+		return false
+	}
+	for dir := path.Dir(slashed); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if _, ok := wf.StatusForDirectory(dir); ok {
+			return true
 		}
 	}
 	return false
@@ -236,6 +244,10 @@ func shouldSkipFile(relPath string) bool {
 	if relPath == "audit.jsonl" {
 		return true
 	}
+	// Skip the trash of pre-change feature snapshots kept for vb undo and vb revert
+	if strings.HasPrefix(filepath.ToSlash(relPath), ".trash/") {
+		return true
+	}
 	return false
 }
 
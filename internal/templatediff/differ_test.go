@@ -3,7 +3,10 @@ package templatediff
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/workflow"
 )
 
 func TestCompareDirectories(t *testing.T) {
@@ -72,12 +75,12 @@ func TestCompareDirectories(t *testing.T) {
 
 	// Verify feature files were ignored
 	for _, fd := range diff.Added {
-		if isFeatureFile(fd.Path) {
+		if isFeatureFile(workflow.Default(), fd.Path) {
 			t.Errorf("Feature file %s should have been ignored", fd.Path)
 		}
 	}
 	for _, fd := range diff.Removed {
-		if isFeatureFile(fd.Path) {
+		if isFeatureFile(workflow.Default(), fd.Path) {
 			t.Errorf("Feature file %s should have been ignored", fd.Path)
 		}
 	}
@@ -285,13 +288,55 @@ func TestIsFeatureFile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := isFeatureFile(tt.path); got != tt.want {
+			if got := isFeatureFile(workflow.Default(), tt.path); got != tt.want {
 				t.Errorf("isFeatureFile(%q) = %v, want %v", tt.path, got, tt.want)
 			}
 		})
 	}
 }
 
+func TestCompareDirectoriesCustomWorkflow(t *testing.T) {
+	localDir := t.TempDir()
+	remoteDir := t.TempDir()
+
+	writeFile(t, filepath.Join(localDir, "workflow.yaml"), `statuses:
+  - name: todo
+    transitions: [qa]
+  - name: qa
+    directory: features/quality
+    transitions: [todo]
+`)
+	writeFile(t, filepath.Join(remoteDir, "workflow.yaml"), "")
+	for _, dir := range []string{"quality", "backlog"} {
+		if err := os.MkdirAll(filepath.Join(localDir, "features", dir), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(localDir, "features", "quality", "FTR-0001-x.md"), "local feature\n")
+	writeFile(t, filepath.Join(localDir, "features", "backlog", "notes.md"), "not a status dir\n")
+
+	diff, err := CompareDirectories(localDir, remoteDir)
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	removed := []string{}
+	for _, fd := range diff.Removed {
+		removed = append(removed, filepath.ToSlash(fd.Path))
+	}
+	joined := strings.Join(removed, ",")
+	if strings.Contains(joined, "features/quality") {
+		t.Fatalf("feature in custom status directory should be ignored: %v", removed)
+	}
+	if !strings.Contains(joined, "features/backlog/notes.md") {
+		t.Fatalf("backlog is not a status in the custom workflow and should be compared: %v", removed)
+	}
+
+	writeFile(t, filepath.Join(localDir, "workflow.yaml"), "statuses: [")
+	if _, err := CompareDirectories(localDir, remoteDir); err == nil {
+		t.Fatalf("expected error for invalid workflow")
+	}
+}
+
 func TestShouldSkipFile(t *testing.T) {
 	tests := []struct {
 		name string
@@ -333,6 +378,11 @@ func TestShouldSkipFile(t *testing.T) {
 			path: "features/audit.jsonl",
 			want: false,
 		},
+		{
+			name: "trash snapshots should be skipped",
+			path: ".trash/0123abcd.md",
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -17,6 +17,8 @@ type Fixture struct {
 func NewFixture(t *testing.T) *Fixture {
 	t.Helper()
 	root := t.TempDir()
+	// Keep the developer's own ~/.config/vb/config.yaml out of tests.
+	t.Setenv(config.UserConfigEnv, filepath.Join(root, "user-config.yaml"))
 
 	workspace := filepath.Join(root, ".virtualboard")
 	dirs := []string{
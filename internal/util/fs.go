@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // atomicFile encapsulates the subset of *os.File behaviour needed by WriteFileAtomic.
@@ -92,3 +93,13 @@ func writeFileAtomic(fs atomicFS, path string, data []byte, perm fs.FileMode) er
 
 	return nil
 }
+
+// RelativePath returns path relative to root with forward slashes, or path itself
+// when it lies outside root.
+func RelativePath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
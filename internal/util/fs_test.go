@@ -147,3 +147,17 @@ func TestWriteFileAtomicFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestRelativePath(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "work")
+	if got := RelativePath(root, filepath.Join(root, "x", "y.md")); got != "x/y.md" {
+		t.Fatalf("unexpected relative path %s", got)
+	}
+	if got := RelativePath(root, filepath.Join(root, "..work2", "y.md")); got != "..work2/y.md" {
+		t.Fatalf("expected a dot-dot prefixed name to stay inside root, got %s", got)
+	}
+	outside := filepath.Join(string(filepath.Separator), "elsewhere", "y.md")
+	if got := RelativePath(root, outside); got != filepath.ToSlash(outside) {
+		t.Fatalf("expected the path itself outside root, got %s", got)
+	}
+}
@@ -147,9 +147,26 @@ func DisplayPromptHelp() {
 	fmt.Fprintln(os.Stderr, "")
 }
 
+// preferredEditor is the configured editor command, consulted before $EDITOR/$VISUAL.
+var preferredEditor string
+
+// SetEditor sets the editor command used by OpenInEditor; it may include arguments.
+func SetEditor(editor string) {
+	preferredEditor = strings.TrimSpace(editor)
+}
+
 // OpenInEditor opens a file in the user's preferred editor
 func OpenInEditor(filePath string) error {
-	editor := os.Getenv("EDITOR")
+	var args []string
+	editor := ""
+	if preferredEditor != "" {
+		// Configured editors may carry arguments, e.g. "code --wait".
+		parts := strings.Fields(preferredEditor)
+		editor, args = parts[0], parts[1:]
+	}
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
 	if editor == "" {
 		editor = os.Getenv("VISUAL")
 	}
@@ -163,11 +180,11 @@ func OpenInEditor(filePath string) error {
 		}
 	}
 	if editor == "" {
-		return fmt.Errorf("no editor found. Set $EDITOR or $VISUAL environment variable")
+		return fmt.Errorf("no editor found. Set editor in config.yaml, $EDITOR or $VISUAL")
 	}
 
-	// #nosec G204 G702 - editor command comes from user's environment variable ($EDITOR/$VISUAL) which is intentional
-	cmd := exec.Command(editor, filePath)
+	// #nosec G204 G702 - editor command comes from the user's configuration or environment, which is intentional
+	cmd := exec.Command(editor, append(args, filePath)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
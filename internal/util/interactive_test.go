@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -414,3 +415,17 @@ func TestCountLines(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenInEditorUsesConfiguredEditor(t *testing.T) {
+	t.Cleanup(func() { SetEditor("") })
+
+	SetEditor("true --wait")
+	if err := OpenInEditor(filepath.Join(t.TempDir(), "file.md")); err != nil {
+		t.Fatalf("configured editor failed: %v", err)
+	}
+
+	SetEditor("false")
+	if err := OpenInEditor("file.md"); err == nil {
+		t.Fatalf("expected error from failing editor")
+	}
+}
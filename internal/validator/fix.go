@@ -0,0 +1,319 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/util"
+)
+
+// Fix is a single change made by ApplyFixes.
+type Fix struct {
+	// ID is the feature's ID after fixing.
+	ID string `json:"id"`
+	// Kind names what was fixed: id, dates, labels, dependencies, owner,
+	// template, directory or filename.
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// String renders the fix as "ID [kind] message".
+func (f Fix) String() string {
+	return fmt.Sprintf("%s [%s] %s", f.ID, f.Kind, f.Message)
+}
+
+// FixReport lists the fixes ApplyFixes made.
+type FixReport struct {
+	Fixes []Fix `json:"fixes"`
+}
+
+// addFix records a fix for the feature being fixed.
+type addFix func(kind, format string, args ...interface{})
+
+// DefaultOwner replaces an empty owner.
+const DefaultOwner = "unassigned"
+
+var (
+	idFormat          = regexp.MustCompile(`^FTR-\d{4}$`)
+	filenameIDPattern = regexp.MustCompile(`(?i)^(ftr-\d{4})(?:-|\.md$)`)
+)
+
+// dateLayouts are the date formats normalized to YYYY-MM-DD. Day-first and
+// month-first numeric dates are ambiguous and left for validation to report.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-1-2",
+	"2006/01/02",
+	"2006/1/2",
+	"2006.01.02",
+	"20060102",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// ApplyFixes applies non-destructive fixes: it normalizes IDs, dates, labels,
+// dependencies and empty owners, runs the template processor, and moves each file
+// to the directory for its status under the name for its ID and title. Features
+// are only rewritten when a fix changed them. All files are written in a single
+// transaction: if any write fails, none of the fixes stick. In dry-run mode nothing
+// is written and the manager's patches preview the changes.
+func (v *Validator) ApplyFixes(features []*feature.Feature, processor func(*feature.Feature) error) (*FixReport, error) {
+	all, err := v.mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	owners := map[string][]string{}
+	for _, feat := range all {
+		id := normalizeID(feat.FrontMatter.ID)
+		owners[id] = append(owners[id], filepath.Clean(feat.Path))
+	}
+
+	sorted := append([]*feature.Feature{}, features...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	report := &FixReport{Fixes: []Fix{}}
+	tx := v.mgr.Begin()
+	for _, feat := range sorted {
+		before, err := feat.Encode()
+		if err != nil {
+			return nil, err
+		}
+		var fixes []Fix
+		add := func(kind, format string, args ...interface{}) {
+			fixes = append(fixes, Fix{Kind: kind, Message: fmt.Sprintf(format, args...)})
+		}
+
+		fixID(feat, owners, add)
+		fixDates(feat, add)
+		if cleaned := cleanList(feat.FrontMatter.Labels, false); !equalLists(cleaned, feat.FrontMatter.Labels) {
+			add("labels", "cleaned labels %s to %s", formatList(feat.FrontMatter.Labels), formatList(cleaned))
+			feat.FrontMatter.Labels = cleaned
+		}
+		if cleaned := cleanList(feat.FrontMatter.Dependencies, true); !equalLists(cleaned, feat.FrontMatter.Dependencies) {
+			add("dependencies", "cleaned dependencies %s to %s", formatList(feat.FrontMatter.Dependencies), formatList(cleaned))
+			feat.FrontMatter.Dependencies = cleaned
+		}
+		if strings.TrimSpace(feat.FrontMatter.Owner) == "" {
+			add("owner", "set empty owner to %s", DefaultOwner)
+			feat.FrontMatter.Owner = DefaultOwner
+		}
+		if err := v.fixTemplate(feat, processor, add); err != nil {
+			return nil, err
+		}
+
+		target := v.fixPath(feat, add)
+		after, err := feat.Encode()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case target != feat.Path:
+			if err := tx.Move(feat, target); err != nil {
+				return nil, err
+			}
+		case string(after) != string(before):
+			if err := tx.Save(feat); err != nil {
+				return nil, err
+			}
+		}
+		for _, fix := range fixes {
+			fix.ID = feat.FrontMatter.ID
+			report.Fixes = append(report.Fixes, fix)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// fixID uppercases the ID and reconciles it with the ID in the filename. The
+// frontmatter ID wins, and the file is renamed to match, unless it is empty,
+// malformed, or used by another feature while the filename's ID is free.
+func fixID(feat *feature.Feature, owners map[string][]string, add addFix) {
+	original := feat.FrontMatter.ID
+	id := normalizeID(original)
+	feat.FrontMatter.ID = id
+	keep := func() {
+		if id != original && id != "" {
+			add("id", "normalized id %q to %s", original, id)
+		}
+	}
+
+	match := filenameIDPattern.FindStringSubmatch(filepath.Base(feat.Path))
+	if match == nil || normalizeID(match[1]) == id {
+		keep()
+		return
+	}
+	fileID := normalizeID(match[1])
+	path := filepath.Clean(feat.Path)
+	usedElsewhere := func(candidate string) bool {
+		for _, owner := range owners[candidate] {
+			if owner != path {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case !idFormat.MatchString(id):
+		add("id", "set id %q to %s from the filename", original, fileID)
+	case usedElsewhere(id) && !usedElsewhere(fileID):
+		add("id", "set id to %s from the filename; %s is used by another feature", fileID, id)
+	default:
+		keep()
+		return
+	}
+	feat.FrontMatter.ID = fileID
+}
+
+// fixDates rewrites created and updated dates given in another format as YYYY-MM-DD.
+func fixDates(feat *feature.Feature, add addFix) {
+	for _, field := range []struct {
+		key   string
+		value *string
+	}{{"created", &feat.FrontMatter.Created}, {"updated", &feat.FrontMatter.Updated}} {
+		if normalized, ok := normalizeDate(*field.value); ok && normalized != *field.value {
+			add("dates", "normalized %s %q to %s", field.key, *field.value, normalized)
+			*field.value = normalized
+		}
+	}
+}
+
+// fixTemplate runs the template processor and reports the sections and
+// frontmatter defaults it added.
+func (v *Validator) fixTemplate(feat *feature.Feature, processor func(*feature.Feature) error, add addFix) error {
+	order, _ := feature.ExtractSections(feat.Body)
+	defaults := map[string]*string{
+		"status":     &feat.FrontMatter.Status,
+		"priority":   &feat.FrontMatter.Priority,
+		"complexity": &feat.FrontMatter.Complexity,
+	}
+	empty := map[string]bool{}
+	for key, value := range defaults {
+		empty[key] = *value == ""
+	}
+
+	if err := processor(feat); err != nil {
+		return err
+	}
+
+	had := map[string]bool{}
+	for _, name := range order {
+		had[name] = true
+	}
+	after, _ := feature.ExtractSections(feat.Body)
+	var added []string
+	for _, name := range after {
+		if !had[name] {
+			added = append(added, name)
+		}
+	}
+	if len(added) > 0 {
+		add("template", "added missing sections: %s", strings.Join(added, ", "))
+	}
+	for _, key := range []string{"status", "priority", "complexity"} {
+		if empty[key] && *defaults[key] != "" {
+			add("template", "set empty %s to %s from the template", key, *defaults[key])
+		}
+	}
+	return nil
+}
+
+// fixPath returns the path the feature belongs at: the directory for its status
+// and the name for its ID and title.
+func (v *Validator) fixPath(feat *feature.Feature, add addFix) string {
+	dir := filepath.Dir(feat.Path)
+	if statusDir := v.mgr.Workflow().DirectoryForStatus(feat.FrontMatter.Status); statusDir != "" {
+		expected := filepath.Join(v.mgr.FeaturesDir(), strings.TrimPrefix(statusDir, "features/"))
+		if !strings.EqualFold(filepath.Clean(expected), filepath.Clean(dir)) {
+			add("directory", "moved from %s to %s for status %s", v.relative(dir), v.relative(expected), feat.FrontMatter.Status)
+			dir = expected
+		}
+	}
+	name := filepath.Base(feat.Path)
+	if expected := fmt.Sprintf("%s-%s.md", feat.FrontMatter.ID, util.Slugify(feat.FrontMatter.Title)); !strings.EqualFold(name, expected) {
+		add("filename", "renamed %s to %s", name, expected)
+		name = expected
+	}
+	return filepath.Join(dir, name)
+}
+
+// relative returns path relative to the workspace directory.
+func (v *Validator) relative(path string) string {
+	root := filepath.Dir(v.mgr.FeaturesDir())
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+func normalizeID(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
+}
+
+// normalizeDate parses value in one of dateLayouts and formats it as YYYY-MM-DD.
+func normalizeDate(value string) (string, bool) {
+	trimmed := strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, trimmed); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// cleanList trims entries and drops empty and duplicate ones, uppercasing them
+// first when upper is set. Nil stays nil.
+func cleanList(values []string, upper bool) []string {
+	if values == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	cleaned := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if upper {
+			value = strings.ToUpper(value)
+		}
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		cleaned = append(cleaned, value)
+	}
+	return cleaned
+}
+
+func equalLists(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatList renders a list with each entry quoted, so whitespace is visible.
+func formatList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/patch"
+	"github.com/virtualboard/vb-cli/internal/testutil"
+)
+
+func noopProcessor(*feature.Feature) error { return nil }
+
+func fixMessages(report *FixReport) string {
+	lines := make([]string, 0, len(report.Fixes))
+	for _, f := range report.Fixes {
+		lines = append(lines, f.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestApplyFixes(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	misplaced := newFeature(mgr, "FTR-0001", "in-progress", "Misplaced", nil)
+	misplaced.Path = filepath.Join(opts.RootDir, "features", "backlog", "FTR-0001-misplaced.md")
+	misplaced.FrontMatter.Labels = []string{" api", "api", "", "ui"}
+	misplaced.FrontMatter.Dependencies = []string{"ftr-0002", "FTR-0002 ", " "}
+	misplaced.FrontMatter.Owner = " "
+	writeFeature(t, fix, misplaced)
+
+	messy := newFeature(mgr, "ftr-0002", "backlog", "Messy", nil)
+	messy.Path = filepath.Join(opts.RootDir, "features", "backlog", "FTR-0002-old-title.md")
+	messy.FrontMatter.Created = "2024/01/05"
+	messy.FrontMatter.Updated = "March 3, 2024"
+	writeFeature(t, fix, messy)
+
+	clean := newFeature(mgr, "FTR-0003", "backlog", "Clean", nil)
+	writeFeature(t, fix, clean)
+	cleanData, _ := os.ReadFile(clean.Path)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	feats, err := v.CollectFeatures()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	report, err := v.ApplyFixes(feats, noopProcessor)
+	if err != nil {
+		t.Fatalf("apply fixes failed: %v", err)
+	}
+	want := strings.Join([]string{
+		`FTR-0001 [labels] cleaned labels [" api", "api", "", "ui"] to ["api", "ui"]`,
+		`FTR-0001 [dependencies] cleaned dependencies ["ftr-0002", "FTR-0002 ", " "] to ["FTR-0002"]`,
+		`FTR-0001 [owner] set empty owner to unassigned`,
+		`FTR-0001 [directory] moved from features/backlog to features/in-progress for status in-progress`,
+		`FTR-0002 [id] normalized id "ftr-0002" to FTR-0002`,
+		`FTR-0002 [dates] normalized created "2024/01/05" to 2024-01-05`,
+		`FTR-0002 [dates] normalized updated "March 3, 2024" to 2024-03-03`,
+		`FTR-0002 [filename] renamed FTR-0002-old-title.md to FTR-0002-messy.md`,
+	}, "\n")
+	if got := fixMessages(report); got != want {
+		t.Fatalf("unexpected fixes:\n%s", got)
+	}
+	if len(mgr.Patches()) != 0 {
+		t.Fatalf("expected no planned patches outside dry-run")
+	}
+
+	moved, err := mgr.LoadByID("FTR-0001")
+	if err != nil || filepath.Base(filepath.Dir(moved.Path)) != "in-progress" || moved.FrontMatter.Owner != "unassigned" ||
+		strings.Join(moved.FrontMatter.Labels, ",") != "api,ui" || strings.Join(moved.FrontMatter.Dependencies, ",") != "FTR-0002" {
+		t.Fatalf("expected fixed feature, got %v %+v", err, moved)
+	}
+	renamed, err := mgr.LoadByID("FTR-0002")
+	if err != nil || filepath.Base(renamed.Path) != "FTR-0002-messy.md" || renamed.FrontMatter.ID != "FTR-0002" || renamed.FrontMatter.Created != "2024-01-05" {
+		t.Fatalf("expected renamed feature, got %v %+v", err, renamed)
+	}
+	if data, _ := os.ReadFile(clean.Path); string(data) != string(cleanData) {
+		t.Fatalf("expected the clean feature to be left untouched")
+	}
+
+	feats, _ = v.CollectFeatures()
+	if report, err = v.ApplyFixes(feats, noopProcessor); err != nil || len(report.Fixes) != 0 {
+		t.Fatalf("expected fixes to be idempotent, got %v %+v", err, report)
+	}
+}
+
+func TestApplyFixesIDReconciliation(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	original := newFeature(mgr, "FTR-0001", "backlog", "Original", nil)
+	writeFeature(t, fix, original)
+	copied := newFeature(mgr, "FTR-0001", "backlog", "Copy", nil)
+	copied.Path = filepath.Join(filepath.Dir(copied.Path), "FTR-0002-copy.md")
+	writeFeature(t, fix, copied)
+	blank := newFeature(mgr, "FTR-0003", "backlog", "Blank", nil)
+	blank.FrontMatter.ID = "tbd"
+	writeFeature(t, fix, blank)
+	renumbered := newFeature(mgr, "FTR-0005", "backlog", "Renumbered", nil)
+	renumbered.Path = filepath.Join(filepath.Dir(renumbered.Path), "FTR-0004-renumbered.md")
+	writeFeature(t, fix, renumbered)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	feats, _ := v.CollectFeatures()
+	report, err := v.ApplyFixes(feats, noopProcessor)
+	if err != nil {
+		t.Fatalf("apply fixes failed: %v", err)
+	}
+	want := strings.Join([]string{
+		`FTR-0002 [id] set id to FTR-0002 from the filename; FTR-0001 is used by another feature`,
+		`FTR-0003 [id] set id "tbd" to FTR-0003 from the filename`,
+		`FTR-0005 [filename] renamed FTR-0004-renumbered.md to FTR-0005-renumbered.md`,
+	}, "\n")
+	if got := fixMessages(report); got != want {
+		t.Fatalf("unexpected fixes:\n%s", got)
+	}
+	summary, err := v.ValidateAll()
+	if err != nil || summary.Invalid != 0 || summary.Total != 4 {
+		t.Fatalf("expected a valid workspace after fixing: %v %+v", err, summary)
+	}
+}
+
+func TestApplyFixesDryRunAndTemplate(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, true)
+	mgr := feature.NewManager(opts)
+
+	sparse := newFeature(mgr, "FTR-0001", "backlog", "Sparse", nil)
+	sparse.FrontMatter.Priority = ""
+	sparse.Path = filepath.Join(filepath.Dir(sparse.Path), "FTR-0001-old.md")
+	writeFeature(t, fix, sparse)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	feats, _ := v.CollectFeatures()
+	report, err := v.ApplyFixes(feats, func(feat *feature.Feature) error {
+		feat.AddMissingSections([]string{"Summary", "Notes", "Risks"}, nil)
+		feat.FrontMatter.Priority = "high"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("apply fixes failed: %v", err)
+	}
+	want := strings.Join([]string{
+		`FTR-0001 [template] added missing sections: Notes, Risks`,
+		`FTR-0001 [template] set empty priority to high from the template`,
+		`FTR-0001 [filename] renamed FTR-0001-old.md to FTR-0001-sparse.md`,
+	}, "\n")
+	if got := fixMessages(report); got != want {
+		t.Fatalf("unexpected fixes:\n%s", got)
+	}
+	for _, line := range []string{"+## Notes\n", "+priority: \"high\"\n", "rename to .virtualboard/features/backlog/FTR-0001-sparse.md\n"} {
+		if diff := patch.Join(mgr.Patches()); !strings.Contains(diff, line) {
+			t.Fatalf("expected %q in diff:\n%s", line, diff)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(sparse.Path), "FTR-0001-old.md")); err != nil {
+		t.Fatalf("dry run must not rename: %v", err)
+	}
+
+	boom := errors.New("boom")
+	if _, err := v.ApplyFixes(feats, func(*feature.Feature) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected processor error, got %v", err)
+	}
+	fix.WriteFile(t, "features/backlog/broken.md", []byte("not frontmatter"))
+	if _, err := v.ApplyFixes(feats, noopProcessor); err == nil {
+		t.Fatalf("expected list error")
+	}
+}
+
+func TestNormalizeDate(t *testing.T) {
+	for value, want := range map[string]string{
+		"2024-01-05":           "2024-01-05",
+		" 2024-1-5 ":           "2024-01-05",
+		"2024.01.05":           "2024-01-05",
+		"20240105":             "2024-01-05",
+		"2024-01-05T10:00:00Z": "2024-01-05",
+		"2024-01-05 10:00":     "2024-01-05",
+		"5 January 2024":       "2024-01-05",
+	} {
+		if got, ok := normalizeDate(value); !ok || got != want {
+			t.Fatalf("%q: expected %s, got %s (%v)", value, want, got, ok)
+		}
+	}
+	for _, value := range []string{"01/05/2024", "yesterday", ""} {
+		if _, ok := normalizeDate(value); ok {
+			t.Fatalf("%q: expected no normalization", value)
+		}
+	}
+	if cleanList(nil, true) != nil {
+		t.Fatalf("expected nil list to stay nil")
+	}
+	if equalLists([]string{"a"}, []string{"b"}) {
+		t.Fatalf("expected different lists")
+	}
+}
@@ -3,22 +3,41 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/virtualboard/vb-cli/internal/config"
+	"github.com/virtualboard/vb-cli/internal/epic"
 	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/graph"
+	"github.com/virtualboard/vb-cli/internal/rules"
 	"github.com/virtualboard/vb-cli/internal/util"
 )
 
 // Result represents validation outcome for a single feature.
 type Result struct {
 	Feature *feature.Feature
-	Errors  []string
+	// Errors holds the messages of error-severity findings.
+	Errors []string
+	// Findings holds every reported finding, including warnings and info.
+	Findings []rules.Finding
+
+	// locator is built on the first reported finding, so clean features are not re-read.
+	locator *rules.Locator
+}
+
+func (r *Result) add(f rules.Finding) {
+	r.Findings = append(r.Findings, f)
+	if f.Severity == rules.Error {
+		r.Errors = append(r.Errors, f.Message)
+	}
 }
 
 // Summary aggregates validation results.
@@ -26,6 +45,7 @@ type Summary struct {
 	Total      int               `json:"total"`
 	Valid      int               `json:"valid"`
 	Invalid    int               `json:"invalid"`
+	Warnings   int               `json:"warnings"`
 	ErrorCount map[string]int    `json:"error_counts"`
 	Results    map[string]Result `json:"results"`
 }
@@ -35,39 +55,187 @@ type Validator struct {
 	mgr          *feature.Manager
 	schemaLoader gojsonschema.JSONLoader
 	log          *logrus.Entry
+	rules        *rules.Set
+
+	epics       *epic.Manager
+	epicIDs     map[string]bool
+	epicErr     error
+	epicsLoaded bool
+}
+
+// featureRule is a rule checked against one feature at a time.
+type featureRule struct {
+	rules.Rule
+	check func(v *Validator, feat *feature.Feature, r *rules.Rule) []rules.Problem
+}
+
+var featureRules = []featureRule{
+	{rules.Rule{ID: "schema", Description: "Frontmatter matches the JSON schema", Severity: rules.Error}, (*Validator).checkSchema},
+	{rules.Rule{ID: "status-directory", Description: "The status is defined and the file is in its directory", Severity: rules.Error}, (*Validator).checkStatusDirectory},
+	{rules.Rule{ID: "filename", Description: "The filename is <id>-<slugified title>.md", Severity: rules.Error}, (*Validator).checkFilename},
+	{rules.Rule{ID: "dates", Description: "created and updated are YYYY-MM-DD", Severity: rules.Error}, (*Validator).checkDates},
+	{rules.Rule{ID: "custom-fields", Description: "Custom fields match their declared types", Severity: rules.Error}, (*Validator).checkCustomFields},
+	{rules.Rule{ID: "status-rules", Description: "The feature meets the rules configured for its status", Severity: rules.Error}, (*Validator).checkStatusRules},
+	{rules.Rule{ID: "checklist-done", Description: "Done features have no unchecked gated task items", Severity: rules.Error}, (*Validator).checkChecklist},
+	{rules.Rule{ID: "epic-ref", Description: "The epic reference resolves to a defined epic", Severity: rules.Error}, (*Validator).checkEpicRef},
+	{rules.Rule{ID: "title-length", Description: "The title is at most max characters long", Severity: rules.Warning, Options: map[string]interface{}{"max": 72}}, (*Validator).checkTitleLength},
+}
+
+// crossRules compare features with each other and run in ValidateAll and ValidateID.
+var crossRules = []rules.Rule{
+	{ID: "duplicate-id", Description: "Feature IDs are unique", Severity: rules.Error},
+	{ID: "dependency-missing", Description: "Dependencies refer to existing features", Severity: rules.Error},
+	{ID: "dependency-unfinished", Description: "Dependencies are done before the feature reaches a gated status", Severity: rules.Error},
+	{ID: "dependency-cycle", Description: "Dependencies do not form a cycle", Severity: rules.Error},
+}
+
+// DefaultRules returns every feature rule with its default severity and options.
+func DefaultRules() []rules.Rule {
+	defs := make([]rules.Rule, 0, len(featureRules)+len(crossRules))
+	for _, r := range featureRules {
+		defs = append(defs, r.Rule)
+	}
+	return append(defs, crossRules...)
 }
 
 // New creates a validator configured for the manager.
 func New(opts *config.Options, mgr *feature.Manager) (*Validator, error) {
 	schemaPath := mgr.SchemaPath()
 	loader := gojsonschema.NewReferenceLoader("file://" + filepath.ToSlash(schemaPath))
+	set := rules.NewSet(DefaultRules())
+	if err := set.Configure(opts.Settings.Validation.Rules); err != nil {
+		return nil, fmt.Errorf("invalid config: validation.rules: %w", err)
+	}
 	return &Validator{
 		mgr:          mgr,
 		schemaLoader: loader,
 		log:          opts.Logger().WithField("component", "validator"),
+		rules:        set,
+		epics:        epic.NewManager(opts),
 	}, nil
 }
 
+// Rules returns the validator's rule set, e.g. to narrow it with Select before a run.
+func (v *Validator) Rules() *rules.Set {
+	return v.rules
+}
+
+// report adds a finding for the rule unless it is off or the feature suppresses it.
+func (v *Validator) report(res *Result, id string, p rules.Problem) {
+	var suppressed map[string]bool
+	if res.Feature != nil {
+		suppressed = rules.Suppressed(res.Feature.FrontMatter.Custom[rules.DisableKey], res.Feature.Body)
+	}
+	if f, ok := v.rules.Finding(id, p.Message, suppressed); ok {
+		if res.Feature != nil {
+			if res.locator == nil {
+				res.locator = locate(res.Feature)
+			}
+			f.Line = res.locator.Line(p)
+		}
+		res.add(f)
+	}
+}
+
+// locate indexes the feature file for line numbers, falling back to the
+// encoded feature when the file cannot be read.
+func locate(feat *feature.Feature) *rules.Locator {
+	// #nosec G304 -- feature paths come from workspace discovery
+	data, err := os.ReadFile(feat.Path)
+	if err != nil {
+		data, _ = feat.Encode()
+	}
+	return rules.NewLocator(data)
+}
+
 // ValidateAll runs validations across every feature.
 func (v *Validator) ValidateAll() (*Summary, error) {
 	features, err := v.mgr.List()
 	if err != nil {
 		return nil, err
 	}
+	return v.validate(features, nil), nil
+}
+
+// IDsAt returns the IDs of the features stored at the given paths, sorted.
+// Paths that are not feature files are ignored.
+func (v *Validator) IDsAt(paths []string) ([]string, error) {
+	features, err := v.mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	for _, path := range paths {
+		wanted[filepath.Clean(path)] = true
+	}
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, feat := range features {
+		if wanted[filepath.Clean(feat.Path)] && !seen[feat.FrontMatter.ID] {
+			seen[feat.FrontMatter.ID] = true
+			ids = append(ids, feat.FrontMatter.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ValidateNeighbourhood validates the given features together with their direct
+// dependencies and dependents, since dependency rules span files. Every feature
+// is still read: dependents and duplicate IDs can only be found by parsing all
+// files, so only the checks are limited to the neighbourhood.
+func (v *Validator) ValidateNeighbourhood(ids []string) (*Summary, error) {
+	features, err := v.mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]bool{}
+	for _, id := range ids {
+		changed[id] = true
+	}
+	selected := map[string]bool{}
+	for _, feat := range features {
+		id := feat.FrontMatter.ID
+		if changed[id] {
+			selected[id] = true
+		}
+		for _, dep := range feat.FrontMatter.Dependencies {
+			dep = strings.TrimSpace(dep)
+			if dep == "" {
+				continue
+			}
+			if changed[id] {
+				selected[dep] = true
+			}
+			if changed[dep] {
+				selected[id] = true
+			}
+		}
+	}
+	return v.validate(features, selected), nil
+}
+
+// validate checks the features whose IDs are selected, or all of them when
+// selected is nil, against each other and the rest of the list.
+func (v *Validator) validate(features []*feature.Feature, selected map[string]bool) *Summary {
 	results := make(map[string]Result)
 	errorCounts := map[string]int{}
 	idToFeature := map[string]*feature.Feature{}
 
 	for _, feat := range features {
-		if _, exists := idToFeature[feat.FrontMatter.ID]; exists {
-			res := v.validateSingle(feat)
-			res.Errors = append(res.Errors, fmt.Sprintf("duplicate ID detected for %s", feat.FrontMatter.ID))
-			results[feat.FrontMatter.ID] = res
+		id := feat.FrontMatter.ID
+		if _, exists := idToFeature[id]; exists {
+			if selected == nil || selected[id] {
+				res := v.validateSingle(feat)
+				v.report(&res, "duplicate-id", rules.Problem{Message: fmt.Sprintf("duplicate ID detected for %s", id), Key: "id"})
+				results[id] = res
+			}
 			continue
 		}
-		idToFeature[feat.FrontMatter.ID] = feat
-		res := v.validateSingle(feat)
-		results[feat.FrontMatter.ID] = res
+		idToFeature[id] = feat
+		if selected == nil || selected[id] {
+			results[id] = v.validateSingle(feat)
+		}
 	}
 
 	v.applyDependencyChecks(idToFeature, results)
@@ -75,6 +243,7 @@ func (v *Validator) ValidateAll() (*Summary, error) {
 	total := len(results)
 	valid := 0
 	invalid := 0
+	warnings := 0
 	for id, res := range results {
 		if len(res.Errors) == 0 {
 			valid++
@@ -82,6 +251,11 @@ func (v *Validator) ValidateAll() (*Summary, error) {
 			invalid++
 			errorCounts[id] = len(res.Errors)
 		}
+		for _, f := range res.Findings {
+			if f.Severity == rules.Warning {
+				warnings++
+			}
+		}
 	}
 
 	if invalid > 0 {
@@ -94,9 +268,10 @@ func (v *Validator) ValidateAll() (*Summary, error) {
 		Total:      total,
 		Valid:      valid,
 		Invalid:    invalid,
+		Warnings:   warnings,
 		ErrorCount: errorCounts,
 		Results:    results,
-	}, nil
+	}
 }
 
 // ValidateID runs validation on a specific feature.
@@ -125,199 +300,200 @@ func (v *Validator) ValidateID(id string) (Result, error) {
 }
 
 func (v *Validator) validateSingle(feat *feature.Feature) Result {
-	errors := make([]string, 0)
+	res := Result{Feature: feat, Errors: []string{}, Findings: []rules.Finding{}}
+	for i := range featureRules {
+		r, _ := v.rules.Lookup(featureRules[i].ID)
+		if !v.rules.Enabled(r.ID) {
+			continue
+		}
+		for _, p := range featureRules[i].check(v, feat, r) {
+			v.report(&res, r.ID, p)
+		}
+	}
+	return res
+}
 
-	docLoader := gojsonschema.NewGoLoader(feat.FrontMatter)
-	result, err := gojsonschema.Validate(v.schemaLoader, docLoader)
+func (v *Validator) checkSchema(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	doc := feat.FrontMatter.Map()
+	delete(doc, rules.DisableKey)
+	result, err := gojsonschema.Validate(v.schemaLoader, gojsonschema.NewGoLoader(doc))
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("schema validation error: %v", err))
-	} else if !result.Valid() {
-		for _, desc := range result.Errors() {
-			errors = append(errors, desc.String())
-		}
+		return []rules.Problem{{Message: fmt.Sprintf("schema validation error: %v", err)}}
 	}
+	var problems []rules.Problem
+	for _, desc := range result.Errors() {
+		problems = append(problems, rules.Problem{Message: desc.String(), Key: rules.SchemaKey(desc)})
+	}
+	return problems
+}
 
-	dir := feature.DirectoryForStatus(feat.FrontMatter.Status)
-	statusSubdir := strings.TrimPrefix(dir, "features/")
-	expectedDir := filepath.Join(v.mgr.FeaturesDir(), statusSubdir)
-	actualDir := filepath.Dir(feat.Path)
+func (v *Validator) checkStatusDirectory(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	dir := v.mgr.Workflow().DirectoryForStatus(feat.FrontMatter.Status)
 	if dir == "" {
-		errors = append(errors, fmt.Sprintf("invalid status %s", feat.FrontMatter.Status))
-	} else {
-		expectedClean := filepath.Clean(expectedDir)
-		actualClean := filepath.Clean(actualDir)
-		if !strings.EqualFold(expectedClean, actualClean) {
-			errors = append(errors, fmt.Sprintf("status '%s' requires directory %s", feat.FrontMatter.Status, expectedDir))
-		}
+		return []rules.Problem{{Message: fmt.Sprintf("invalid status %s", feat.FrontMatter.Status), Key: "status"}}
 	}
+	expectedDir := filepath.Join(v.mgr.FeaturesDir(), strings.TrimPrefix(dir, "features/"))
+	if !strings.EqualFold(filepath.Clean(expectedDir), filepath.Clean(filepath.Dir(feat.Path))) {
+		return []rules.Problem{{Message: fmt.Sprintf("status '%s' requires directory %s", feat.FrontMatter.Status, expectedDir), Key: "status"}}
+	}
+	return nil
+}
 
+func (v *Validator) checkFilename(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
 	expectedName := fmt.Sprintf("%s-%s.md", feat.FrontMatter.ID, util.Slugify(feat.FrontMatter.Title))
 	if base := filepath.Base(feat.Path); !strings.EqualFold(base, expectedName) {
-		errors = append(errors, fmt.Sprintf("filename '%s' should be '%s'", base, expectedName))
+		return []rules.Problem{{Message: fmt.Sprintf("filename '%s' should be '%s'", base, expectedName)}}
 	}
+	return nil
+}
 
+func (v *Validator) checkDates(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	var problems []rules.Problem
 	if _, err := time.Parse("2006-01-02", feat.FrontMatter.Created); err != nil {
-		errors = append(errors, "created date must be YYYY-MM-DD")
+		problems = append(problems, rules.Problem{Message: "created date must be YYYY-MM-DD", Key: "created"})
 	}
 	if _, err := time.Parse("2006-01-02", feat.FrontMatter.Updated); err != nil {
-		errors = append(errors, "updated date must be YYYY-MM-DD")
+		problems = append(problems, rules.Problem{Message: "updated date must be YYYY-MM-DD", Key: "updated"})
 	}
-
-	return Result{Feature: feat, Errors: errors}
+	return problems
 }
 
-func (v *Validator) applyDependencyChecks(features map[string]*feature.Feature, results map[string]Result) {
-	for id, feat := range features {
-		res := results[id]
-		for _, dep := range feat.FrontMatter.Dependencies {
-			dep = strings.TrimSpace(dep)
-			if dep == "" {
-				continue
-			}
-			depFeat, ok := features[dep]
-			if !ok {
-				res.Errors = append(res.Errors, fmt.Sprintf("dependency %s not found", dep))
-				continue
-			}
-			if strings.ToLower(feat.FrontMatter.Status) == "in-progress" && strings.ToLower(depFeat.FrontMatter.Status) != "done" {
-				res.Errors = append(res.Errors, fmt.Sprintf("dependency %s must be done before moving to in-progress", dep))
+func (v *Validator) checkCustomFields(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	var problems []rules.Problem
+	defs, err := v.mgr.CustomFields()
+	if err != nil {
+		problems = append(problems, rules.Problem{Message: fmt.Sprintf("custom fields: %v", err)})
+	}
+	for _, name := range defs.Names() {
+		if value, ok := feat.FrontMatter.Custom[name]; ok {
+			if err := defs[name].Check(value); err != nil {
+				problems = append(problems, rules.Problem{Message: err.Error(), Key: name})
 			}
 		}
-		results[id] = res
 	}
+	return problems
+}
 
-	cycles := findCycles(features)
-	for _, cycle := range cycles {
-		message := "circular dependency detected: " + strings.Join(cycle, " -> ")
-		for _, id := range cycle {
-			res := results[id]
-			res.Errors = append(res.Errors, message)
-			results[id] = res
-		}
+func (v *Validator) checkStatusRules(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	var problems []rules.Problem
+	for _, violation := range v.mgr.RuleViolations(feat, feat.FrontMatter.Status) {
+		problems = append(problems, rules.Problem{Message: violation.Message, Key: violation.Field, Heading: violation.Section})
 	}
+	return problems
 }
 
-func findCycles(features map[string]*feature.Feature) [][]string {
-	graph := make(map[string][]string)
-	for id, feat := range features {
-		deps := make([]string, 0, len(feat.FrontMatter.Dependencies))
-		for _, dep := range feat.FrontMatter.Dependencies {
-			dep = strings.TrimSpace(dep)
-			if dep != "" {
-				deps = append(deps, dep)
-			}
-		}
-		graph[id] = deps
-	}
-
-	visited := map[string]bool{}
-	onStack := map[string]bool{}
-	stack := make([]string, 0)
-	cycles := [][]string{}
-
-	var dfs func(string)
-	dfs = func(node string) {
-		visited[node] = true
-		onStack[node] = true
-		stack = append(stack, node)
-
-		for _, dep := range graph[node] {
-			if !visited[dep] {
-				dfs(dep)
-			} else if onStack[dep] {
-				cycle := extractCycle(stack, dep)
-				if len(cycle) > 0 {
-					cycles = append(cycles, cycle)
-				}
-			}
-		}
-
-		onStack[node] = false
-		stack = stack[:len(stack)-1]
+func (v *Validator) checkChecklist(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	if !v.mgr.Workflow().IsDone(feat.FrontMatter.Status) {
+		return nil
+	}
+	if unchecked := v.mgr.UncheckedGated(feat); len(unchecked) > 0 {
+		return []rules.Problem{{
+			Message: fmt.Sprintf("%s but status is %s", feature.DescribeUnchecked(unchecked), feat.FrontMatter.Status),
+			Key:     "status",
+			Heading: unchecked[0].Section,
+		}}
 	}
+	return nil
+}
 
-	for node := range graph {
-		if !visited[node] {
-			dfs(node)
+func (v *Validator) checkEpicRef(feat *feature.Feature, _ *rules.Rule) []rules.Problem {
+	if ref := strings.TrimSpace(feat.FrontMatter.Epic); ref != "" {
+		if msg := v.checkEpic(ref); msg != "" {
+			return []rules.Problem{{Message: msg, Key: "epic"}}
 		}
 	}
+	return nil
+}
 
-	return dedupeCycles(cycles)
+func (v *Validator) checkTitleLength(feat *feature.Feature, r *rules.Rule) []rules.Problem {
+	limit := r.Int("max")
+	if length := utf8.RuneCountInString(feat.FrontMatter.Title); limit > 0 && length > limit {
+		return []rules.Problem{{Message: fmt.Sprintf("title is %d characters long; keep it to %d", length, limit), Key: "title"}}
+	}
+	return nil
 }
 
-func extractCycle(stack []string, start string) []string {
-	idx := -1
-	for i := len(stack) - 1; i >= 0; i-- {
-		if stack[i] == start {
-			idx = i
-			break
+// checkEpic reports a problem with an epic reference, or "" when it resolves.
+// Workspaces without an epics directory keep epic as a free-form label.
+func (v *Validator) checkEpic(ref string) string {
+	if !v.epicsLoaded {
+		v.epicsLoaded = true
+		if v.epics.Defined() {
+			v.epicIDs = map[string]bool{}
+			epics, err := v.epics.List()
+			v.epicErr = err
+			for _, e := range epics {
+				v.epicIDs[epic.Key(e.FrontMatter.ID)] = true
+			}
 		}
 	}
-	if idx == -1 {
-		return nil
+	switch {
+	case v.epicIDs == nil:
+		return ""
+	case v.epicErr != nil:
+		return fmt.Sprintf("epic %s could not be checked: %v", ref, v.epicErr)
+	case !v.epicIDs[epic.Key(ref)]:
+		return fmt.Sprintf("epic %s not found", ref)
 	}
-	cycle := append([]string{}, stack[idx:]...)
-	cycle = append(cycle, start)
-	return cycle
+	return ""
 }
 
-func dedupeCycles(cycles [][]string) [][]string {
-	unique := [][]string{}
-	seen := map[string]struct{}{}
-	for _, cycle := range cycles {
-		if len(cycle) == 0 {
+// applyDependencyChecks reports dependency problems for the features that have a
+// result; the others only serve as dependency targets.
+func (v *Validator) applyDependencyChecks(features map[string]*feature.Feature, results map[string]Result) {
+	wf := v.mgr.Workflow()
+	for id, feat := range features {
+		res, ok := results[id]
+		if !ok {
 			continue
 		}
-		key := strings.Join(cycle, "->")
-		if _, ok := seen[key]; ok {
-			continue
+		for _, dep := range feat.FrontMatter.Dependencies {
+			dep = strings.TrimSpace(dep)
+			if dep == "" {
+				continue
+			}
+			depFeat, ok := features[dep]
+			if !ok {
+				v.report(&res, "dependency-missing", rules.Problem{Message: fmt.Sprintf("dependency %s not found", dep), Key: "dependencies"})
+				continue
+			}
+			if wf.RequiresDoneDependencies(feat.FrontMatter.Status) && !wf.IsDone(depFeat.FrontMatter.Status) {
+				v.report(&res, "dependency-unfinished", rules.Problem{Message: fmt.Sprintf("dependency %s must be %s before moving to %s", dep, wf.Done, strings.ToLower(feat.FrontMatter.Status)), Key: "dependencies"})
+			}
 		}
-		seen[key] = struct{}{}
-		unique = append(unique, cycle)
+		results[id] = res
 	}
-	return unique
-}
 
-// ApplyFixes applies non-destructive fixes (template re-application and filename syncing).
-func (v *Validator) ApplyFixes(features map[string]*feature.Feature, processor func(*feature.Feature) error) error {
+	list := make([]*feature.Feature, 0, len(features))
 	for _, feat := range features {
-		// Apply template fixes
-		if err := processor(feat); err != nil {
-			return err
-		}
-
-		// Sync filename with title
-		if _, err := v.mgr.RenameToMatchTitle(feat); err != nil {
-			return err
-		}
-
-		// Save if not already saved by rename
-		if err := v.mgr.Save(feat); err != nil {
-			return err
+		list = append(list, feat)
+	}
+	for _, cycle := range graph.New(list, wf).Cycles() {
+		message := "circular dependency detected: " + strings.Join(cycle, " -> ")
+		for _, id := range cycle {
+			res, ok := results[id]
+			if !ok {
+				continue
+			}
+			v.report(&res, "dependency-cycle", rules.Problem{Message: message, Key: "dependencies"})
+			results[id] = res
 		}
 	}
-	return nil
 }
 
-// CollectFeatures returns a map of ID to feature for fix workflows.
-func (v *Validator) CollectFeatures(ids ...string) (map[string]*feature.Feature, error) {
-	features := map[string]*feature.Feature{}
+// CollectFeatures returns the features for fix workflows, every feature when no
+// IDs are given. Features sharing an ID are all returned.
+func (v *Validator) CollectFeatures(ids ...string) ([]*feature.Feature, error) {
 	if len(ids) == 0 {
-		all, err := v.mgr.List()
-		if err != nil {
-			return nil, err
-		}
-		for _, feat := range all {
-			features[feat.FrontMatter.ID] = feat
-		}
-		return features, nil
+		return v.mgr.List()
 	}
+	features := make([]*feature.Feature, 0, len(ids))
 	for _, id := range ids {
 		feat, err := v.mgr.LoadByID(id)
 		if err != nil {
 			return nil, err
 		}
-		features[feat.FrontMatter.ID] = feat
+		features = append(features, feat)
 	}
 	return features, nil
 }
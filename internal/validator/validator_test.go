@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/virtualboard/vb-cli/internal/config"
 	"github.com/virtualboard/vb-cli/internal/feature"
+	"github.com/virtualboard/vb-cli/internal/rules"
 	"github.com/virtualboard/vb-cli/internal/testutil"
 	"github.com/virtualboard/vb-cli/internal/util"
 )
@@ -74,7 +77,7 @@ func TestValidatorWorkflow(t *testing.T) {
 	}
 
 	collection, err := v.CollectFeatures()
-	if err != nil || len(collection) != summary.Total {
+	if err != nil || len(collection) != summary.Total+1 {
 		t.Fatalf("collect all failed: %v %d", err, len(collection))
 	}
 
@@ -84,7 +87,7 @@ func TestValidatorWorkflow(t *testing.T) {
 	}
 
 	applyCalled := false
-	if err := v.ApplyFixes(filtered, func(feat *feature.Feature) error {
+	if _, err := v.ApplyFixes(filtered, func(feat *feature.Feature) error {
 		applyCalled = true
 		feat.FrontMatter.Owner = "fixed"
 		return nil
@@ -97,7 +100,7 @@ func TestValidatorWorkflow(t *testing.T) {
 }
 
 func newFeature(mgr *feature.Manager, id, status, title string, labels []string) *feature.Feature {
-	statusDir := filepath.Join(mgr.FeaturesDir(), filepath.Base(feature.DirectoryForStatus(status)))
+	statusDir := filepath.Join(mgr.FeaturesDir(), filepath.Base(mgr.Workflow().DirectoryForStatus(status)))
 	return &feature.Feature{
 		Path: filepath.Join(statusDir, fmt.Sprintf("%s-%s.md", id, util.Slugify(title))),
 		FrontMatter: feature.FrontMatter{
@@ -128,3 +131,375 @@ func writeFeature(t *testing.T, fix *testutil.Fixture, feat *feature.Feature) {
 	}
 	fix.WriteFile(t, rel, data)
 }
+
+func TestValidatorCustomWorkflow(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "workflow.yaml", []byte(`statuses:
+  - name: backlog
+    transitions: [in-progress]
+  - name: in-progress
+    transitions: [qa]
+    requires_done_dependencies: true
+  - name: qa
+    directory: features/quality
+    transitions: [done]
+    requires_done_dependencies: true
+  - name: done
+`))
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	qa := newFeature(mgr, "FTR-0001", "qa", "In QA", nil)
+	qa.Path = filepath.Join(opts.RootDir, "features", "quality", "FTR-0001-in-qa.md")
+	qa.FrontMatter.Dependencies = []string{"FTR-0002"}
+	writeFeature(t, fix, qa)
+
+	dep := newFeature(mgr, "FTR-0002", "backlog", "Dep", nil)
+	writeFeature(t, fix, dep)
+
+	blocked := newFeature(mgr, "FTR-0003", "blocked", "Old Status", nil)
+	writeFeature(t, fix, blocked)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	summary, err := v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+
+	qaErrors := summary.Results["FTR-0001"].Errors
+	if len(qaErrors) != 1 || qaErrors[0] != "dependency FTR-0002 must be done before moving to qa" {
+		t.Fatalf("expected only a dependency error for qa feature, got %v", qaErrors)
+	}
+	if len(summary.Results["FTR-0002"].Errors) != 0 {
+		t.Fatalf("expected backlog feature to be valid: %v", summary.Results["FTR-0002"].Errors)
+	}
+	blockedErrors := summary.Results["FTR-0003"].Errors
+	if len(blockedErrors) == 0 || blockedErrors[0] != "invalid status blocked" {
+		t.Fatalf("expected status outside workflow to be rejected, got %v", blockedErrors)
+	}
+}
+
+func TestValidatorCustomFields(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	fix.WriteFile(t, "config.yaml", []byte("fields:\n  estimate: int\n  due: date\n"))
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	good := newFeature(mgr, "FTR-0001", "backlog", "Good", nil)
+	good.FrontMatter.Custom = map[string]interface{}{"estimate": 3, "due": "2026-01-01", "jira": "PAY-1"}
+	writeFeature(t, fix, good)
+	bad := newFeature(mgr, "FTR-0002", "backlog", "Bad", nil)
+	bad.FrontMatter.Custom = map[string]interface{}{"estimate": "three", "due": "soon"}
+	writeFeature(t, fix, bad)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	summary, err := v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+	if errs := summary.Results["FTR-0001"].Errors; len(errs) != 0 {
+		t.Fatalf("expected valid custom fields, got %v", errs)
+	}
+	errs := summary.Results["FTR-0002"].Errors
+	if len(errs) != 2 || errs[0] != "field due must be a date (YYYY-MM-DD)" || errs[1] != "field estimate must be an integer" {
+		t.Fatalf("unexpected custom field errors: %v", errs)
+	}
+
+	fix.WriteFile(t, "config.yaml", []byte("fields:\n  title: string\n"))
+	opts = fix.Options(t, false, false, false)
+	mgr = feature.NewManager(opts)
+	v, _ = New(opts, mgr)
+	result, err := v.ValidateID("FTR-0001")
+	if err != nil {
+		t.Fatalf("validate id failed: %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "custom fields:") {
+		t.Fatalf("expected custom field definition error, got %v", result.Errors)
+	}
+}
+
+func TestValidatorEpicReferences(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	linked := newFeature(mgr, "FTR-0001", "backlog", "Linked", nil)
+	linked.FrontMatter.Epic = "Checkout"
+	writeFeature(t, fix, linked)
+	dangling := newFeature(mgr, "FTR-0002", "backlog", "Dangling", nil)
+	dangling.FrontMatter.Epic = "search"
+	writeFeature(t, fix, dangling)
+
+	v, _ := New(opts, mgr)
+	summary, err := v.ValidateAll()
+	if err != nil || summary.Invalid != 0 {
+		t.Fatalf("expected free-form epics without an epics directory, got %v %+v", err, summary)
+	}
+
+	fix.WriteFile(t, "epics/checkout.md", []byte("---\nid: checkout\ntitle: Checkout\nstatus: active\ncreated: 2026-01-01\nupdated: 2026-01-01\n---\n"))
+	v, _ = New(opts, mgr)
+	summary, err = v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+	if errs := summary.Results["FTR-0001"].Errors; len(errs) != 0 {
+		t.Fatalf("expected case-insensitive epic match, got %v", errs)
+	}
+	if errs := summary.Results["FTR-0002"].Errors; len(errs) != 1 || errs[0] != "epic search not found" {
+		t.Fatalf("expected missing epic error, got %v", errs)
+	}
+
+	fix.WriteFile(t, "epics/broken.md", []byte("broken"))
+	v, _ = New(opts, mgr)
+	result, err := v.ValidateID("FTR-0001")
+	if err != nil {
+		t.Fatalf("validate id failed: %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "epic Checkout could not be checked: failed to parse 1 epic file(s)") {
+		t.Fatalf("expected epic parse error, got %v", result.Errors)
+	}
+}
+
+func TestValidatorChecklistGate(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	done := newFeature(mgr, "FTR-0001", "done", "Done", nil)
+	done.Body += "\n## Acceptance Criteria\n- [ ] Pending\n"
+	writeFeature(t, fix, done)
+	open := newFeature(mgr, "FTR-0002", "review", "Open", nil)
+	open.Body = done.Body
+	writeFeature(t, fix, open)
+
+	v, _ := New(opts, mgr)
+	summary, err := v.ValidateAll()
+	if err != nil || summary.Invalid != 0 {
+		t.Fatalf("expected the gate to be disabled by default: %v %+v", err, summary)
+	}
+
+	opts.Settings.Checklist.BlockDone = true
+	summary, err = v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+	errs := summary.Results["FTR-0001"].Errors
+	if len(errs) != 1 || errs[0] != "1 unchecked item(s) in Acceptance Criteria but status is done" {
+		t.Fatalf("expected checklist error, got %v", errs)
+	}
+	if errs := summary.Results["FTR-0002"].Errors; len(errs) != 0 {
+		t.Fatalf("expected features before done to pass, got %v", errs)
+	}
+}
+
+func TestValidatorStatusRules(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	opts.Settings.Rules = map[string]config.StatusRules{"review": {RequiredSections: []string{"Acceptance Criteria"}}}
+	mgr := feature.NewManager(opts)
+
+	writeFeature(t, fix, newFeature(mgr, "FTR-0001", "review", "Missing", nil))
+	ok := newFeature(mgr, "FTR-0002", "review", "Present", nil)
+	ok.Body += "\n## Acceptance Criteria\n\n- [ ] Works\n"
+	writeFeature(t, fix, ok)
+
+	v, _ := New(opts, mgr)
+	summary, err := v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+	if errs := summary.Results["FTR-0001"].Errors; len(errs) != 1 || errs[0] != "status review requires a '## Acceptance Criteria' section" {
+		t.Fatalf("expected rule violation, got %v", errs)
+	}
+	if errs := summary.Results["FTR-0002"].Errors; len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+}
+
+func TestValidatorRuleEngine(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	long := newFeature(mgr, "FTR-0001", "backlog", strings.Repeat("Long title ", 8), nil)
+	writeFeature(t, fix, long)
+	misnamed := newFeature(mgr, "FTR-0002", "backlog", "Misnamed", nil)
+	misnamed.Path = filepath.Join(filepath.Dir(misnamed.Path), "FTR-0002-other.md")
+	writeFeature(t, fix, misnamed)
+	quiet := newFeature(mgr, "FTR-0003", "backlog", "Quiet", nil)
+	quiet.Path = filepath.Join(filepath.Dir(quiet.Path), "FTR-0003-other.md")
+	quiet.FrontMatter.Dependencies = []string{"FTR-0404"}
+	quiet.FrontMatter.Custom = map[string]interface{}{"vb-disable": []string{"filename"}}
+	quiet.Body += "\n<!-- vb-disable dependency-missing -->\n"
+	writeFeature(t, fix, quiet)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	summary, err := v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+	if summary.Invalid != 1 || summary.Warnings != 1 {
+		t.Fatalf("expected one invalid feature and one warning: %+v", summary)
+	}
+	res := summary.Results["FTR-0001"]
+	if len(res.Errors) != 0 || len(res.Findings) != 1 || res.Findings[0].Rule != "title-length" || res.Findings[0].Severity != rules.Warning {
+		t.Fatalf("expected a title-length warning, got %+v", res.Findings)
+	}
+	if res.Findings[0].Message != "title is 88 characters long; keep it to 72" {
+		t.Fatalf("unexpected message %q", res.Findings[0].Message)
+	}
+	if res := summary.Results["FTR-0002"]; len(res.Findings) != 1 || res.Findings[0].Rule != "filename" || len(res.Errors) != 1 {
+		t.Fatalf("expected a filename error, got %+v", res.Findings)
+	}
+	if res := summary.Results["FTR-0003"]; len(res.Findings) != 0 {
+		t.Fatalf("expected suppressed findings, got %+v", res.Findings)
+	}
+
+	opts.Settings.Validation.Rules = rules.Overrides{
+		"filename":     {Severity: rules.Warning},
+		"title-length": {Options: map[string]interface{}{"max": 100}},
+	}
+	v, err = New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	summary, _ = v.ValidateAll()
+	if summary.Invalid != 0 || summary.Warnings != 1 || summary.Results["FTR-0002"].Findings[0].Severity != rules.Warning {
+		t.Fatalf("expected configured severities: %+v", summary)
+	}
+
+	v.Rules().Select([]string{"title-length"}, nil)
+	if summary, _ = v.ValidateAll(); summary.Invalid != 0 || summary.Warnings != 0 {
+		t.Fatalf("expected only title-length to run: %+v", summary)
+	}
+
+	opts.Settings.Validation.Rules = rules.Overrides{"title-length": {Options: map[string]interface{}{"min": 1}}}
+	if _, err := New(opts, mgr); err == nil || !strings.Contains(err.Error(), "validation.rules: rule title-length has no option min") {
+		t.Fatalf("expected option error, got %v", err)
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range DefaultRules() {
+		if seen[r.ID] || r.Description == "" || r.Severity == "" {
+			t.Fatalf("unexpected rule %+v", r)
+		}
+		seen[r.ID] = true
+	}
+	for _, id := range []string{"schema", "filename", "duplicate-id", "dependency-cycle", "title-length"} {
+		if !seen[id] {
+			t.Fatalf("expected rule %s to be registered", id)
+		}
+	}
+}
+
+func TestValidatorFindingLines(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	opts.Settings.Rules = map[string]config.StatusRules{"backlog": {RequiredSections: []string{"Details"}, RequireOwner: true}}
+	mgr := feature.NewManager(opts)
+
+	content := "---\nid: FTR-0001\ntitle: Lines\nstatus: backlog\nowner: unassigned\npriority: medium\ncomplexity: S\ncreated: 2023-99-01\nupdated: 2023-01-01\nlabels: []\ndependencies: [FTR-0404]\n---\n# Lines\n\n## Details\n<!-- todo -->\n"
+	fix.WriteFile(t, "features/backlog/FTR-0001-lines.md", []byte(content))
+
+	v, _ := New(opts, mgr)
+	summary, err := v.ValidateAll()
+	if err != nil {
+		t.Fatalf("validate all failed: %v", err)
+	}
+	lines := map[string]int{}
+	for _, f := range summary.Results["FTR-0001"].Findings {
+		lines[f.Message] = f.Line
+	}
+	want := map[string]int{
+		"created date must be YYYY-MM-DD":                          8,
+		"status backlog requires an owner":                         5,
+		"status backlog requires a non-empty '## Details' section": 15,
+		"dependency FTR-0404 not found":                            11,
+	}
+	for message, line := range want {
+		if got, ok := lines[message]; !ok || got != line {
+			t.Fatalf("expected %q on line %d, got %d (%v)", message, line, got, lines)
+		}
+	}
+}
+
+func TestValidatorNeighbourhood(t *testing.T) {
+	fix := testutil.NewFixture(t)
+	opts := fix.Options(t, false, false, false)
+	mgr := feature.NewManager(opts)
+
+	dependent := newFeature(mgr, "FTR-0001", "backlog", "Dependent", nil)
+	dependent.FrontMatter.Dependencies = []string{"FTR-0002"}
+	writeFeature(t, fix, dependent)
+	changed := newFeature(mgr, "FTR-0002", "backlog", "Changed", nil)
+	changed.FrontMatter.Dependencies = []string{"FTR-0003", "FTR-0404", " "}
+	writeFeature(t, fix, changed)
+	dependency := newFeature(mgr, "FTR-0003", "backlog", "Dependency", nil)
+	dependency.FrontMatter.Dependencies = []string{"FTR-0005"}
+	writeFeature(t, fix, dependency)
+	unrelated := newFeature(mgr, "FTR-0005", "backlog", "Unrelated", nil)
+	unrelated.FrontMatter.Created = "yesterday"
+	writeFeature(t, fix, unrelated)
+	dup := newFeature(mgr, "FTR-0005", "backlog", "Unrelated Copy", nil)
+	writeFeature(t, fix, dup)
+	cycleA := newFeature(mgr, "FTR-0100", "backlog", "Cycle A", nil)
+	cycleA.FrontMatter.Dependencies = []string{"FTR-0101"}
+	writeFeature(t, fix, cycleA)
+	cycleB := newFeature(mgr, "FTR-0101", "backlog", "Cycle B", nil)
+	cycleB.FrontMatter.Dependencies = []string{"FTR-0100"}
+	writeFeature(t, fix, cycleB)
+
+	v, err := New(opts, mgr)
+	if err != nil {
+		t.Fatalf("validator init failed: %v", err)
+	}
+	ids, err := v.IDsAt([]string{changed.Path, filepath.Join(opts.RootDir, "specs", "x.md"), changed.Path + "/"})
+	if err != nil || strings.Join(ids, ",") != "FTR-0002" {
+		t.Fatalf("expected the changed feature, got %v (%v)", ids, err)
+	}
+
+	summary, err := v.ValidateNeighbourhood(ids)
+	if err != nil {
+		t.Fatalf("validate neighbourhood failed: %v", err)
+	}
+	if summary.Total != 3 || summary.Invalid != 1 {
+		t.Fatalf("expected the changed feature and its neighbours: %+v", summary)
+	}
+	for _, id := range []string{"FTR-0001", "FTR-0002", "FTR-0003"} {
+		if _, ok := summary.Results[id]; !ok {
+			t.Fatalf("expected %s to be validated", id)
+		}
+	}
+	if res := summary.Results["FTR-0002"]; len(res.Errors) != 1 || !strings.Contains(res.Errors[0], "dependency FTR-0404 not found") {
+		t.Fatalf("expected a missing dependency, got %v", res.Errors)
+	}
+
+	if summary, _ = v.ValidateNeighbourhood([]string{"FTR-0005"}); summary.Total != 2 || len(summary.Results["FTR-0005"].Errors) != 2 {
+		t.Fatalf("expected the duplicate and its dependent: %+v", summary)
+	}
+	if summary, _ = v.ValidateNeighbourhood([]string{"FTR-0100"}); summary.Invalid != 2 {
+		t.Fatalf("expected both cycle members to fail: %+v", summary)
+	}
+	if summary, _ = v.ValidateNeighbourhood(nil); summary.Total != 0 {
+		t.Fatalf("expected nothing to validate: %+v", summary)
+	}
+
+	fix.WriteFile(t, "features/backlog/broken.md", []byte("not frontmatter"))
+	if _, err := v.IDsAt(nil); err == nil {
+		t.Fatalf("expected list error")
+	}
+	if _, err := v.ValidateNeighbourhood(nil); err == nil {
+		t.Fatalf("expected list error")
+	}
+}
@@ -0,0 +1,124 @@
+// Package vcs reads change information from the local git repository.
+package vcs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotRepository indicates the directory is not inside a git work tree.
+	ErrNotRepository = errors.New("not a git repository")
+	// ErrUnknownRef indicates the ref does not name a commit.
+	ErrUnknownRef = errors.New("unknown git ref")
+)
+
+// gitBinary is the git executable; tests may point it elsewhere.
+var gitBinary = "git"
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command(gitBinary, args...) // #nosec G204 -- arguments are fixed subcommands and a ref checked by rev-parse
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// ChangedFiles returns the absolute paths of files added, modified or renamed
+// since ref in the repository containing dir. Uncommitted changes, staged or
+// not, and untracked files that are not ignored count as changes. Renamed files
+// are reported under their new name.
+func ChangedFiles(dir, ref string) ([]string, error) {
+	root, ref, err := resolve(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := git(root, "diff", "--name-only", "-z", "--find-renames", "--diff-filter=AMR", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(root, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var paths []string
+	for _, name := range strings.Split(string(diff)+string(untracked), "\x00") {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		paths = append(paths, filepath.Join(root, filepath.FromSlash(name)))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// DeletedFile is a file deleted since a ref.
+type DeletedFile struct {
+	// Path is the absolute path the file had.
+	Path string
+	// Content is the file as it was at the ref.
+	Content []byte
+}
+
+// DeletedFiles returns the files deleted since ref in the repository containing
+// dir, committed or not, with their content at ref, sorted by path. Renamed files
+// are not deleted.
+func DeletedFiles(dir, ref string) ([]DeletedFile, error) {
+	root, ref, err := resolve(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := git(root, "diff", "--name-only", "-z", "--find-renames", "--diff-filter=D", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	names := strings.Split(string(diff), "\x00")
+	sort.Strings(names)
+	var files []DeletedFile
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		content, err := git(root, "show", ref+":"+name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, DeletedFile{Path: filepath.Join(root, filepath.FromSlash(name)), Content: content})
+	}
+	return files, nil
+}
+
+// resolve returns the top level of the repository containing dir and the
+// trimmed ref, checked to name a commit.
+func resolve(dir, ref string) (string, string, error) {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrNotRepository, err)
+	}
+	root := strings.TrimSpace(string(top))
+
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", "", fmt.Errorf("%w: %q", ErrUnknownRef, ref)
+	}
+	if _, err := git(root, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return "", "", fmt.Errorf("%w: %q", ErrUnknownRef, ref)
+	}
+	return root, ref, nil
+}
@@ -0,0 +1,156 @@
+package vcs
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with one commit and returns its resolved path.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("resolve temp dir: %v", err)
+	}
+	run(t, dir, "init", "-q")
+	write(t, dir, "keep.md", "keep\n")
+	write(t, dir, "edit.md", "before\n")
+	write(t, dir, "old.md", "a file that is renamed\nwith enough content\nto be detected\n")
+	write(t, dir, "gone.md", "deleted\n")
+	write(t, dir, ".gitignore", "*.tmp\n")
+	run(t, dir, "add", "-A")
+	run(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := initRepo(t)
+
+	write(t, dir, "sub/committed.md", "new\n")
+	run(t, dir, "add", "-A")
+	run(t, dir, "commit", "-q", "-m", "second")
+
+	write(t, dir, "edit.md", "after\n")
+	run(t, dir, "mv", "old.md", "new.md")
+	run(t, dir, "rm", "-q", "gone.md")
+	write(t, dir, "untracked.md", "untracked\n")
+	write(t, dir, "ignored.tmp", "ignored\n")
+
+	paths, err := ChangedFiles(filepath.Join(dir, "sub"), "HEAD~1")
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	var names []string
+	for _, p := range paths {
+		rel, _ := filepath.Rel(dir, p)
+		names = append(names, filepath.ToSlash(rel))
+	}
+	if got := strings.Join(names, ","); got != "edit.md,new.md,sub/committed.md,untracked.md" {
+		t.Fatalf("unexpected changed files %s", got)
+	}
+
+	paths, err = ChangedFiles(dir, "HEAD")
+	if err != nil || len(paths) != 3 {
+		t.Fatalf("expected the uncommitted changes only, got %v (%v)", paths, err)
+	}
+}
+
+func TestDeletedFiles(t *testing.T) {
+	dir := initRepo(t)
+
+	write(t, dir, "sub/committed.md", "committed\n")
+	run(t, dir, "add", "-A")
+	run(t, dir, "commit", "-q", "-m", "second")
+	run(t, dir, "rm", "-q", "sub/committed.md")
+	run(t, dir, "commit", "-q", "-m", "third")
+
+	run(t, dir, "mv", "old.md", "new.md")
+	run(t, dir, "rm", "-q", "gone.md")
+	if err := os.Remove(filepath.Join(dir, "edit.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := DeletedFiles(dir, "HEAD~1")
+	if err != nil {
+		t.Fatalf("DeletedFiles failed: %v", err)
+	}
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(dir, f.Path)
+		got = append(got, filepath.ToSlash(rel)+"="+strings.TrimSpace(string(f.Content)))
+	}
+	if strings.Join(got, ",") != "edit.md=before,gone.md=deleted,sub/committed.md=committed" {
+		t.Fatalf("unexpected deleted files %v", got)
+	}
+
+	if _, err := DeletedFiles(dir, "no-such-branch"); !errors.Is(err, ErrUnknownRef) {
+		t.Fatalf("expected ErrUnknownRef, got %v", err)
+	}
+	if _, err := DeletedFiles(t.TempDir(), "HEAD"); !errors.Is(err, ErrNotRepository) {
+		t.Fatalf("expected ErrNotRepository, got %v", err)
+	}
+}
+
+func TestChangedFilesErrors(t *testing.T) {
+	if _, err := ChangedFiles(t.TempDir(), "HEAD"); !errors.Is(err, ErrNotRepository) {
+		t.Fatalf("expected ErrNotRepository, got %v", err)
+	}
+
+	dir := initRepo(t)
+	for _, ref := range []string{"", "  ", "--output=x", "no-such-branch"} {
+		if _, err := ChangedFiles(dir, ref); !errors.Is(err, ErrUnknownRef) {
+			t.Fatalf("%q: expected ErrUnknownRef, got %v", ref, err)
+		}
+	}
+}
+
+func TestChangedFilesGitFailures(t *testing.T) {
+	original := gitBinary
+	t.Cleanup(func() { gitBinary = original })
+
+	gitBinary = filepath.Join(t.TempDir(), "missing-git")
+	if _, err := ChangedFiles(t.TempDir(), "HEAD"); !errors.Is(err, ErrNotRepository) || !strings.Contains(err.Error(), "git rev-parse") {
+		t.Fatalf("expected a wrapped exec error, got %v", err)
+	}
+
+	for _, failing := range []string{"diff", "ls-files"} {
+		script := filepath.Join(t.TempDir(), "git")
+		body := "#!/bin/sh\nif [ \"$1\" = " + failing + " ]; then echo \"fatal: " + failing + " broke\" >&2; exit 1; fi\n" +
+			"if [ \"$2\" = --show-toplevel ]; then pwd; fi\n"
+		if err := os.WriteFile(script, []byte(body), 0o700); err != nil { // #nosec G306 -- test script must be executable
+			t.Fatalf("write script: %v", err)
+		}
+		gitBinary = script
+		if _, err := ChangedFiles(t.TempDir(), "HEAD"); err == nil || !strings.Contains(err.Error(), "git "+failing+": fatal: "+failing+" broke") {
+			t.Fatalf("%s: expected failure, got %v", failing, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the workspace-relative name of the workflow definition.
+const FileName = "workflow.yaml"
+
+// Status describes a single workflow state.
+type Status struct {
+	Name string `yaml:"name" json:"name"`
+	// Directory is relative to the workspace root; defaults to features/<name>.
+	Directory   string   `yaml:"directory,omitempty" json:"directory"`
+	Transitions []string `yaml:"transitions" json:"transitions"`
+	// RequiresDoneDependencies blocks entering this status until every dependency is done.
+	RequiresDoneDependencies bool `yaml:"requires_done_dependencies,omitempty" json:"requires_done_dependencies,omitempty"`
+}
+
+// Workflow is the set of statuses a feature can be in and the moves between them.
+type Workflow struct {
+	// Initial is the status assigned to newly created features.
+	Initial string `yaml:"initial" json:"initial"`
+	// Done is the status that counts as finished for dependency checks.
+	Done     string   `yaml:"done" json:"done"`
+	Statuses []Status `yaml:"statuses" json:"statuses"`
+
+	byName map[string]*Status
+}
+
+// Default returns the built-in backlog → in-progress → review → done workflow.
+func Default() *Workflow {
+	wf := &Workflow{
+		Initial: "backlog",
+		Done:    "done",
+		Statuses: []Status{
+			{Name: "backlog", Transitions: []string{"in-progress"}},
+			{Name: "in-progress", Transitions: []string{"blocked", "review"}, RequiresDoneDependencies: true},
+			{Name: "blocked", Transitions: []string{"in-progress"}},
+			{Name: "review", Transitions: []string{"in-progress", "done"}},
+			{Name: "done", Transitions: []string{}},
+		},
+	}
+	if err := wf.normalize(); err != nil {
+		panic(err) // the built-in workflow is always valid
+	}
+	return wf
+}
+
+// Load reads workflow.yaml from the workspace root, falling back to Default when absent.
+func Load(root string) (*Workflow, error) {
+	path := filepath.Join(root, FileName)
+	// #nosec G304 -- workflow path is derived from the validated workspace root
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Default(), nil
+		}
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a workflow definition.
+func Parse(data []byte) (*Workflow, error) {
+	var wf Workflow
+	if err := yaml.Unmarshal(data, &wf); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	if err := wf.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	return &wf, nil
+}
+
+// normalize lower-cases names, fills in default directories and checks references.
+func (w *Workflow) normalize() error {
+	if len(w.Statuses) == 0 {
+		return errors.New("at least one status is required")
+	}
+	w.byName = make(map[string]*Status, len(w.Statuses))
+	dirs := map[string]string{}
+	for i := range w.Statuses {
+		st := &w.Statuses[i]
+		st.Name = strings.ToLower(strings.TrimSpace(st.Name))
+		if st.Name == "" {
+			return fmt.Errorf("status %d has no name", i+1)
+		}
+		if _, dup := w.byName[st.Name]; dup {
+			return fmt.Errorf("duplicate status %q", st.Name)
+		}
+		if st.Directory == "" {
+			st.Directory = "features/" + st.Name
+		}
+		st.Directory = filepath.ToSlash(filepath.Clean(st.Directory))
+		if !filepath.IsLocal(st.Directory) || !strings.HasPrefix(st.Directory, "features/") {
+			return fmt.Errorf("status %q directory %q must be inside features/", st.Name, st.Directory)
+		}
+		if other, dup := dirs[st.Directory]; dup {
+			return fmt.Errorf("statuses %q and %q share directory %s", other, st.Name, st.Directory)
+		}
+		dirs[st.Directory] = st.Name
+		if st.Transitions == nil {
+			st.Transitions = []string{}
+		}
+		w.byName[st.Name] = st
+	}
+	for i := range w.Statuses {
+		st := &w.Statuses[i]
+		for j, next := range st.Transitions {
+			next = strings.ToLower(strings.TrimSpace(next))
+			if _, ok := w.byName[next]; !ok {
+				return fmt.Errorf("status %q transitions to unknown status %q", st.Name, next)
+			}
+			st.Transitions[j] = next
+		}
+	}
+
+	w.Initial = strings.ToLower(strings.TrimSpace(w.Initial))
+	if w.Initial == "" {
+		w.Initial = w.Statuses[0].Name
+	}
+	if _, ok := w.byName[w.Initial]; !ok {
+		return fmt.Errorf("initial status %q is not defined", w.Initial)
+	}
+	w.Done = strings.ToLower(strings.TrimSpace(w.Done))
+	if w.Done == "" {
+		w.Done = w.Statuses[len(w.Statuses)-1].Name
+	}
+	if _, ok := w.byName[w.Done]; !ok {
+		return fmt.Errorf("done status %q is not defined", w.Done)
+	}
+	return nil
+}
+
+func (w *Workflow) status(name string) *Status {
+	return w.byName[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// DirectoryForStatus returns the workspace-relative directory for a status, or "" if unknown.
+func (w *Workflow) DirectoryForStatus(status string) string {
+	if st := w.status(status); st != nil {
+		return st.Directory
+	}
+	return ""
+}
+
+// StatusForDirectory returns the status stored in the given workspace-relative directory.
+func (w *Workflow) StatusForDirectory(dir string) (string, bool) {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	for _, st := range w.Statuses {
+		if strings.EqualFold(st.Directory, dir) {
+			return st.Name, true
+		}
+	}
+	return "", false
+}
+
+// Names returns status names in declaration order.
+func (w *Workflow) Names() []string {
+	names := make([]string, len(w.Statuses))
+	for i, st := range w.Statuses {
+		names[i] = st.Name
+	}
+	return names
+}
+
+// ValidStatuses returns the sorted list of allowed statuses.
+func (w *Workflow) ValidStatuses() []string {
+	names := w.Names()
+	sort.Strings(names)
+	return names
+}
+
+// ValidateStatus ensures status exists.
+func (w *Workflow) ValidateStatus(status string) error {
+	if w.status(status) == nil {
+		return fmt.Errorf("invalid status '%s'", strings.ToLower(status))
+	}
+	return nil
+}
+
+// ValidateTransition ensures the transition is permitted.
+func (w *Workflow) ValidateTransition(current, target string) error {
+	current = strings.ToLower(current)
+	target = strings.ToLower(target)
+
+	if err := w.ValidateStatus(target); err != nil {
+		return err
+	}
+
+	st := w.status(current)
+	if st == nil {
+		return fmt.Errorf("status '%s' cannot transition", current)
+	}
+
+	for _, next := range st.Transitions {
+		if next == target {
+			return nil
+		}
+	}
+
+	if len(st.Transitions) == 0 {
+		return fmt.Errorf("cannot transition from %s", current)
+	}
+	return fmt.Errorf("cannot transition from %s to %s", current, target)
+}
+
+// IsDone reports whether the status counts as finished.
+func (w *Workflow) IsDone(status string) bool {
+	return strings.EqualFold(strings.TrimSpace(status), w.Done)
+}
+
+// RequiresDoneDependencies reports whether entering the status requires finished dependencies.
+func (w *Workflow) RequiresDoneDependencies(status string) bool {
+	st := w.status(status)
+	return st != nil && st.RequiresDoneDependencies
+}
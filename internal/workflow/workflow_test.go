@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultWorkflow(t *testing.T) {
+	wf := Default()
+	if wf.Initial != "backlog" || wf.Done != "done" {
+		t.Fatalf("unexpected defaults: %+v", wf)
+	}
+	if wf.DirectoryForStatus("In-Progress") != "features/in-progress" {
+		t.Fatalf("unexpected directory for in-progress")
+	}
+	if wf.DirectoryForStatus("unknown") != "" {
+		t.Fatalf("expected empty directory for unknown status")
+	}
+	if got := strings.Join(wf.Names(), ","); got != "backlog,in-progress,blocked,review,done" {
+		t.Fatalf("unexpected declared order: %s", got)
+	}
+	if got := strings.Join(wf.ValidStatuses(), ","); got != "backlog,blocked,done,in-progress,review" {
+		t.Fatalf("unexpected sorted statuses: %s", got)
+	}
+	if !wf.RequiresDoneDependencies("in-progress") || wf.RequiresDoneDependencies("review") || wf.RequiresDoneDependencies("nope") {
+		t.Fatalf("unexpected dependency gating")
+	}
+	if !wf.IsDone(" DONE ") || wf.IsDone("review") {
+		t.Fatalf("unexpected done detection")
+	}
+	if status, ok := wf.StatusForDirectory("features/review/"); !ok || status != "review" {
+		t.Fatalf("expected review for its directory, got %q %v", status, ok)
+	}
+	if _, ok := wf.StatusForDirectory("features"); ok {
+		t.Fatalf("features root is not a status directory")
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	wf := Default()
+	if err := wf.ValidateTransition("backlog", "in-progress"); err != nil {
+		t.Fatalf("expected transition to succeed: %v", err)
+	}
+	if err := wf.ValidateTransition("backlog", "done"); err == nil || !strings.Contains(err.Error(), "from backlog to done") {
+		t.Fatalf("expected disallowed transition error, got %v", err)
+	}
+	if err := wf.ValidateTransition("done", "backlog"); err == nil || err.Error() != "cannot transition from done" {
+		t.Fatalf("expected terminal status error, got %v", err)
+	}
+	if err := wf.ValidateTransition("unknown", "backlog"); err == nil {
+		t.Fatalf("expected error for unknown current status")
+	}
+	if err := wf.ValidateTransition("backlog", "nope"); err == nil {
+		t.Fatalf("expected error for unknown target status")
+	}
+}
+
+func TestParseCustomWorkflow(t *testing.T) {
+	wf, err := Parse([]byte(`
+statuses:
+  - name: Todo
+    transitions: [doing, cancelled]
+  - name: doing
+    transitions: [qa, cancelled]
+    requires_done_dependencies: true
+  - name: qa
+    directory: features/quality-assurance
+    transitions: [doing, shipped]
+  - name: shipped
+  - name: cancelled
+    directory: features/archive/cancelled/
+done: shipped
+`))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if wf.Initial != "todo" {
+		t.Fatalf("expected first status as initial, got %s", wf.Initial)
+	}
+	if wf.DirectoryForStatus("qa") != "features/quality-assurance" || wf.DirectoryForStatus("cancelled") != "features/archive/cancelled" {
+		t.Fatalf("unexpected custom directories")
+	}
+	if err := wf.ValidateTransition("doing", "qa"); err != nil {
+		t.Fatalf("expected qa transition: %v", err)
+	}
+	if err := wf.ValidateTransition("shipped", "todo"); err == nil {
+		t.Fatalf("expected shipped to be terminal")
+	}
+	if !wf.IsDone("shipped") || !wf.RequiresDoneDependencies("doing") {
+		t.Fatalf("unexpected done/dependency configuration")
+	}
+
+	last, err := Parse([]byte("statuses:\n  - name: open\n    transitions: [closed]\n  - name: closed\n"))
+	if err != nil || last.Done != "closed" {
+		t.Fatalf("expected last status to default as done: %v %+v", err, last)
+	}
+}
+
+func TestParseInvalidWorkflow(t *testing.T) {
+	cases := map[string]string{
+		"syntax":             "statuses: [",
+		"empty":              "initial: backlog\n",
+		"unnamed":            "statuses:\n  - directory: features/x\n",
+		"duplicate":          "statuses:\n  - name: a\n  - name: A\n",
+		"escape":             "statuses:\n  - name: a\n    directory: ../outside\n",
+		"outside features":   "statuses:\n  - name: a\n    directory: specs/a\n",
+		"shared directory":   "statuses:\n  - name: a\n  - name: b\n    directory: features/a\n",
+		"unknown transition": "statuses:\n  - name: a\n    transitions: [b]\n",
+		"unknown initial":    "initial: z\nstatuses:\n  - name: a\n",
+		"unknown done":       "done: z\nstatuses:\n  - name: a\n",
+	}
+	for name, data := range cases {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	wf, err := Load(dir)
+	if err != nil || wf.Initial != "backlog" {
+		t.Fatalf("expected default workflow when file missing: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("statuses:\n  - name: open\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wf, err = Load(dir)
+	if err != nil || wf.Initial != "open" || wf.Done != "open" {
+		t.Fatalf("expected custom workflow: %v %+v", err, wf)
+	}
+
+	if err := os.Remove(filepath.Join(dir, FileName)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, FileName), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Fatalf("expected read error when workflow path is a directory")
+	}
+}